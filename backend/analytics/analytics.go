@@ -0,0 +1,143 @@
+// Package analytics counts page views without storing anything that
+// identifies a visitor.
+//
+// A view is reduced to (kind, item, day, referrer host) and two counters.
+// Unique visitors are recognised by a hash of IP and user agent salted with
+// a random value that changes every day and is never written to disk, so
+// the hashes cannot be linked across days or reversed later. Counts are
+// buffered (in Redis when usable, otherwise in memory) and flushed to the
+// database in batches, keeping writes off the request path.
+package analytics
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/config"
+	"backend/logger"
+	"backend/models"
+)
+
+// Content kinds that can be tracked.
+const (
+	KindBlog     = "blog"
+	KindSolution = "solution"
+)
+
+// Hit is one page view reported by the tracking beacon.
+type Hit struct {
+	Kind   string
+	ItemID int
+	// Referrer is the external referrer host, "" for direct or internal
+	// traffic (see ReferrerHost).
+	Referrer  string
+	IP        string
+	UserAgent string
+	Time      time.Time
+}
+
+// Store adds flushed counts to the stored totals.
+type Store func(ctx context.Context, counts []models.ViewCount) error
+
+// Day is the UTC calendar day views are counted under.
+func Day(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+type countKey struct {
+	Kind     string
+	ItemID   int
+	Day      string
+	Referrer string
+}
+
+type counters struct {
+	Views    int
+	Visitors int
+}
+
+var (
+	memory  = &memoryBuffer{}
+	flushMu sync.Mutex
+)
+
+// Record buffers h. While Redis is usable the counts and the set of seen
+// visitors live there, shared by every instance; otherwise (or if the Redis
+// write fails) they are kept in this process.
+func Record(ctx context.Context, h Hit) {
+	key := countKey{Kind: h.Kind, ItemID: h.ItemID, Day: Day(h.Time), Referrer: h.Referrer}
+	if config.Redis != nil && config.RedisUsable() {
+		err := redisAdd(ctx, config.Redis, key, h)
+		config.ReportRedisResult(err)
+		if err == nil {
+			return
+		}
+		logger.FromContext(ctx).Warn("analytics redis buffer failed; buffering in memory", "error", err)
+	}
+	memory.add(key, h)
+}
+
+// Flush moves every buffered count into store. Counts the store rejects go
+// back to the memory buffer and are retried on the next flush.
+func Flush(ctx context.Context, store Store) error {
+	flushMu.Lock()
+	defer flushMu.Unlock()
+
+	counts := memory.drain()
+	if config.Redis != nil && config.RedisUsable() {
+		pending, err := redisDrain(ctx, config.Redis)
+		config.ReportRedisResult(err)
+		if err != nil {
+			logger.FromContext(ctx).Warn("analytics redis drain failed; retrying on next flush", "error", err)
+		}
+		for k, c := range pending {
+			merged := counts[k]
+			merged.Views += c.Views
+			merged.Visitors += c.Visitors
+			counts[k] = merged
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	list := make([]models.ViewCount, 0, len(counts))
+	for k, c := range counts {
+		list = append(list, models.ViewCount{
+			Kind:     k.Kind,
+			ItemID:   k.ItemID,
+			Day:      k.Day,
+			Referrer: k.Referrer,
+			Views:    c.Views,
+			Visitors: c.Visitors,
+		})
+	}
+	if err := store(ctx, list); err != nil {
+		memory.restore(counts)
+		return err
+	}
+	return nil
+}
+
+// visitorHash identifies one visitor of one item for the day salt belongs
+// to.
+func visitorHash(salt []byte, key countKey, h Hit) string {
+	sum := sha256.New()
+	sum.Write(salt)
+	for _, part := range []string{key.Kind, strconv.Itoa(key.ItemID), h.IP, h.UserAgent} {
+		sum.Write([]byte{0})
+		sum.Write([]byte(part))
+	}
+	return hex.EncodeToString(sum.Sum(nil)[:16])
+}
+
+func newSalt() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+}
@@ -0,0 +1,167 @@
+package analytics
+
+import (
+	"context"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// memoryBuffer holds counts while Redis is not in use. Seen visitors are
+// only remembered for the current day; the salt is replaced with them.
+type memoryBuffer struct {
+	mu     sync.Mutex
+	counts map[countKey]counters
+	day    string
+	salt   []byte
+	seen   map[string]bool
+}
+
+func (b *memoryBuffer) add(key countKey, h Hit) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.counts == nil {
+		b.counts = map[countKey]counters{}
+	}
+	if b.day != key.Day {
+		b.day, b.salt, b.seen = key.Day, newSalt(), map[string]bool{}
+	}
+
+	c := b.counts[key]
+	c.Views++
+	if visitor := visitorHash(b.salt, key, h); !b.seen[visitor] {
+		b.seen[visitor] = true
+		c.Visitors++
+	}
+	b.counts[key] = c
+}
+
+// drain returns and clears the buffered counts.
+func (b *memoryBuffer) drain() map[countKey]counters {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	counts := b.counts
+	b.counts = map[countKey]counters{}
+	if counts == nil {
+		counts = map[countKey]counters{}
+	}
+	return counts
+}
+
+// restore puts counts that could not be stored back into the buffer.
+func (b *memoryBuffer) restore(counts map[countKey]counters) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.counts == nil {
+		b.counts = map[countKey]counters{}
+	}
+	for k, c := range counts {
+		merged := b.counts[k]
+		merged.Views += c.Views
+		merged.Visitors += c.Visitors
+		b.counts[k] = merged
+	}
+}
+
+// Redis keys. Hash fields are "v|" (views) or "u|"
+// (visitors) followed by kind|item|day|referrer; hosts never contain "|".
+const (
+	pendingKey    = "analytics:{pending}"
+	saltKeyPrefix = "analytics:salt:"
+	seenKeyPrefix = "analytics:seen:"
+	// dayTTL outlives the day a salt or seen marker belongs to, whatever
+	// the time zone of the instance that set it.
+	dayTTL = 48 * time.Hour
+)
+
+// redisSalt caches the shared salt of the current day, so only the first
+// hit of a day per instance needs the extra round trips.
+var redisSalt struct {
+	mu   sync.Mutex
+	day  string
+	salt []byte
+}
+
+// dailySalt returns the salt for day, creating it in Redis if this is the
+// first instance to need it.
+func dailySalt(ctx context.Context, client redis.UniversalClient, day string) ([]byte, error) {
+	redisSalt.mu.Lock()
+	defer redisSalt.mu.Unlock()
+	if redisSalt.day == day {
+		return redisSalt.salt, nil
+	}
+
+	key := saltKeyPrefix + day
+	if err := client.SetNX(ctx, key, hex.EncodeToString(newSalt()), dayTTL).Err(); err != nil {
+		return nil, err
+	}
+	salt, err := client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	redisSalt.day, redisSalt.salt = day, salt
+	return salt, nil
+}
+
+func redisAdd(ctx context.Context, client redis.UniversalClient, key countKey, h Hit) error {
+	salt, err := dailySalt(ctx, client, key.Day)
+	if err != nil {
+		return err
+	}
+	seen := seenKeyPrefix + key.Day + ":" + visitorHash(salt, key, h)
+	first, err := client.SetNX(ctx, seen, 1, dayTTL).Result()
+	if err != nil {
+		return err
+	}
+
+	field := strings.Join([]string{key.Kind, strconv.Itoa(key.ItemID), key.Day, key.Referrer}, "|")
+	pipe := client.Pipeline()
+	pipe.HIncrBy(ctx, pendingKey, "v|"+field, 1)
+	if first {
+		pipe.HIncrBy(ctx, pendingKey, "u|"+field, 1)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// redisDrain reads and deletes the pending hash in one MULTI/EXEC, so hits
+// that arrive meanwhile start a new one, no two instances flush the same
+// counts, and a failed read leaves the counts in place for the next flush.
+func redisDrain(ctx context.Context, client redis.UniversalClient) (map[countKey]counters, error) {
+	var read *redis.MapStringStringCmd
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		read = pipe.HGetAll(ctx, pendingKey)
+		pipe.Del(ctx, pendingKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	fields := read.Val()
+
+	counts := map[countKey]counters{}
+	for field, value := range fields {
+		parts := strings.SplitN(field, "|", 5)
+		if len(parts) != 5 {
+			continue
+		}
+		id, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+		n, _ := strconv.Atoi(value)
+		key := countKey{Kind: parts[1], ItemID: id, Day: parts[3], Referrer: parts[4]}
+		c := counts[key]
+		if parts[0] == "u" {
+			c.Visitors += n
+		} else {
+			c.Views += n
+		}
+		counts[key] = c
+	}
+	return counts, nil
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// botPattern matches crawlers, link previewers, uptime monitors, headless
+// browsers and HTTP libraries.
+var botPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|archiver|facebookexternalhit|embedly|preview|` +
+	`headless|phantomjs|lighthouse|pingdom|uptime|monitor|curl|wget|python|go-http-client|java/|okhttp|axios|` +
+	`node-fetch|libwww|httpclient|scrapy`)
+
+// IsBot reports whether userAgent should not be counted. Every mainstream
+// browser sends a "Mozilla/" token, so anything without one (including an
+// empty header) is treated as a script.
+func IsBot(userAgent string) bool {
+	return !strings.Contains(userAgent, "Mozilla/") || botPattern.MatchString(userAgent)
+}
+
+// maxHostLength is the longest DNS name.
+const maxHostLength = 253
+
+// ReferrerHost reduces a referrer URL to its lower-case host without port
+// or "www.". Referrers that are not http(s) URLs, or point at one of the
+// site's own hosts, return "" and count as direct traffic.
+func ReferrerHost(referrer string, ownHosts ...string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := normalizeHost(u.Host)
+	if host == "" || len(host) > maxHostLength {
+		return ""
+	}
+	for _, own := range ownHosts {
+		if own != "" && normalizeHost(own) == host {
+			return ""
+		}
+	}
+	return host
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"backend/middleware"
+)
+
+var (
+	workerMu     sync.Mutex
+	workerCancel context.CancelFunc
+	workerDone   chan struct{}
+
+	// finalFlushTimeout bounds the flush on StopWorker, including the wait
+	// for the DB read lock.
+	finalFlushTimeout = 5 * time.Second
+)
+
+// StartWorker flushes buffered counts into store every interval until
+// StopWorker is called.
+func StartWorker(store Store, interval time.Duration) {
+	workerMu.Lock()
+	defer workerMu.Unlock()
+	if workerCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workerCancel = cancel
+	workerDone = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// Final flush so a clean shutdown loses nothing buffered in
+				// memory; Redis keeps its counts for the next instance anyway.
+				// If a restore keeps the database locked past the timeout, the
+				// memory buffer is dropped rather than holding up shutdown.
+				flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+				if middleware.LockDBReadContext(flushCtx) {
+					flush(flushCtx, store)
+					middleware.UnlockDBRead()
+				} else {
+					slog.Warn("analytics final flush skipped: database locked by a restore; buffered counts dropped")
+				}
+				cancel()
+				return
+			case <-ticker.C:
+				// The DB read lock keeps a restore from swapping the database
+				// during the write; while one runs, the counts stay buffered
+				// until the next tick.
+				if !middleware.TryLockDBRead() {
+					continue
+				}
+				flush(ctx, store)
+				middleware.UnlockDBRead()
+			}
+		}
+	}(workerDone)
+}
+
+// StopWorker stops the worker after a last flush.
+func StopWorker() {
+	workerMu.Lock()
+	cancel, done := workerCancel, workerDone
+	workerCancel, workerDone = nil, nil
+	workerMu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func flush(ctx context.Context, store Store) {
+	if err := Flush(ctx, store); err != nil {
+		slog.Warn("analytics flush failed; counts kept for the next attempt", "error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"backend/middleware"
+	"backend/models"
+)
+
+func TestStopWorkerDuringRestore(t *testing.T) {
+	defer func(d time.Duration) { finalFlushTimeout = d }(finalFlushTimeout)
+	finalFlushTimeout = 100 * time.Millisecond
+
+	var stored atomic.Int32
+	store := func(ctx context.Context, counts []models.ViewCount) error {
+		stored.Add(1)
+		return nil
+	}
+	Record(context.Background(), Hit{Kind: KindBlog, ItemID: 1, IP: "192.0.2.1", Time: time.Now()})
+	t.Cleanup(func() { memory.drain() })
+
+	// A restore holds the write lock for its whole duration.
+	middleware.LockDBWrite()
+	defer middleware.UnlockDBWrite()
+
+	StartWorker(store, time.Hour)
+	stopped := make(chan struct{})
+	go func() {
+		StopWorker()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StopWorker blocked while the database was locked")
+	}
+	if n := stored.Load(); n != 0 {
+		t.Errorf("store called %d times while the database was locked", n)
+	}
+}
@@ -0,0 +1,238 @@
+package antispam
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons a contact submission can be rejected outright.
+const (
+	ReasonRateLimited  = "rate_limited"
+	ReasonHoneypot     = "honeypot"
+	ReasonInvalidToken = "invalid_token"
+	ReasonTooFast      = "too_fast"
+	ReasonInvalidInput = "invalid_input"
+)
+
+// Field length limits for the public contact form.
+const (
+	MaxNameLen    = 100
+	MaxEmailLen   = 254
+	MaxSubjectLen = 200
+	MaxMessageLen = 5000
+)
+
+var (
+	ErrTokenInvalid = errors.New("invalid form token")
+	ErrTokenExpired = errors.New("form token expired")
+	ErrTooFast      = errors.New("form submitted too quickly")
+)
+
+// Config holds the anti-spam settings for the contact form.
+type Config struct {
+	MinFillTime  time.Duration
+	TokenMaxAge  time.Duration
+	MaxLinks     int
+	SpamKeywords []string
+	Secret       []byte
+}
+
+var (
+	cfgMu sync.RWMutex
+	cfg   = Config{
+		MinFillTime: 3 * time.Second,
+		TokenMaxAge: 2 * time.Hour,
+		MaxLinks:    2,
+		Secret:      randomSecret(),
+	}
+)
+
+// Configure replaces the anti-spam settings. Keywords are matched
+// case-insensitively; an empty Secret keeps a random per-process key, so
+// tokens issued before a restart become invalid.
+func Configure(c Config) {
+	keywords := make([]string, 0, len(c.SpamKeywords))
+	for _, kw := range c.SpamKeywords {
+		if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+			keywords = append(keywords, kw)
+		}
+	}
+	c.SpamKeywords = keywords
+	if len(c.Secret) == 0 {
+		c.Secret = randomSecret()
+	}
+
+	cfgMu.Lock()
+	cfg = c
+	cfgMu.Unlock()
+}
+
+// Settings returns the current anti-spam configuration.
+func Settings() Config {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
+	return cfg
+}
+
+func randomSecret() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// IssueToken returns a signed token that records when the form was rendered.
+func IssueToken(now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	return ts + "." + sign(ts)
+}
+
+// VerifyToken checks the token signature and that the form was open for at
+// least MinFillTime and no longer than TokenMaxAge.
+func VerifyToken(token string, now time.Time) error {
+	s := Settings()
+
+	ts, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(sign(ts))) {
+		return ErrTokenInvalid
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+
+	elapsed := now.Sub(time.Unix(unix, 0))
+	if elapsed < s.MinFillTime {
+		return ErrTooFast
+	}
+	if s.TokenMaxAge > 0 && elapsed > s.TokenMaxAge {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, Settings().Secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// FieldError reports which contact field failed validation. Rule follows the
+// validator tag names ("required", "max", "email") so the API can render it
+// like any other validation error.
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+func (e *FieldError) Error() string {
+	switch e.Rule {
+	case "required":
+		return e.Field + " is required"
+	case "max":
+		return e.Field + " must be at most " + e.Param + " characters"
+	default:
+		return e.Field + " is not a valid address"
+	}
+}
+
+// ValidateFields checks required fields, lengths and email syntax. Failures
+// are returned as *FieldError.
+func ValidateFields(name, email, subject, message string) error {
+	fields := []struct {
+		label string
+		value string
+		max   int
+	}{
+		{"name", name, MaxNameLen},
+		{"email", email, MaxEmailLen},
+		{"subject", subject, MaxSubjectLen},
+		{"message", message, MaxMessageLen},
+	}
+	for _, f := range fields {
+		if strings.TrimSpace(f.value) == "" {
+			return &FieldError{Field: f.label, Rule: "required"}
+		}
+		if len([]rune(f.value)) > f.max {
+			return &FieldError{Field: f.label, Rule: "max", Param: strconv.Itoa(f.max)}
+		}
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return &FieldError{Field: "email", Rule: "email"}
+	}
+	return nil
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+
+// Classify applies the keyword and link-count heuristics. It returns a
+// non-empty reason when the message looks like spam.
+func Classify(subject, message string) string {
+	s := Settings()
+
+	text := strings.ToLower(subject + "\n" + message)
+	for _, kw := range s.SpamKeywords {
+		if strings.Contains(text, kw) {
+			return "keyword:" + kw
+		}
+	}
+
+	if n := len(linkPattern.FindAllStringIndex(text, -1)); n > s.MaxLinks {
+		return fmt.Sprintf("links:%d", n)
+	}
+	return ""
+}
+
+var (
+	countersMu sync.Mutex
+	rejected   = map[string]*atomic.Int64{}
+	flagged    atomic.Int64
+)
+
+// RecordRejected increments the rejected-submission counter for reason.
+func RecordRejected(reason string) {
+	countersMu.Lock()
+	c, ok := rejected[reason]
+	if !ok {
+		c = &atomic.Int64{}
+		rejected[reason] = c
+	}
+	countersMu.Unlock()
+	c.Add(1)
+}
+
+// RecordFlagged increments the counter of stored submissions flagged as spam.
+func RecordFlagged() {
+	flagged.Add(1)
+}
+
+// Stats is a snapshot of the anti-spam counters since process start.
+type Stats struct {
+	Rejected map[string]int64 `json:"rejected"`
+	Flagged  int64            `json:"flagged"`
+}
+
+// Snapshot returns the current counter values.
+func Snapshot() Stats {
+	countersMu.Lock()
+	defer countersMu.Unlock()
+
+	st := Stats{Rejected: make(map[string]int64, len(rejected)), Flagged: flagged.Load()}
+	for reason, c := range rejected {
+		st.Rejected[reason] = c.Load()
+	}
+	return st
+}
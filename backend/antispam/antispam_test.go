@@ -0,0 +1,98 @@
+package antispam
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configure replaces the settings for one test.
+func configure(t *testing.T, c Config) {
+	t.Helper()
+	prev := Settings()
+	Configure(c)
+	t.Cleanup(func() { Configure(prev) })
+}
+
+func TestVerifyToken(t *testing.T) {
+	configure(t, Config{MinFillTime: 3 * time.Second, TokenMaxAge: time.Hour, Secret: []byte("key")})
+	issued := time.Unix(1700000000, 0)
+	token := IssueToken(issued)
+	ts, sig, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		after time.Duration
+		want  error
+	}{
+		{"valid", token, 10 * time.Second, nil},
+		{"too fast", token, time.Second, ErrTooFast},
+		{"expired", token, 2 * time.Hour, ErrTokenExpired},
+		{"backdated timestamp", "1600000000." + sig, 10 * time.Second, ErrTokenInvalid},
+		{"missing signature", ts, 10 * time.Second, ErrTokenInvalid},
+		{"garbage", "x.y", 10 * time.Second, ErrTokenInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyToken(tt.token, issued.Add(tt.after)); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyToken = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Tokens are bound to the secret.
+	configure(t, Config{MinFillTime: 3 * time.Second, TokenMaxAge: time.Hour, Secret: []byte("other")})
+	if err := VerifyToken(token, issued.Add(10*time.Second)); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("token from another secret: %v, want %v", err, ErrTokenInvalid)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	long := strings.Repeat("字", MaxSubjectLen+1)
+	tests := []struct {
+		name                           string
+		fullName, email, subj, message string
+		field, rule                    string
+	}{
+		{"valid", "Ada", "ada@example.com", "Hi", "Hello", "", ""},
+		{"blank name", "  ", "ada@example.com", "Hi", "Hello", "name", "required"},
+		{"subject too long in runes", "Ada", "ada@example.com", long, "Hello", "subject", "max"},
+		{"bad email", "Ada", "not-an-email", "Hi", "Hello", "email", "email"},
+		{"display name in email", "Ada", "Ada <ada@example.com>", "Hi", "Hello", "email", "email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFields(tt.fullName, tt.email, tt.subj, tt.message)
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("ValidateFields = %v, want nil", err)
+				}
+				return
+			}
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != tt.field || fe.Rule != tt.rule {
+				t.Errorf("ValidateFields = %v, want %s/%s", err, tt.field, tt.rule)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	configure(t, Config{MaxLinks: 2, SpamKeywords: []string{" Casino ", ""}})
+
+	tests := []struct {
+		subject, message, want string
+	}{
+		{"Hi", "Hello there", ""},
+		{"Visit our CASINO", "now", "keyword:casino"},
+		{"Links", "https://a.example and www.b.example", ""},
+		{"Links", "https://a.example http://b.example www.c.example", "links:3"},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.subject, tt.message); got != tt.want {
+			t.Errorf("Classify(%q, %q) = %q, want %q", tt.subject, tt.message, got, tt.want)
+		}
+	}
+}
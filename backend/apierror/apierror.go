@@ -0,0 +1,205 @@
+// Package apierror defines the JSON error envelope returned by the API.
+//
+// Every error response has the shape
+//
+//	{"error": "<localized message>", "code": "<machine code>",
+//	 "details": [{"field": "...", "rule": "...", "param": "...", "message": "..."}],
+//	 "request_id": "..."}
+//
+// "error" stays a human-readable string so existing clients that display
+// it keep working; clients that need to branch on the failure use "code".
+// "details" is only present for validation and conflict errors.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Machine-readable error codes shared by many endpoints. Endpoint-specific
+// codes (for example the contact form token codes) are passed to New.
+const (
+	CodeBadRequest    = "bad_request"
+	CodeInvalidJSON   = "invalid_json"
+	CodeInvalidID     = "invalid_id"
+	CodeValidation    = "validation_failed"
+	CodeUnauthorized  = "unauthorized"
+	CodeNotFound      = "not_found"
+	CodeRouteNotFound = "route_not_found"
+	CodeConflict      = "conflict"
+	CodeRateLimited   = "rate_limited"
+	CodeInternal      = "internal_error"
+)
+
+// Error is an API error. Status and Code are sent to the client; the human
+// message is looked up in the catalog by Key (or Code when Key is empty) in
+// the negotiated language. Err is the underlying cause: it is logged but
+// never sent.
+type Error struct {
+	Status  int
+	Code    string
+	Key     string
+	Args    []string
+	Details []FieldError
+	Err     error
+}
+
+// FieldError describes one invalid request field. Message is filled in when
+// the error is rendered.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Body is the JSON envelope written to the client.
+type Body struct {
+	Error     string       `json:"error"`
+	Code      string       `json:"code"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Code
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Body renders the envelope in lang.
+func (e *Error) Body(lang string) Body {
+	key := e.Key
+	if key == "" {
+		key = e.Code
+	}
+	b := Body{Error: Message(lang, key, e.Args...), Code: e.Code}
+
+	if len(e.Details) > 0 {
+		b.Details = make([]FieldError, len(e.Details))
+		msgs := make([]string, len(e.Details))
+		for i, f := range e.Details {
+			f.Message = fieldMessage(lang, f)
+			b.Details[i] = f
+			msgs[i] = f.Message
+		}
+		if e.Code == CodeValidation {
+			b.Error = Message(lang, "validation_summary", b.Error, strings.Join(msgs, Message(lang, "list_separator")))
+		}
+	}
+	return b
+}
+
+// New returns an error with a custom code; the message key is the code.
+func New(status int, code string, args ...string) *Error {
+	return &Error{Status: status, Code: code, Args: args}
+}
+
+// BadRequest is a 400 with the catalog message key.
+func BadRequest(key string, args ...string) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeBadRequest, Key: key, Args: args}
+}
+
+// InvalidID is a 400 for a malformed :id path parameter.
+func InvalidID() *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeInvalidID}
+}
+
+// Field builds a FieldError; param is the rule argument, e.g. the max length.
+func Field(field, rule string, param ...string) FieldError {
+	f := FieldError{Field: field, Rule: rule}
+	if len(param) > 0 {
+		f.Param = param[0]
+	}
+	return f
+}
+
+// Validation is a 422 listing the invalid fields.
+func Validation(details ...FieldError) *Error {
+	return &Error{Status: http.StatusUnprocessableEntity, Code: CodeValidation, Details: details}
+}
+
+// NotFound is a 404 for the named resource (a catalog term such as "blog").
+func NotFound(resource string) *Error {
+	return &Error{Status: http.StatusNotFound, Code: CodeNotFound, Args: []string{resource}}
+}
+
+// Conflict is a 409 for a uniqueness violation on field.
+func Conflict(resource, field string) *Error {
+	return &Error{
+		Status:  http.StatusConflict,
+		Code:    CodeConflict,
+		Args:    []string{resource, field},
+		Details: []FieldError{Field(field, "unique")},
+	}
+}
+
+// Unauthorized is a 401 with the catalog message key.
+func Unauthorized(key string) *Error {
+	return &Error{Status: http.StatusUnauthorized, Code: CodeUnauthorized, Key: key}
+}
+
+// RateLimited is a 429.
+func RateLimited() *Error {
+	return &Error{Status: http.StatusTooManyRequests, Code: CodeRateLimited}
+}
+
+// Internal is a 500 that hides err from the client.
+func Internal(err error) *Error {
+	return &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Err: err}
+}
+
+// From returns err as an *Error, wrapping anything else as Internal.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err)
+}
+
+// Bind converts an error from gin's ShouldBind* into a 400 for malformed
+// JSON or a 422 with per-field details for validation failures.
+func Bind(err error) *Error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		details := make([]FieldError, len(verrs))
+		for i, fe := range verrs {
+			details[i] = Field(fe.Field(), fe.Tag(), fe.Param())
+		}
+		return Validation(details...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Validation(Field(typeErr.Field, "type", typeErr.Type.String()))
+	}
+
+	return &Error{Status: http.StatusBadRequest, Code: CodeInvalidJSON, Err: err}
+}
+
+func init() {
+	// Report validation errors with JSON field names rather than Go names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package apierror
+
+import (
+	"fmt"
+
+	"backend/i18n"
+)
+
+// messages maps a message key to its translation per locale. Templates use
+// indexed verbs (%[1]s) so a translation may reorder or omit arguments.
+var messages = map[string]map[string]string{
+	// envelope
+	CodeBadRequest:    {i18n.English: "Bad request", i18n.Chinese: "请求无效"},
+	CodeInvalidJSON:   {i18n.English: "Request body is not valid JSON", i18n.Chinese: "请求体不是有效的 JSON"},
+	CodeInvalidID:     {i18n.English: "Invalid ID", i18n.Chinese: "无效的ID"},
+	CodeValidation:    {i18n.English: "Validation failed", i18n.Chinese: "参数校验失败"},
+	CodeUnauthorized:  {i18n.English: "Unauthorized", i18n.Chinese: "未授权"},
+	CodeNotFound:      {i18n.English: "%[1]s not found", i18n.Chinese: "%[1]s不存在"},
+	CodeRouteNotFound: {i18n.English: "Route not found", i18n.Chinese: "接口不存在"},
+	CodeConflict:      {i18n.English: "A %[1]s with this %[2]s already exists", i18n.Chinese: "%[1]s的 %[2]s 已存在"},
+	CodeRateLimited:   {i18n.English: "Too many requests, please try again later", i18n.Chinese: "请求过于频繁，请稍后再试"},
+	CodeInternal:      {i18n.English: "Internal server error", i18n.Chinese: "服务器内部错误"},
+
+	"validation_summary": {i18n.English: "%[1]s: %[2]s", i18n.Chinese: "%[1]s：%[2]s"},
+	"list_separator":     {i18n.English: "; ", i18n.Chinese: "；"},
+
+	// auth
+	"invalid_credentials": {i18n.English: "Invalid username or password", i18n.Chinese: "用户名或密码错误"},
+	"token_missing":       {i18n.English: "Authorization token required", i18n.Chinese: "未提供token"},
+	"token_malformed":     {i18n.English: "Malformed authorization header", i18n.Chinese: "token格式错误"},
+	"token_invalid":       {i18n.English: "Invalid or expired token", i18n.Chinese: "无效的token"},
+	"session_invalid":     {i18n.English: "Session is no longer valid, please sign in again", i18n.Chinese: "登录信息无效，请重新登录"},
+
+	// contact form
+	"invalid_form_token": {i18n.English: "Invalid form token, please reload the page", i18n.Chinese: "表单令牌无效，请刷新页面"},
+	"form_token_expired": {i18n.English: "The form has expired, please reload the page", i18n.Chinese: "表单已过期，请刷新页面"},
+	"form_too_fast":      {i18n.English: "The form was submitted too quickly, please try again", i18n.Chinese: "提交过快，请稍后重试"},
+
+	// newsletter
+	"invalid_newsletter_token": {i18n.English: "This link is invalid", i18n.Chinese: "链接无效"},
+	"newsletter_token_expired": {i18n.English: "This link has expired, please subscribe again", i18n.Chinese: "链接已过期，请重新订阅"},
+	"email_suppressed":         {i18n.English: "This address has unsubscribed and cannot be subscribed again", i18n.Chinese: "该邮箱已退订，不能重新订阅"},
+	"mail_unavailable":         {i18n.English: "The email could not be sent, please try again later", i18n.Chinese: "邮件发送失败，请稍后重试"},
+
+	// backup / restore
+	"upload_failed":  {i18n.English: "Failed to read the uploaded file", i18n.Chinese: "读取上传文件失败"},
+	"invalid_backup": {i18n.English: "Invalid backup file: %[1]s", i18n.Chinese: "备份文件无效：%[1]s"},
+}
+
+// terms are resource names substituted into messages such as not_found.
+var terms = map[string]map[string]string{
+	"blog":        {i18n.English: "Blog", i18n.Chinese: "博客"},
+	"solution":    {i18n.English: "Solution", i18n.Chinese: "解决方案"},
+	"carousel":    {i18n.English: "Carousel slide", i18n.Chinese: "轮播图"},
+	"category":    {i18n.English: "Category", i18n.Chinese: "分类"},
+	"social_link": {i18n.English: "Social link", i18n.Chinese: "链接"},
+	"contact":     {i18n.English: "Contact message", i18n.Chinese: "联系消息"},
+	"admin":       {i18n.English: "Admin", i18n.Chinese: "管理员"},
+	"webhook":     {i18n.English: "Webhook", i18n.Chinese: "Webhook"},
+	"delivery":    {i18n.English: "Delivery", i18n.Chinese: "投递记录"},
+	"translation": {i18n.English: "Translation", i18n.Chinese: "译文"},
+	"comment":     {i18n.English: "Comment", i18n.Chinese: "评论"},
+	"suppression": {i18n.English: "Suppression", i18n.Chinese: "禁止发送记录"},
+}
+
+// rules are per-field messages keyed by validator tag; %[1]s is the field
+// name and %[2]s the rule parameter.
+var rules = map[string]map[string]string{
+	"required":  {i18n.English: "%[1]s is required", i18n.Chinese: "%[1]s 不能为空"},
+	"email":     {i18n.English: "%[1]s must be a valid email address", i18n.Chinese: "%[1]s 不是有效的邮箱地址"},
+	"url":       {i18n.English: "%[1]s must be a valid http(s) URL", i18n.Chinese: "%[1]s 必须是有效的 http(s) 地址"},
+	"max":       {i18n.English: "%[1]s must be at most %[2]s characters", i18n.Chinese: "%[1]s 不能超过 %[2]s 个字符"},
+	"min":       {i18n.English: "%[1]s must be at least %[2]s characters", i18n.Chinese: "%[1]s 不能少于 %[2]s 个字符"},
+	"oneof":     {i18n.English: "%[1]s must be one of: %[2]s", i18n.Chinese: "%[1]s 必须是以下之一：%[2]s"},
+	"gtfield":   {i18n.English: "%[1]s must be after %[2]s", i18n.Chinese: "%[1]s 必须晚于 %[2]s"},
+	"number":    {i18n.English: "%[1]s must be a number", i18n.Chinese: "%[1]s 必须是数字"},
+	"type":      {i18n.English: "%[1]s must be of type %[2]s", i18n.Chinese: "%[1]s 的类型应为 %[2]s"},
+	"unique":    {i18n.English: "%[1]s is already taken", i18n.Chinese: "%[1]s 已被占用"},
+	"incorrect": {i18n.English: "%[1]s is incorrect", i18n.Chinese: "%[1]s 不正确"},
+	"invalid":   {i18n.English: "%[1]s is invalid", i18n.Chinese: "%[1]s 无效"},
+}
+
+// Message renders key in lang, falling back to English and then to the key
+// itself. Arguments that name a resource term are translated too.
+func Message(lang, key string, args ...string) string {
+	tmpl := lookup(messages, lang, key)
+	if tmpl == "" {
+		return key
+	}
+	if len(args) == 0 {
+		return tmpl
+	}
+	vals := make([]any, len(args))
+	for i, a := range args {
+		if t := lookup(terms, lang, a); t != "" {
+			vals[i] = t
+		} else {
+			vals[i] = a
+		}
+	}
+	return fmt.Sprintf(tmpl, vals...)
+}
+
+func fieldMessage(lang string, f FieldError) string {
+	tmpl := lookup(rules, lang, f.Rule)
+	if tmpl == "" {
+		tmpl = lookup(rules, lang, "invalid")
+	}
+	return fmt.Sprintf(tmpl, f.Field, f.Param)
+}
+
+func lookup(catalog map[string]map[string]string, lang, key string) string {
+	tr, ok := catalog[key]
+	if !ok {
+		return ""
+	}
+	if s, ok := tr[lang]; ok {
+		return s
+	}
+	return tr[i18n.English]
+}
@@ -4,64 +4,133 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
-	"encoding/json"
-	"errors"
-	"log"
+	"log/slog"
 	"time"
 
 	"backend/config"
 )
 
+// CachedResponse is a stored response. The JSON fields are its metadata;
+// bodies are kept as raw bytes next to it (see SetBody).
 type CachedResponse struct {
-	Status      int    `json:"status"`
-	ContentType string `json:"content_type"`
-	BodyB64     string `json:"body_b64"`
+	Status       int    `json:"status"`
+	ContentType  string `json:"content_type"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	CacheControl string `json:"cache_control,omitempty"`
+	// FreshUntil (unix seconds) ends the fresh period; the entry is kept a
+	// little longer so it can be served stale while it is refreshed. Zero
+	// means always fresh.
+	FreshUntil int64 `json:"fresh_until,omitempty"`
+	// ExpiresAt (unix seconds) ends the entry's life before the store TTL,
+	// for content that changes at a known time. Zero means no such limit.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+
+	Body   []byte `json:"-"` // uncompressed, only for small bodies
+	Gzip   []byte `json:"-"`
+	Brotli []byte `json:"-"`
+}
+
+// keyPrefix versions every key. Bump it when the stored format changes;
+// Init removes the previous version's keys.
+const (
+	keyPrefix    = "cache:v2:"
+	oldKeyPrefix = "cache:v1:"
+)
+
+// Store is a response cache backend. Implementations are best-effort: errors
+// are logged and counted, never returned, so a broken cache only costs
+// performance.
+type Store interface {
+	Get(ctx context.Context, key string) (*CachedResponse, bool)
+	// Set stores cr and indexes key under each tag.
+	Set(ctx context.Context, key string, cr *CachedResponse, ttl time.Duration, tags ...string)
+	// PurgeTags deletes the entries indexed under any of tags.
+	PurgeTags(ctx context.Context, tags ...string)
+	// PurgePatterns deletes keys matching Redis glob patterns (*, ? and [...]).
+	PurgePatterns(ctx context.Context, patterns ...string)
+	// Name identifies the backend in logs and /readyz.
+	Name() string
+}
+
+var store Store
+
+// Init selects the store from config.Current.Cache.Backend. Call it after
+// config.InitRedis.
+func Init() {
+	cfg := config.Current.Cache
+	switch {
+	case cfg.Backend == "none":
+		store = nil
+	case cfg.Backend == "memory":
+		store = NewMemory(cfg.MemoryMaxEntries, cfg.MemoryMaxBytes)
+	case config.Redis != nil:
+		redisStore := NewRedis(config.Redis)
+		// Entries in the previous format are unreadable now; drop them instead
+		// of waiting for their TTL. The scan is bounded so a slow Redis only
+		// delays startup briefly; whatever it misses still expires.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		redisStore.PurgePatterns(ctx, oldKeyPrefix+"*")
+		cancel()
+		store = redisStore
+		if cfg.Backend == "auto" {
+			store = NewFailover(redisStore, NewMemory(cfg.MemoryMaxEntries, cfg.MemoryMaxBytes))
+		}
+	case cfg.Backend == "auto":
+		store = NewMemory(cfg.MemoryMaxEntries, cfg.MemoryMaxBytes)
+	default: // "redis" without a valid configuration
+		store = nil
+	}
+
+	if store == nil {
+		slog.Info("response cache disabled", "backend", cfg.Backend)
+		return
+	}
+	slog.Info("response cache enabled", "backend", cfg.Backend, "store", store.Name())
+}
+
+// Use replaces the active store; nil disables caching.
+func Use(s Store) {
+	store = s
+}
+
+// Backend names the active store, or "" when caching is disabled.
+func Backend() string {
+	if store == nil {
+		return ""
+	}
+	return store.Name()
 }
 
 func Enabled() bool {
-	return config.Redis != nil
+	return store != nil
 }
 
 func CacheKey(method, path, rawQuery string) string {
 	h := sha1.Sum([]byte(rawQuery))
-	return "cache:v1:" + method + ":" + path + ":" + hex.EncodeToString(h[:])
+	return keyPrefix + method + ":" + path + ":" + hex.EncodeToString(h[:])
 }
 
 func Get(ctx context.Context, key string) (*CachedResponse, bool) {
 	if !Enabled() {
 		return nil, false
 	}
-
-	b, err := config.Redis.Get(ctx, key).Bytes()
-	if err != nil {
-		return nil, false
-	}
-
-	var cr CachedResponse
-	if err := json.Unmarshal(b, &cr); err != nil {
-		return nil, false
-	}
-	if cr.Status == 0 {
-		return nil, false
-	}
-	return &cr, true
+	return store.Get(ctx, key)
 }
 
-func Set(ctx context.Context, key string, cr *CachedResponse, ttl time.Duration) {
+// Set stores cr under key, tagged with the entities it depends on.
+func Set(ctx context.Context, key string, cr *CachedResponse, ttl time.Duration, tags ...string) {
 	if !Enabled() {
 		return
 	}
 	if cr == nil {
 		return
 	}
-	b, err := json.Marshal(cr)
-	if err != nil {
-		return
-	}
-	_ = config.Redis.Set(ctx, key, b, ttl).Err()
+	store.Set(ctx, key, cr, ttl, tags...)
 }
 
-// PurgePatterns deletes keys matching the provided Redis glob patterns.
+// PurgePatterns deletes keys matching the provided Redis glob patterns. It
+// scans the whole keyspace, so writes should use Invalidate / PurgeTags.
 //
 // This is best-effort: it logs errors but does not fail requests.
 func PurgePatterns(ctx context.Context, patterns ...string) {
@@ -71,32 +140,5 @@ func PurgePatterns(ctx context.Context, patterns ...string) {
 	if len(patterns) == 0 {
 		return
 	}
-
-	for _, pattern := range patterns {
-		if pattern == "" {
-			continue
-		}
-
-		var cursor uint64
-		for {
-			keys, next, err := config.Redis.Scan(ctx, cursor, pattern, 500).Result()
-			if err != nil {
-				if !errors.Is(err, context.Canceled) {
-					log.Printf("redis scan failed (%s): %v", pattern, err)
-				}
-				break
-			}
-
-			if len(keys) > 0 {
-				if err := config.Redis.Del(ctx, keys...).Err(); err != nil {
-					log.Printf("redis del failed (%s): %v", pattern, err)
-				}
-			}
-
-			cursor = next
-			if cursor == 0 {
-				break
-			}
-		}
-	}
+	store.PurgePatterns(ctx, patterns...)
 }
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content codings of stored bodies.
+const (
+	Identity = "identity"
+	Gzip     = "gzip"
+	Brotli   = "br"
+)
+
+// MinCompressSize is the smallest body worth compressing; smaller bodies are
+// stored and sent as is.
+const MinCompressSize = 1024
+
+// SetBody stores body, compressed at write time so hits never pay for it.
+// Compressed entries keep only the gzip and brotli forms: the rare client
+// that accepts neither gets the gzip form decompressed.
+func (cr *CachedResponse) SetBody(body []byte) error {
+	cr.Body, cr.Gzip, cr.Brotli = nil, nil, nil
+	if len(body) < MinCompressSize {
+		cr.Body = body
+		return nil
+	}
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	if _, err := gw.Write(body); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	var br bytes.Buffer
+	bw := brotli.NewWriterLevel(&br, brotli.DefaultCompression)
+	if _, err := bw.Write(body); err != nil {
+		return err
+	}
+	if err := bw.Close(); err != nil {
+		return err
+	}
+
+	cr.Gzip, cr.Brotli = gz.Bytes(), br.Bytes()
+	return nil
+}
+
+// Encodings lists the codings the entry can be sent in without
+// decompressing, in order of preference.
+func (cr *CachedResponse) Encodings() []string {
+	if cr.Body != nil {
+		return []string{Identity}
+	}
+	return []string{Brotli, Gzip}
+}
+
+// Encoded returns the body in the given coding (one of Encodings, or
+// Identity).
+func (cr *CachedResponse) Encoded(coding string) ([]byte, error) {
+	switch {
+	case cr.Body != nil:
+		return cr.Body, nil
+	case coding == Brotli:
+		return cr.Brotli, nil
+	case coding == Gzip:
+		return cr.Gzip, nil
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(cr.Gzip))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return io.ReadAll(gr)
+}
+
+func (cr *CachedResponse) size() int {
+	return len(cr.ContentType) + len(cr.ETag) + len(cr.LastModified) + len(cr.CacheControl) +
+		len(cr.Body) + len(cr.Gzip) + len(cr.Brotli)
+}
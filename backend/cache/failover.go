@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"backend/config"
+)
+
+// failoverStore serves from Redis while it is usable and from an in-process
+// LRU while it is down or its circuit breaker is open.
+type failoverStore struct {
+	redis  Store
+	memory Store
+}
+
+// NewFailover returns a Store that switches between primary (Redis) and
+// fallback as config.RedisUsable changes.
+func NewFailover(primary, fallback Store) Store {
+	return &failoverStore{redis: primary, memory: fallback}
+}
+
+func (s *failoverStore) active() Store {
+	if config.RedisUsable() {
+		return s.redis
+	}
+	return s.memory
+}
+
+func (s *failoverStore) Name() string { return s.active().Name() }
+
+func (s *failoverStore) Get(ctx context.Context, key string) (*CachedResponse, bool) {
+	return s.active().Get(ctx, key)
+}
+
+func (s *failoverStore) Set(ctx context.Context, key string, cr *CachedResponse, ttl time.Duration, tags ...string) {
+	s.active().Set(ctx, key, cr, ttl, tags...)
+}
+
+// PurgeTags always purges the fallback as well, so entries it kept from an
+// earlier outage are not served after the next one.
+func (s *failoverStore) PurgeTags(ctx context.Context, tags ...string) {
+	s.memory.PurgeTags(ctx, tags...)
+	s.redis.PurgeTags(ctx, tags...)
+}
+
+func (s *failoverStore) PurgePatterns(ctx context.Context, patterns ...string) {
+	s.memory.PurgePatterns(ctx, patterns...)
+	s.redis.PurgePatterns(ctx, patterns...)
+}
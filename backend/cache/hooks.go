@@ -0,0 +1,53 @@
+package cache
+
+import "sync"
+
+// purgeHook runs a callback in the background after purges. Runs never
+// overlap: purges during a run schedule exactly one more run, so a burst of
+// writes costs at most two.
+type purgeHook struct {
+	mu      sync.Mutex
+	fn      func()
+	running bool
+	again   bool
+}
+
+var hook purgeHook
+
+// OnPurge registers fn (e.g. cache warming) to run after PurgeTags and
+// PurgeAll; nil removes it.
+func OnPurge(fn func()) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.fn = fn
+}
+
+func afterPurge() {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if hook.fn == nil {
+		return
+	}
+	if hook.running {
+		hook.again = true
+		return
+	}
+	hook.running = true
+	go hook.loop(hook.fn)
+}
+
+func (h *purgeHook) loop(fn func()) {
+	for {
+		fn()
+
+		h.mu.Lock()
+		if !h.again || h.fn == nil {
+			h.running = false
+			h.mu.Unlock()
+			return
+		}
+		h.again = false
+		fn = h.fn
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/logger"
+)
+
+// memoryStore is a bounded in-process LRU used when Redis is not available.
+// Entries are private to the process, so with several instances each one
+// purges only its own copy; that is fine for the single-instance deployments
+// that run without Redis.
+type memoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	ll         *list.List // front is most recently used
+	items      map[string]*list.Element
+	tags       map[string]map[string]struct{} // tag -> keys
+}
+
+type memoryEntry struct {
+	key     string
+	value   CachedResponse
+	size    int
+	expires time.Time
+	tags    []string
+}
+
+// NewMemory returns an LRU Store holding at most maxEntries entries and
+// roughly maxBytes of keys and bodies.
+func NewMemory(maxEntries, maxBytes int) Store {
+	return &memoryStore{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+func (s *memoryStore) Name() string { return "memory" }
+
+func (s *memoryStore) Get(_ context.Context, key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if time.Now().After(e.expires) {
+		s.remove(el)
+		return nil, false
+	}
+	s.ll.MoveToFront(el)
+	cr := e.value
+	return &cr, true
+}
+
+func (s *memoryStore) Set(_ context.Context, key string, cr *CachedResponse, ttl time.Duration, tags ...string) {
+	size := len(key) + cr.size()
+	if size > s.maxBytes {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+	e := &memoryEntry{key: key, value: *cr, size: size, expires: time.Now().Add(ttl), tags: tags}
+	s.items[key] = s.ll.PushFront(e)
+	s.bytes += size
+	for _, tag := range tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = make(map[string]struct{})
+		}
+		s.tags[tag][key] = struct{}{}
+	}
+
+	for s.ll.Len() > s.maxEntries || s.bytes > s.maxBytes {
+		s.remove(s.ll.Back())
+	}
+}
+
+func (s *memoryStore) PurgeTags(_ context.Context, tags ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			s.remove(s.items[key])
+		}
+	}
+}
+
+func (s *memoryStore) PurgePatterns(ctx context.Context, patterns ...string) {
+	var matchers []*regexp.Regexp
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		re, err := globRegexp(pattern)
+		if err != nil {
+			logger.FromContext(ctx).Warn("invalid cache purge pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		matchers = append(matchers, re)
+	}
+	if len(matchers) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, el := range s.items {
+		for _, re := range matchers {
+			if re.MatchString(key) {
+				s.remove(el)
+				break
+			}
+		}
+	}
+}
+
+func (s *memoryStore) remove(el *list.Element) {
+	e := s.ll.Remove(el).(*memoryEntry)
+	delete(s.items, e.key)
+	s.bytes -= e.size
+	for _, tag := range e.tags {
+		delete(s.tags[tag], e.key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
+
+// globRegexp translates a Redis glob pattern into an anchored regexp. Unlike
+// path.Match, * also matches "/", as it does for Redis SCAN MATCH.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString("[" + pattern[i+1:i+1+end] + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	entry := func(body string) *CachedResponse {
+		return &CachedResponse{Status: 200, Body: []byte(body)}
+	}
+	has := func(s Store, key string) bool {
+		_, ok := s.Get(ctx, key)
+		return ok
+	}
+
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+		s := NewMemory(2, 1<<20)
+		s.Set(ctx, "a", entry("a"), time.Minute)
+		s.Set(ctx, "b", entry("b"), time.Minute)
+		has(s, "a") // touch, so b is now the oldest
+		s.Set(ctx, "c", entry("c"), time.Minute)
+		if !has(s, "a") || has(s, "b") || !has(s, "c") {
+			t.Errorf("a, b, c present = %v, %v, %v; want b evicted", has(s, "a"), has(s, "b"), has(s, "c"))
+		}
+	})
+
+	t.Run("byte budget", func(t *testing.T) {
+		s := NewMemory(100, 25)
+		s.Set(ctx, "a", entry("0123456789"), time.Minute)
+		s.Set(ctx, "b", entry("0123456789"), time.Minute)
+		s.Set(ctx, "c", entry("0123456789"), time.Minute)
+		if has(s, "a") || !has(s, "b") || !has(s, "c") {
+			t.Errorf("a, b, c present = %v, %v, %v; want a evicted to stay under the budget", has(s, "a"), has(s, "b"), has(s, "c"))
+		}
+		// An entry larger than the whole budget is not stored at all.
+		s.Set(ctx, "huge", entry(string(bytes.Repeat([]byte("x"), 30))), time.Minute)
+		if has(s, "huge") || !has(s, "c") {
+			t.Error("oversized entry was stored or evicted the others")
+		}
+	})
+
+	t.Run("entries expire with their TTL", func(t *testing.T) {
+		s := NewMemory(10, 1<<20)
+		s.Set(ctx, "a", entry("a"), -time.Second)
+		if has(s, "a") {
+			t.Error("expired entry served")
+		}
+	})
+
+	t.Run("purge by tag and pattern", func(t *testing.T) {
+		s := NewMemory(10, 1<<20)
+		s.Set(ctx, "cache:v2:GET:/api/blogs:1", entry("list"), time.Minute, TagBlogs)
+		s.Set(ctx, "cache:v2:GET:/api/blogs/7:1", entry("post"), time.Minute, TagBlogs, "blogs:7")
+		s.Set(ctx, "cache:v2:GET:/api/solutions:1", entry("solutions"), time.Minute, TagSolutions)
+
+		s.PurgeTags(ctx, "blogs:7")
+		if has(s, "cache:v2:GET:/api/blogs/7:1") || !has(s, "cache:v2:GET:/api/blogs:1") {
+			t.Error("entity tag purge removed the wrong entries")
+		}
+		// Purged keys are dropped from every tag set they were in.
+		s.Set(ctx, "cache:v2:GET:/api/blogs/7:1", entry("post"), time.Minute)
+		s.PurgeTags(ctx, TagBlogs)
+		if has(s, "cache:v2:GET:/api/blogs:1") || !has(s, "cache:v2:GET:/api/blogs/7:1") {
+			t.Error("collection tag purge removed the wrong entries")
+		}
+
+		s.PurgePatterns(ctx, "cache:v2:GET:/api/*")
+		if has(s, "cache:v2:GET:/api/blogs/7:1") || has(s, "cache:v2:GET:/api/solutions:1") {
+			t.Error("* did not match across path segments")
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"backend/config"
+	"backend/logger"
+	"backend/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisStore keeps each entry in a Redis hash shared by every instance: the
+// JSON metadata in "meta" and the raw bodies in "body", "gzip" and "br".
+//
+// While config.RedisUsable is false (Redis unreachable or the circuit
+// breaker open) every operation is a no-op: reads miss and writes are
+// dropped without waiting on a timeout.
+type redisStore struct {
+	client redis.UniversalClient
+
+	// epoch is the config.RedisEpoch the stored entries belong to; flushing
+	// is set while a background flush brings them up to date.
+	epoch    atomic.Uint64
+	flushing atomic.Bool
+}
+
+// NewRedis returns a Store backed by client. Every command touches a single
+// key, so it works against a cluster where entries and tag sets hash to
+// different slots (the cluster client splits MULTI blocks per slot).
+func NewRedis(client redis.UniversalClient) Store {
+	s := &redisStore{client: client}
+	s.epoch.Store(config.RedisEpoch())
+	return s
+}
+
+// ready reports whether Redis may be used now. When Redis comes back every
+// entry is dropped: purges made while it was skipped never reached it, so
+// anything it still holds may be stale. The flush runs in the background and
+// the store stays unready until it is done, so requests never wait on it.
+func (s *redisStore) ready() bool {
+	if !config.RedisUsable() {
+		return false
+	}
+	epoch := config.RedisEpoch()
+	if s.epoch.Load() == epoch {
+		return true
+	}
+	if s.flushing.CompareAndSwap(false, true) {
+		go s.flush(epoch)
+	}
+	return false
+}
+
+// flush drops every entry and then marks the store current for epoch. The
+// scan is bounded; whatever it misses still expires with its TTL.
+func (s *redisStore) flush(epoch uint64) {
+	defer s.flushing.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.scan(ctx, []string{keyPrefix + "*"})
+	s.epoch.Store(epoch)
+	logger.FromContext(ctx).Info("redis usable again; flushed cached responses")
+}
+
+func (s *redisStore) Name() string { return "redis" }
+
+func (s *redisStore) Get(ctx context.Context, key string) (*CachedResponse, bool) {
+	if !s.ready() {
+		return nil, false
+	}
+	fields, err := s.client.HGetAll(ctx, key).Result()
+	config.ReportRedisResult(err)
+	if err != nil {
+		metrics.RedisErrors.WithLabelValues("get").Inc()
+		return nil, false
+	}
+
+	var cr CachedResponse
+	if err := json.Unmarshal([]byte(fields["meta"]), &cr); err != nil {
+		return nil, false
+	}
+	if cr.Status == 0 {
+		return nil, false
+	}
+	if body, ok := fields["body"]; ok {
+		cr.Body = []byte(body)
+	} else {
+		cr.Gzip, cr.Brotli = []byte(fields[Gzip]), []byte(fields[Brotli])
+	}
+	return &cr, true
+}
+
+// Set writes the entry and adds key to a set per tag. All entries share one
+// TTL, so refreshing the set's expiry on each write keeps it alive exactly as
+// long as its newest member.
+func (s *redisStore) Set(ctx context.Context, key string, cr *CachedResponse, ttl time.Duration, tags ...string) {
+	if !s.ready() {
+		return
+	}
+	meta, err := json.Marshal(cr)
+	if err != nil {
+		return
+	}
+	fields := []any{"meta", meta}
+	if cr.Body != nil {
+		fields = append(fields, "body", cr.Body)
+	} else {
+		fields = append(fields, Gzip, cr.Gzip, Brotli, cr.Brotli)
+	}
+
+	// MULTI/EXEC so readers never see a half-written hash.
+	_, err = s.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Del(ctx, key)
+		p.HSet(ctx, key, fields...)
+		p.Expire(ctx, key, ttl)
+		for _, tag := range tags {
+			p.SAdd(ctx, tagKey(tag), key)
+			p.Expire(ctx, tagKey(tag), ttl)
+		}
+		return nil
+	})
+	config.ReportRedisResult(err)
+	if err != nil {
+		metrics.RedisErrors.WithLabelValues("set").Inc()
+	}
+}
+
+// PurgeTags reads and deletes each tag set atomically, then deletes its
+// members, so the cost is proportional to the tagged entries only.
+func (s *redisStore) PurgeTags(ctx context.Context, tags ...string) {
+	if !s.ready() {
+		return
+	}
+	for _, tag := range tags {
+		var members *redis.StringSliceCmd
+		_, err := s.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			members = p.SMembers(ctx, tagKey(tag))
+			p.Del(ctx, tagKey(tag))
+			return nil
+		})
+		config.ReportRedisResult(err)
+		if err != nil {
+			metrics.RedisErrors.WithLabelValues("smembers").Inc()
+			logger.FromContext(ctx).Warn("redis tag lookup failed", "tag", tag, "error", err)
+			continue
+		}
+
+		if err := s.del(ctx, members.Val()); err != nil {
+			logger.FromContext(ctx).Warn("redis del failed", "tag", tag, "error", err)
+		}
+	}
+}
+
+// PurgePatterns scans for matching keys; a cluster keeps its keyspace split
+// across masters, so each one is scanned.
+func (s *redisStore) PurgePatterns(ctx context.Context, patterns ...string) {
+	if s.ready() {
+		s.scan(ctx, patterns)
+	}
+}
+
+func (s *redisStore) scan(ctx context.Context, patterns []string) {
+	if cluster, ok := s.client.(*redis.ClusterClient); ok {
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+			s.purgePatterns(ctx, shard, patterns)
+			return nil
+		})
+		if err != nil {
+			metrics.RedisErrors.WithLabelValues("scan").Inc()
+			logger.FromContext(ctx).Warn("redis cluster scan failed", "error", err)
+		}
+		return
+	}
+	s.purgePatterns(ctx, s.client, patterns)
+}
+
+func (s *redisStore) purgePatterns(ctx context.Context, node redis.Cmdable, patterns []string) {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+
+		var cursor uint64
+		for {
+			keys, next, err := node.Scan(ctx, cursor, pattern, 500).Result()
+			config.ReportRedisResult(err)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					metrics.RedisErrors.WithLabelValues("scan").Inc()
+					logger.FromContext(ctx).Warn("redis scan failed", "pattern", pattern, "error", err)
+				}
+				break
+			}
+
+			if err := s.del(ctx, keys); err != nil {
+				logger.FromContext(ctx).Warn("redis del failed", "pattern", pattern, "error", err)
+			}
+
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+}
+
+// del removes keys with one DEL per key in a single pipeline: a multi-key
+// DEL fails with CROSSSLOT in cluster mode when the keys hash to different
+// slots, while the cluster client routes each single-key command itself.
+func (s *redisStore) del(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := s.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, key := range keys {
+			p.Del(ctx, key)
+		}
+		return nil
+	})
+	config.ReportRedisResult(err)
+	if err != nil {
+		metrics.RedisErrors.WithLabelValues("del").Inc()
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// blockScan holds every SCAN until release is closed, standing in for a
+// slow sweep over a large keyspace.
+type blockScan struct{ release chan struct{} }
+
+func (h blockScan) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h blockScan) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == "scan" {
+			select {
+			case <-h.release:
+			case <-ctx.Done():
+			}
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h blockScan) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisFlushDoesNotBlockRequests(t *testing.T) {
+	mr := miniredis.RunT(t)
+	config.Current = config.Defaults()
+	config.Current.Redis.Addr = mr.Addr()
+	config.Current.Redis.HealthIntervalSeconds = 3600
+	config.InitRedis()
+	t.Cleanup(func() {
+		config.CloseRedis()
+		config.Redis = nil
+		config.Current = config.Defaults()
+	})
+
+	release := make(chan struct{})
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	client.AddHook(blockScan{release})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	s := NewRedis(client).(*redisStore)
+	key := CacheKey("GET", "/api/blogs", "")
+	s.Set(ctx, key, &CachedResponse{Status: 200, Body: []byte("old")}, time.Minute)
+	if !mr.Exists(key) {
+		t.Fatal("entry not written")
+	}
+
+	// An outage: purges made now never reach Redis.
+	mr.Close()
+	if err := config.PingRedis(ctx); err == nil {
+		t.Fatal("ping succeeded while Redis was down")
+	}
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.PingRedis(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// While the flush is stuck in SCAN, requests neither wait for it nor see
+	// the entry it is about to drop.
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		if _, ok := s.Get(ctx, key); ok {
+			t.Fatalf("Get #%d served an entry from before the outage", i+1)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Get #%d waited %v for the flush", i+1, elapsed)
+		}
+	}
+
+	close(release)
+	for deadline := time.Now().Add(5 * time.Second); s.flushing.Load() || !s.ready(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("flush did not finish")
+		}
+	}
+	if mr.Exists(key) {
+		t.Error("entry from before the outage survived the flush")
+	}
+	s.Set(ctx, key, &CachedResponse{Status: 200, Body: []byte("new")}, time.Minute)
+	if cr, ok := s.Get(ctx, key); !ok || string(cr.Body) != "new" {
+		t.Errorf("Get after flush = %v, %v; want the new entry", cr, ok)
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+)
+
+// Tags name what a cached response depends on. A route is tagged with the
+// collections it reads (see middleware.CacheTags) and a detail handler adds
+// the tag of the record it returned (EntityTag); writes then invalidate by
+// tag instead of scanning keys.
+const (
+	TagBlogs       = "blogs"
+	TagSolutions   = "solutions"
+	TagCarousels   = "carousels"
+	TagCategories  = "categories"
+	TagSocialLinks = "social-links"
+	// TagComments is only used per post (EntityTag(TagComments, blogID)):
+	// each comment thread is purged on its own.
+	TagComments = "comments"
+)
+
+// dependents lists the collections derived from another one, e.g. category
+// responses include blog counts, so a blog write must purge them too.
+var dependents = map[string][]string{
+	TagBlogs: {TagCategories},
+}
+
+// EntityTag is the tag of one record in a collection, e.g. "blogs:3".
+func EntityTag(collection string, id int) string {
+	return collection + ":" + strconv.Itoa(id)
+}
+
+// RelatedTag marks detail responses that embed related content from
+// collection (?include=related): any write to the collection can change
+// those blocks, not just writes to the record itself.
+func RelatedTag(collection string) string {
+	return collection + ":related"
+}
+
+// Invalidate purges everything that depends on a write to collection: its
+// lists, the details of the given records, details embedding related
+// content and dependent collections. Pass no ids for a create.
+func Invalidate(ctx context.Context, collection string, ids ...int) {
+	tags := append([]string{collection, RelatedTag(collection)}, dependents[collection]...)
+	for _, id := range ids {
+		tags = append(tags, EntityTag(collection, id))
+	}
+	PurgeTags(ctx, tags...)
+}
+
+// InvalidateComments purges the public comment thread of a blog post and
+// the blog responses that show its comment count.
+func InvalidateComments(ctx context.Context, blogID int) {
+	PurgeTags(ctx, TagBlogs, EntityTag(TagBlogs, blogID), EntityTag(TagComments, blogID))
+}
+
+// PurgeTags deletes every entry stored with any of tags.
+//
+// This is best-effort: it logs errors but does not fail requests.
+func PurgeTags(ctx context.Context, tags ...string) {
+	if !Enabled() {
+		return
+	}
+	if len(tags) == 0 {
+		return
+	}
+	store.PurgeTags(ctx, tags...)
+	afterPurge()
+}
+
+// PurgeAll deletes every cached response and tag index, e.g. after a
+// database restore.
+func PurgeAll(ctx context.Context) {
+	if !Enabled() {
+		return
+	}
+	PurgePatterns(ctx, keyPrefix+"*")
+	afterPurge()
+}
+
+func tagKey(tag string) string {
+	return keyPrefix + "tag:" + tag
+}
@@ -2,10 +2,11 @@ package config
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 
 	_ "modernc.org/sqlite"
 )
@@ -13,14 +14,19 @@ import (
 var DB *sql.DB
 var DBPath string
 
+// migrated 在 InitDB 完成建表和迁移后置为 true，CloseDB 时复位
+var migrated atomic.Bool
+
+// MigrationsApplied 报告当前数据库是否已完成建表和迁移
+func MigrationsApplied() bool {
+	return migrated.Load()
+}
+
 func InitDB() {
 	var err error
 
 	// Ensure data directory exists
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./data.db"
-	}
+	dbPath := Current.Database.Path
 	DBPath = dbPath
 	dir := filepath.Dir(dbPath)
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -29,16 +35,16 @@ func InitDB() {
 
 	DB, err = sql.Open("sqlite", dbPath)
 	if err != nil {
-		log.Fatal("数据库连接失败:", err)
+		fatal("database open failed", err, "path", dbPath)
 	}
 
 	// 测试连接
 	err = DB.Ping()
 	if err != nil {
-		log.Fatal("数据库ping失败:", err)
+		fatal("database ping failed", err, "path", dbPath)
 	}
 
-	log.Println("SQLite数据库连接成功")
+	slog.Info("sqlite database connected", "path", dbPath)
 
 	// 创建数据库和数据表
 	createTables()
@@ -51,6 +57,26 @@ func InitDB() {
 
 	// 迁移轮播图图片显示尺寸字段
 	migrateCarouselImageSize()
+
+	// 迁移联系表单垃圾信息标记字段
+	migrateContactSpam()
+
+	// 迁移博客/解决方案推荐、置顶和排序字段
+	migrateContentOrdering()
+
+	// 迁移轮播图启用开关、展示时间窗口和语言字段
+	migrateCarouselSchedule()
+
+	// 迁移轮播图按钮（CTA）字段
+	migrateCarouselCTA()
+
+	// 迁移评论首次公开时间字段
+	migrateCommentNotified()
+
+	// 为没有签名密钥的 Webhook 生成密钥
+	migrateWebhookSecrets()
+
+	migrated.Store(true)
 }
 
 func migrateSEO() {
@@ -65,28 +91,16 @@ func migrateSEO() {
 	}
 
 	for _, sqlStmt := range seoColumns {
-		_, err := DB.Exec(sqlStmt)
-		if err != nil {
-			// Ignore error if column already exists (sqlite doesn't support IF NOT EXISTS for ADD COLUMN)
-			// In a production env, we would check specifically for "duplicate column name" error
-			log.Printf("Migration note (might be expected if columns exist): %v", err)
-		} else {
-			log.Println("Executed migration:", sqlStmt)
-		}
+		execMigration(sqlStmt)
 	}
 }
 
 // migrateCarouselRotation 为已有的 carousels 表增加 rotation 字段
 // SQLite 不支持 IF NOT EXISTS 语法，这里和 migrateSEO 一样：
-// - 如果列已存在，会打印一条日志但忽略错误
+// - 如果列已存在，execMigration 会忽略该错误
 // - 如果列不存在，则正常新增，默认值为 0（不旋转）
 func migrateCarouselRotation() {
-	sqlStmt := "ALTER TABLE carousels ADD COLUMN rotation INTEGER NOT NULL DEFAULT 0;"
-	if _, err := DB.Exec(sqlStmt); err != nil {
-		log.Printf("Migration note for carousels.rotation (might be expected if column exists): %v", err)
-	} else {
-		log.Println("Executed migration:", sqlStmt)
-	}
+	execMigration("ALTER TABLE carousels ADD COLUMN rotation INTEGER NOT NULL DEFAULT 0;")
 }
 
 // migrateCarouselImageSize 为已有的 carousels 表增加 image_width / image_height 字段
@@ -100,19 +114,94 @@ func migrateCarouselImageSize() {
 		"ALTER TABLE carousels ADD COLUMN image_height INTEGER NOT NULL DEFAULT 0;",
 	}
 	for _, sqlStmt := range stmts {
-		if _, err := DB.Exec(sqlStmt); err != nil {
-			log.Printf("Migration note for carousels image size (might be expected if column exists): %v", err)
-		} else {
-			log.Println("Executed migration:", sqlStmt)
+		execMigration(sqlStmt)
+	}
+}
+
+// migrateContactSpam 为已有的 contacts 表增加 is_spam / spam_reason 字段
+//
+// 命中关键词或链接数量规则的留言仍会入库，但会被标记为垃圾信息，
+// 方便管理员在后台复核。
+func migrateContactSpam() {
+	stmts := []string{
+		"ALTER TABLE contacts ADD COLUMN is_spam INTEGER NOT NULL DEFAULT 0;",
+		"ALTER TABLE contacts ADD COLUMN spam_reason TEXT NOT NULL DEFAULT '';",
+	}
+	for _, sqlStmt := range stmts {
+		execMigration(sqlStmt)
+	}
+}
+
+// migrateContentOrdering 为 blogs / solutions 表增加 featured / pinned / sort_order 字段
+//
+// 默认值都是 0，已有数据的列表顺序不变（仍按创建时间倒序）。
+func migrateContentOrdering() {
+	for _, table := range []string{"blogs", "solutions"} {
+		execMigration("ALTER TABLE " + table + " ADD COLUMN featured INTEGER NOT NULL DEFAULT 0;")
+		execMigration("ALTER TABLE " + table + " ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;")
+		execMigration("ALTER TABLE " + table + " ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;")
+	}
+}
+
+// migrateCarouselSchedule 为已有的 carousels 表增加 active / starts_at / ends_at / locale 字段
+// 已有的轮播图默认启用、不限时间和语言，升级后展示效果不变
+func migrateCarouselSchedule() {
+	execMigration("ALTER TABLE carousels ADD COLUMN active INTEGER NOT NULL DEFAULT 1;")
+	execMigration("ALTER TABLE carousels ADD COLUMN starts_at DATETIME;")
+	execMigration("ALTER TABLE carousels ADD COLUMN ends_at DATETIME;")
+	execMigration("ALTER TABLE carousels ADD COLUMN locale TEXT NOT NULL DEFAULT '';")
+}
+
+// migrateCarouselCTA 为已有的 carousels 表增加按钮文字和跳转目标字段；默认没有按钮
+func migrateCarouselCTA() {
+	execMigration("ALTER TABLE carousels ADD COLUMN cta_label TEXT NOT NULL DEFAULT '';")
+	execMigration("ALTER TABLE carousels ADD COLUMN cta_type TEXT NOT NULL DEFAULT '';")
+	execMigration("ALTER TABLE carousels ADD COLUMN cta_url TEXT NOT NULL DEFAULT '';")
+	execMigration("ALTER TABLE carousels ADD COLUMN cta_ref_id INTEGER NOT NULL DEFAULT 0;")
+}
+
+// migrateCommentNotified 为已有的 blog_comments 表增加 notified_at 字段
+// 已公开的评论视为已通知过，之后撤回再审核通过也不会重复推送回复通知
+func migrateCommentNotified() {
+	execMigration("ALTER TABLE blog_comments ADD COLUMN notified_at DATETIME;")
+	if _, err := DB.Exec("UPDATE blog_comments SET notified_at = updated_at WHERE notified_at IS NULL AND status = 'approved'"); err != nil {
+		slog.Warn("migration failed", "table", "blog_comments", "column", "notified_at", "error", err)
+	}
+}
+
+// migrateWebhookSecrets 为未设置 secret 的 Webhook 订阅生成随机密钥，所有投递都带签名
+// 接收方需要在管理后台重新设置 secret 后才能校验这些订阅的签名
+func migrateWebhookSecrets() {
+	if _, err := DB.Exec("UPDATE webhooks SET secret = lower(hex(randomblob(32))) WHERE secret = ''"); err != nil {
+		slog.Warn("migration failed", "table", "webhooks", "column", "secret", "error", err)
+	}
+}
+
+// execMigration 执行一条 ALTER TABLE 迁移语句
+// 列已存在（duplicate column）视为已迁移，只记 debug 日志
+func execMigration(sqlStmt string) {
+	if _, err := DB.Exec(sqlStmt); err != nil {
+		if strings.Contains(err.Error(), "duplicate column") {
+			slog.Debug("migration already applied", "sql", sqlStmt)
+			return
 		}
+		slog.Warn("migration failed", "sql", sqlStmt, "error", err)
+		return
 	}
+	slog.Info("migration executed", "sql", sqlStmt)
+}
+
+// fatal 记录错误日志并退出进程
+func fatal(msg string, err error, args ...any) {
+	slog.Error(msg, append(args, "error", err)...)
+	os.Exit(1)
 }
 
 func createTables() {
 	// Enable foreign keys
 	_, err := DB.Exec("PRAGMA foreign_keys = ON")
 	if err != nil {
-		log.Println("启用外键失败:", err)
+		slog.Warn("enable foreign keys failed", "error", err)
 	}
 
 	// 创建管理员表
@@ -126,7 +215,7 @@ func createTables() {
 	`
 	_, err = DB.Exec(adminTable)
 	if err != nil {
-		log.Fatal("创建管理员表失败:", err)
+		fatal("create table failed", err, "table", "admins")
 	}
 
 	// 创建博客表
@@ -143,7 +232,7 @@ func createTables() {
 	`
 	_, err = DB.Exec(blogTable)
 	if err != nil {
-		log.Fatal("创建博客表失败:", err)
+		fatal("create table failed", err, "table", "blogs")
 	}
 
 	// 创建博客分类表
@@ -160,7 +249,7 @@ func createTables() {
 	`
 	_, err = DB.Exec(categoryTable)
 	if err != nil {
-		log.Fatal("创建博客分类表失败:", err)
+		fatal("create table failed", err, "table", "blog_categories")
 	}
 
 	// 创建博客-分类关联表
@@ -175,7 +264,7 @@ func createTables() {
 	`
 	_, err = DB.Exec(relationTable)
 	if err != nil {
-		log.Fatal("创建博客-分类关联表失败:", err)
+		fatal("create table failed", err, "table", "blog_category_relations")
 	}
 
 	// 创建解决方案表
@@ -192,7 +281,132 @@ func createTables() {
 	`
 	_, err = DB.Exec(solutionTable)
 	if err != nil {
-		log.Fatal("创建解决方案表失败:", err)
+		fatal("create table failed", err, "table", "solutions")
+	}
+
+	// 创建博客/解决方案翻译表（原文所在的 blogs/solutions 表为默认语言）
+	translationTables := `
+	CREATE TABLE IF NOT EXISTS blog_translations (
+		blog_id INTEGER NOT NULL,
+		locale TEXT NOT NULL,
+		title TEXT NOT NULL,
+		summary TEXT NOT NULL DEFAULT '',
+		content TEXT NOT NULL DEFAULT '',
+		meta_title TEXT NOT NULL DEFAULT '',
+		meta_description TEXT NOT NULL DEFAULT '',
+		meta_keywords TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (blog_id, locale),
+		FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_blog_translations_locale ON blog_translations (locale);
+	CREATE TABLE IF NOT EXISTS solution_translations (
+		solution_id INTEGER NOT NULL,
+		locale TEXT NOT NULL,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		meta_title TEXT NOT NULL DEFAULT '',
+		meta_description TEXT NOT NULL DEFAULT '',
+		meta_keywords TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (solution_id, locale),
+		FOREIGN KEY (solution_id) REFERENCES solutions(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_solution_translations_locale ON solution_translations (locale);
+	`
+	_, err = DB.Exec(translationTables)
+	if err != nil {
+		fatal("create table failed", err, "table", "translations")
+	}
+
+	// 创建相关内容表：写入博客/解决方案时预先计算（kind 为 blogs / solutions）
+	// relation 为 related（按相似度，rank 从 0 开始）、previous、next（按发布时间）
+	relationsTable := `
+	CREATE TABLE IF NOT EXISTS content_relations (
+		kind TEXT NOT NULL,
+		item_id INTEGER NOT NULL,
+		relation TEXT NOT NULL,
+		rank INTEGER NOT NULL DEFAULT 0,
+		target_id INTEGER NOT NULL,
+		score REAL NOT NULL DEFAULT 0,
+		PRIMARY KEY (kind, item_id, relation, rank)
+	);
+	`
+	_, err = DB.Exec(relationsTable)
+	if err != nil {
+		fatal("create table failed", err, "table", "content_relations")
+	}
+
+	// 创建浏览统计表：按内容、天（UTC）和来源域名累计，不保存任何访客信息
+	// kind 为 blog / solution；referrer 为空表示直接访问或站内跳转
+	pageViewsTable := `
+	CREATE TABLE IF NOT EXISTS page_views (
+		kind TEXT NOT NULL,
+		item_id INTEGER NOT NULL,
+		day TEXT NOT NULL,
+		referrer TEXT NOT NULL DEFAULT '',
+		views INTEGER NOT NULL DEFAULT 0,
+		visitors INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (kind, item_id, day, referrer)
+	);
+	CREATE INDEX IF NOT EXISTS idx_page_views_day ON page_views(day);
+	`
+	_, err = DB.Exec(pageViewsTable)
+	if err != nil {
+		fatal("create table failed", err, "table", "page_views")
+	}
+
+	// 创建博客评论表：parent_id 为 0 表示顶层评论
+	// status 为 pending / approved / spam；删除文章时由 repository 一并删除评论
+	// notified_at 为第一次公开的时间，回复通知只在这时推送一次
+	commentsTable := `
+	CREATE TABLE IF NOT EXISTS blog_comments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		blog_id INTEGER NOT NULL,
+		parent_id INTEGER NOT NULL DEFAULT 0,
+		author TEXT NOT NULL,
+		email TEXT NOT NULL,
+		content TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		spam_reason TEXT NOT NULL DEFAULT '',
+		notify_replies INTEGER NOT NULL DEFAULT 0,
+		notified_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_blog_comments_blog ON blog_comments(blog_id, status);
+	CREATE INDEX IF NOT EXISTS idx_blog_comments_parent ON blog_comments(parent_id);
+	`
+	_, err = DB.Exec(commentsTable)
+	if err != nil {
+		fatal("create table failed", err, "table", "blog_comments")
+	}
+
+	// 创建邮件订阅表（双重确认）；status 为 pending / confirmed / unsubscribed
+	// 禁止发送列表中的地址不会再收到确认邮件，见 docs/18-newsletter.md
+	newsletterTables := `
+	CREATE TABLE IF NOT EXISTS newsletter_subscribers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email TEXT NOT NULL UNIQUE,
+		status TEXT NOT NULL DEFAULT 'pending',
+		locale TEXT NOT NULL DEFAULT '',
+		confirmed_at DATETIME,
+		unsubscribed_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS newsletter_suppressions (
+		email TEXT PRIMARY KEY,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	_, err = DB.Exec(newsletterTables)
+	if err != nil {
+		fatal("create table failed", err, "table", "newsletter_subscribers")
 	}
 
 	// 创建联系表
@@ -203,13 +417,15 @@ func createTables() {
 		email TEXT NOT NULL,
 		subject TEXT NOT NULL,
 		message TEXT NOT NULL,
+		is_spam INTEGER NOT NULL DEFAULT 0,
+		spam_reason TEXT NOT NULL DEFAULT '',
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 	`
 	_, err = DB.Exec(contactTable)
 	if err != nil {
-		log.Fatal("创建联系表失败:", err)
+		fatal("create table failed", err, "table", "contacts")
 	}
 
 	// 创建首页轮播图表
@@ -225,13 +441,21 @@ func createTables() {
 		rotation INTEGER NOT NULL DEFAULT 0,
 		image_width INTEGER NOT NULL DEFAULT 0,
 		image_height INTEGER NOT NULL DEFAULT 0,
+		active INTEGER NOT NULL DEFAULT 1,
+		starts_at DATETIME,
+		ends_at DATETIME,
+		locale TEXT NOT NULL DEFAULT '',
+		cta_label TEXT NOT NULL DEFAULT '',
+		cta_type TEXT NOT NULL DEFAULT '',
+		cta_url TEXT NOT NULL DEFAULT '',
+		cta_ref_id INTEGER NOT NULL DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 	`
 	_, err = DB.Exec(carouselTable)
 	if err != nil {
-		log.Fatal("创建轮播图表失败:", err)
+		fatal("create table failed", err, "table", "carousels")
 	}
 
 	// 创建社交媒体链接表
@@ -247,7 +471,48 @@ func createTables() {
 	`
 	_, err = DB.Exec(socialLinkTable)
 	if err != nil {
-		log.Fatal("创建社交媒体链接表失败:", err)
+		fatal("create table failed", err, "table", "social_links")
+	}
+
+	// 创建 Webhook 订阅表
+	// events 为逗号分隔的事件类型，"*" 表示订阅全部事件
+	webhookTable := `
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL DEFAULT '',
+		events TEXT NOT NULL DEFAULT '*',
+		active INTEGER NOT NULL DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	_, err = DB.Exec(webhookTable)
+	if err != nil {
+		fatal("create table failed", err, "table", "webhooks")
+	}
+
+	// 创建 Webhook 投递队列/日志表
+	webhookDeliveryTable := `
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		response_status INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (status, next_attempt_at);
+	`
+	_, err = DB.Exec(webhookDeliveryTable)
+	if err != nil {
+		fatal("create table failed", err, "table", "webhook_deliveries")
 	}
 
 	// 插入默认管理员账号
@@ -256,9 +521,9 @@ func createTables() {
 	if err == nil && count == 0 {
 		_, err = DB.Exec("INSERT INTO admins (username, password) VALUES (?, ?)", "admin", "admin123")
 		if err != nil {
-			log.Println("插入默认管理员失败:", err)
+			slog.Error("insert default admin failed", "error", err)
 		} else {
-			log.Println("默认管理员账号已创建: admin / admin123")
+			slog.Warn("default admin account created; change the password", "username", "admin")
 		}
 	}
 
@@ -283,19 +548,20 @@ func createTables() {
 		for _, cat := range categories {
 			_, err := stmt.Exec(cat.Name, cat.Slug, cat.Icon, cat.Color)
 			if err != nil {
-				log.Printf("插入分类 %s 失败: %v\n", cat.Name, err)
+				slog.Error("insert default category failed", "category", cat.Name, "error", err)
 			} else {
-				log.Printf("分类 %s 已创建\n", cat.Name)
+				slog.Info("default category created", "category", cat.Name)
 			}
 		}
 	}
 
-	log.Println("数据表创建完成")
+	slog.Info("database tables ready")
 }
 
 func CloseDB() {
+	migrated.Store(false)
 	if DB != nil {
 		DB.Close()
-		fmt.Println("数据库连接已关闭")
+		slog.Info("database connection closed")
 	}
 }
@@ -2,59 +2,181 @@ package config
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
 	"os"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-var Redis *redis.Client
+// Redis is a single-node, Sentinel (failover) or Cluster client depending on
+// REDIS_MODE.
+var Redis redis.UniversalClient
+
+// RedisConfigured reports whether REDIS_ADDR or REDIS_URL is set, i.e. Redis
+// is expected to be available rather than intentionally disabled.
+func RedisConfigured() bool {
+	return Current.Redis.Addr != "" || Current.Redis.URL != ""
+}
 
 // InitRedis initializes the global Redis client.
 //
-// If REDIS_ADDR is empty, Redis is treated as disabled and no error is returned.
+// If neither REDIS_ADDR nor REDIS_URL is set, Redis is treated as disabled
+// and no error is returned. A failed first ping keeps the client: the health
+// loop picks Redis up as soon as it answers.
 func InitRedis() {
-	addr := os.Getenv("REDIS_ADDR")
-	if addr == "" {
-		log.Println("REDIS_ADDR not set; redis cache disabled")
+	if !RedisConfigured() {
+		slog.Info("REDIS_ADDR not set; redis cache disabled")
 		return
 	}
 
-	db := 0
-	if v := os.Getenv("REDIS_DB"); v != "" {
-		if parsed, err := strconv.Atoi(v); err == nil {
-			db = parsed
-		}
+	s := Current.Redis
+	opts, err := redisOptions(s)
+	if err != nil {
+		slog.Warn("redis configuration invalid; redis cache disabled", "error", err)
+		return
 	}
 
-	Redis = redis.NewClient(&redis.Options{
-		Addr:         addr,
-		Password:     os.Getenv("REDIS_PASSWORD"),
-		DB:           db,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  2 * time.Second,
-		WriteTimeout: 2 * time.Second,
-	})
+	switch s.Mode {
+	case "cluster":
+		Redis = redis.NewClusterClient(opts.Cluster())
+	case "sentinel":
+		Redis = redis.NewFailoverClient(opts.Failover())
+	default:
+		Redis = redis.NewClient(opts.Simple())
+	}
 
+	startRedisMonitor(Redis, s)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if err := Redis.Ping(ctx).Err(); err != nil {
-		log.Printf("Redis ping failed (%s): %v; redis cache disabled", addr, err)
-		Redis = nil
+	if err := PingRedis(ctx); err != nil {
+		slog.Warn("redis ping failed; retrying in the background", "mode", s.Mode, "addrs", opts.Addrs, "error", err)
 		return
 	}
 
-	log.Printf("Redis connected: %s (db=%d)", addr, db)
+	slog.Info("redis connected", "mode", s.Mode, "addrs", opts.Addrs, "db", opts.DB, "tls", opts.TLSConfig != nil)
 }
 
 func CloseRedis() {
 	if Redis == nil {
 		return
 	}
+	stopRedisMonitor()
 	if err := Redis.Close(); err != nil {
-		log.Printf("Redis close failed: %v", err)
+		slog.Warn("redis close failed", "error", err)
+	}
+}
+
+// redisOptions builds client options from the discrete settings, overlaid by
+// REDIS_URL when set, plus the TLS files.
+func redisOptions(s RedisSettings) (*redis.UniversalOptions, error) {
+	opts := &redis.UniversalOptions{
+		Username:         s.Username,
+		Password:         s.Password,
+		DB:               s.DB,
+		MasterName:       s.SentinelMaster,
+		SentinelPassword: s.SentinelPassword,
+		DialTimeout:      5 * time.Second,
+		ReadTimeout:      2 * time.Second,
+		WriteTimeout:     2 * time.Second,
+	}
+	for _, addr := range strings.Split(s.Addr, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			opts.Addrs = append(opts.Addrs, addr)
+		}
+	}
+
+	if s.URL != "" {
+		u, err := redisURLOptions(s.Mode, s.URL)
+		if err != nil {
+			return nil, err
+		}
+		opts.Addrs, opts.Username, opts.Password, opts.DB, opts.TLSConfig = u.Addrs, u.Username, u.Password, u.DB, u.TLSConfig
+		if s.Mode == "sentinel" {
+			opts.MasterName, opts.SentinelUsername, opts.SentinelPassword = u.MasterName, u.SentinelUsername, u.SentinelPassword
+		}
+	}
+
+	tlsConfig, err := redisTLS(s, opts.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	opts.TLSConfig = tlsConfig
+	return opts, nil
+}
+
+// redisURLOptions parses REDIS_URL with the go-redis parser for mode.
+func redisURLOptions(mode, raw string) (*redis.UniversalOptions, error) {
+	switch mode {
+	case "cluster":
+		o, err := redis.ParseClusterURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &redis.UniversalOptions{Addrs: o.Addrs, Username: o.Username, Password: o.Password, TLSConfig: o.TLSConfig}, nil
+	case "sentinel":
+		o, err := redis.ParseFailoverURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		if o.MasterName == "" {
+			return nil, fmt.Errorf("sentinel URL needs a master_name parameter")
+		}
+		return &redis.UniversalOptions{
+			Addrs:            o.SentinelAddrs,
+			MasterName:       o.MasterName,
+			SentinelUsername: o.SentinelUsername,
+			SentinelPassword: o.SentinelPassword,
+			Username:         o.Username,
+			Password:         o.Password,
+			DB:               o.DB,
+			TLSConfig:        o.TLSConfig,
+		}, nil
+	default:
+		o, err := redis.ParseURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &redis.UniversalOptions{Addrs: []string{o.Addr}, Username: o.Username, Password: o.Password, DB: o.DB, TLSConfig: o.TLSConfig}, nil
+	}
+}
+
+// redisTLS extends base (set by a rediss:// URL) with the configured CA,
+// client certificate and server name. It returns nil when TLS is off.
+func redisTLS(s RedisSettings, base *tls.Config) (*tls.Config, error) {
+	if base == nil && !s.TLS && s.TLSCAFile == "" && s.TLSCertFile == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		cfg = base.Clone()
+	}
+	if s.TLSServerName != "" {
+		cfg.ServerName = s.TLSServerName
+	}
+	if s.TLSCAFile != "" {
+		pem, err := os.ReadFile(s.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read REDIS_TLS_CA_FILE: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("REDIS_TLS_CA_FILE %s: no PEM certificates found", s.TLSCAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if s.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.TLSCertFile, s.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load REDIS_TLS_CERT_FILE/REDIS_TLS_KEY_FILE: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
 	}
+	return cfg, nil
 }
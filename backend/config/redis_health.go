@@ -0,0 +1,241 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisState describes whether the cache may currently use Redis.
+type RedisState string
+
+const (
+	RedisDisabled     RedisState = "disabled"     // REDIS_ADDR/REDIS_URL not set
+	RedisConnected    RedisState = "connected"    // last ping succeeded
+	RedisDisconnected RedisState = "disconnected" // last ping failed; retried in the background
+	RedisCircuitOpen  RedisState = "circuit_open" // too many errors; skipped until the cooldown ends
+)
+
+// RedisStatus is a snapshot of the connection health for /readyz and the
+// admin cache status endpoint.
+type RedisStatus struct {
+	State      RedisState `json:"state"`
+	Mode       string     `json:"mode,omitempty"`
+	Since      *time.Time `json:"since,omitempty"`
+	Failures   int        `json:"consecutive_failures"`
+	Reconnects int        `json:"reconnects"`
+	LastError  string     `json:"last_error,omitempty"`
+	OpenUntil  *time.Time `json:"circuit_open_until,omitempty"`
+}
+
+// redisHealth tracks the connection and the circuit breaker. The go-redis
+// pool redials on its own once the server is back; the health loop only
+// notices when that happens so the cache can start using Redis again.
+type redisHealth struct {
+	mu         sync.Mutex
+	connected  bool
+	failures   int
+	openUntil  time.Time
+	lastError  string
+	since      time.Time
+	reconnects int
+	// epoch is bumped every time Redis becomes usable again, so the cache
+	// can drop entries that missed purges while it was skipped.
+	epoch uint64
+	stop  context.CancelFunc
+	done  chan struct{}
+
+	// Snapshot taken when the monitor starts, so background cache calls
+	// never read Redis or Current while they are being replaced.
+	client    redis.UniversalClient
+	mode      string
+	threshold int
+	cooldown  time.Duration
+}
+
+var health redisHealth
+
+// RedisUsable reports whether cache operations should go to Redis: the
+// client exists, the last ping succeeded and the circuit is closed.
+func RedisUsable() bool {
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	health.expireCircuit(time.Now())
+	return health.client != nil && health.connected && health.openUntil.IsZero()
+}
+
+// RedisEpoch changes each time Redis becomes usable after being skipped.
+func RedisEpoch() uint64 {
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	health.expireCircuit(time.Now())
+	return health.epoch
+}
+
+// GetRedisStatus returns the current connection state.
+func GetRedisStatus() RedisStatus {
+	if !RedisConfigured() {
+		return RedisStatus{State: RedisDisabled}
+	}
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	now := time.Now()
+	health.expireCircuit(now)
+
+	st := RedisStatus{
+		State:      RedisDisconnected,
+		Mode:       health.mode,
+		Failures:   health.failures,
+		Reconnects: health.reconnects,
+		LastError:  health.lastError,
+	}
+	if !health.since.IsZero() {
+		since := health.since
+		st.Since = &since
+	}
+	switch {
+	case health.client == nil || !health.connected:
+	case !health.openUntil.IsZero():
+		st.State = RedisCircuitOpen
+		until := health.openUntil
+		st.OpenUntil = &until
+	default:
+		st.State = RedisConnected
+	}
+	return st
+}
+
+// ReportRedisResult feeds the outcome of a cache operation to the circuit
+// breaker. Misses and cancelled requests are not failures.
+func ReportRedisResult(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	if err == nil || errors.Is(err, redis.Nil) {
+		health.failures = 0
+		return
+	}
+
+	health.failures++
+	health.lastError = err.Error()
+	if health.failures >= health.threshold && health.openUntil.IsZero() {
+		health.openUntil = time.Now().Add(health.cooldown)
+		health.since = time.Now()
+		slog.Warn("redis circuit opened; skipping redis", "failures", health.failures, "cooldown", health.cooldown, "error", err)
+	}
+}
+
+// PingRedis pings Redis and records the result. It returns nil when Redis
+// is not configured.
+func PingRedis(ctx context.Context) error {
+	health.mu.Lock()
+	client := health.client
+	health.mu.Unlock()
+	if client == nil {
+		if RedisConfigured() {
+			return errors.New("redis client not initialized")
+		}
+		return nil
+	}
+	err := client.Ping(ctx).Err()
+
+	health.mu.Lock()
+	defer health.mu.Unlock()
+	now := time.Now()
+	health.expireCircuit(now)
+	if err != nil {
+		health.lastError = err.Error()
+		if health.connected || health.since.IsZero() {
+			health.connected = false
+			health.since = now
+			slog.Warn("redis unreachable; cache falls back until it returns", "error", err)
+		}
+		return err
+	}
+
+	// A ping does not close an open breaker: the errors that opened it came
+	// from real cache traffic, so only expireCircuit ends the cooldown.
+	if !health.openUntil.IsZero() {
+		return nil
+	}
+	health.failures = 0
+	if !health.connected {
+		if !health.since.IsZero() {
+			health.reconnects++
+			slog.Info("redis reachable again", "mode", health.mode)
+		}
+		health.connected = true
+		health.since = now
+		health.epoch++
+	}
+	return nil
+}
+
+// expireCircuit half-opens the breaker once the cooldown is over: calls go
+// through again, and the failure count stays at the threshold so a single
+// further error reopens it. Callers hold mu.
+func (h *redisHealth) expireCircuit(now time.Time) {
+	if h.openUntil.IsZero() || now.Before(h.openUntil) {
+		return
+	}
+	h.openUntil = time.Time{}
+	h.since = now
+	if h.connected {
+		h.epoch++
+	}
+}
+
+// monitorRedis pings Redis every interval until ctx is cancelled, then
+// closes done.
+func monitorRedis(ctx context.Context, interval time.Duration, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, time.Second)
+			_ = PingRedis(pingCtx)
+			cancel()
+		}
+	}
+}
+
+// startRedisMonitor resets the health state for client, snapshots the
+// breaker settings from s and starts the background loop.
+func startRedisMonitor(client redis.UniversalClient, s RedisSettings) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	health.mu.Lock()
+	health.connected, health.failures, health.reconnects = false, 0, 0
+	health.openUntil, health.since, health.lastError = time.Time{}, time.Time{}, ""
+	health.stop, health.done = cancel, done
+	health.client, health.mode = client, s.Mode
+	health.threshold = s.BreakerThreshold
+	health.cooldown = time.Duration(s.BreakerCooldownSeconds) * time.Second
+	health.mu.Unlock()
+	go monitorRedis(ctx, time.Duration(s.HealthIntervalSeconds)*time.Second, done)
+}
+
+// stopRedisMonitor stops the loop and waits for it, so the client can be
+// closed and replaced safely.
+func stopRedisMonitor() {
+	health.mu.Lock()
+	stop, done := health.stop, health.done
+	health.stop, health.done = nil, nil
+	health.connected = false
+	health.client = nil
+	health.mu.Unlock()
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
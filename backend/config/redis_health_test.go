@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPingDoesNotCloseCircuit(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	Current.Redis.Addr = mr.Addr()
+	t.Cleanup(func() { Current = Defaults() })
+
+	// A long interval keeps the background loop out of the way.
+	startRedisMonitor(client, RedisSettings{BreakerThreshold: 2, BreakerCooldownSeconds: 60, HealthIntervalSeconds: 3600})
+	t.Cleanup(stopRedisMonitor)
+
+	ctx := context.Background()
+	if err := PingRedis(ctx); err != nil || !RedisUsable() {
+		t.Fatalf("PingRedis = %v, usable = %v; want connected", err, RedisUsable())
+	}
+
+	for i := 0; i < 2; i++ {
+		ReportRedisResult(errors.New("i/o timeout"))
+	}
+	if st := GetRedisStatus(); st.State != RedisCircuitOpen {
+		t.Fatalf("state = %s, want %s", st.State, RedisCircuitOpen)
+	}
+	epoch := RedisEpoch()
+
+	// Redis still answers pings while commands time out; the breaker must
+	// stay open for the whole cooldown.
+	if err := PingRedis(ctx); err != nil {
+		t.Fatalf("PingRedis: %v", err)
+	}
+	if st := GetRedisStatus(); st.State != RedisCircuitOpen || RedisUsable() {
+		t.Errorf("after ping during cooldown: state = %s, usable = %v; want circuit open", st.State, RedisUsable())
+	}
+
+	// Once the cooldown is over the breaker half-opens and the cache may
+	// use Redis again.
+	health.mu.Lock()
+	health.openUntil = time.Now().Add(-time.Second)
+	health.mu.Unlock()
+	if !RedisUsable() || RedisEpoch() == epoch {
+		t.Errorf("after cooldown: usable = %v, epoch = %d (was %d); want usable with a new epoch", RedisUsable(), RedisEpoch(), epoch)
+	}
+	// A single further error reopens it.
+	ReportRedisResult(errors.New("i/o timeout"))
+	if RedisUsable() {
+		t.Error("half-open breaker did not reopen after an error")
+	}
+}
+
+func TestReconnectAfterFailedStart(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+	initTestRedis(t, func(s *RedisSettings) {
+		s.Addr = addr
+		s.HealthIntervalSeconds = 1
+	})
+
+	// The client is kept and retried in the background.
+	if Redis == nil {
+		t.Fatal("client dropped after a failed first ping")
+	}
+	if st := GetRedisStatus(); st.State != RedisDisconnected || RedisUsable() {
+		t.Fatalf("state = %s, want %s", st.State, RedisDisconnected)
+	}
+	epoch := RedisEpoch()
+
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); GetRedisStatus().State != RedisConnected; time.Sleep(20 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the health loop to reconnect")
+		}
+	}
+	if st := GetRedisStatus(); st.Reconnects != 1 || !RedisUsable() || RedisEpoch() == epoch {
+		t.Errorf("status = %+v, epoch = %d (was %d); want one reconnect and a new epoch", st, RedisEpoch(), epoch)
+	}
+}
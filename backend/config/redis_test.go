@@ -0,0 +1,156 @@
+package config
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// initTestRedis connects the global client with the default settings
+// changed by configure, and closes it when the test ends.
+func initTestRedis(t *testing.T, configure func(*RedisSettings)) {
+	t.Helper()
+	Current = Defaults()
+	configure(&Current.Redis)
+	InitRedis()
+	t.Cleanup(func() {
+		CloseRedis()
+		Redis = nil
+		Current = Defaults()
+	})
+}
+
+func TestRedisConnectionOptions(t *testing.T) {
+	// connected checks the client is up and writes to the expected server.
+	connected := func(t *testing.T, mr *miniredis.Miniredis) {
+		t.Helper()
+		if st := GetRedisStatus(); st.State != RedisConnected {
+			t.Fatalf("state = %s (%s), want %s", st.State, st.LastError, RedisConnected)
+		}
+		if err := Redis.Set(context.Background(), "probe", "1", time.Minute).Err(); err != nil {
+			t.Fatalf("SET: %v", err)
+		}
+		if !mr.Exists("probe") {
+			t.Error("nothing was written to the configured Redis")
+		}
+	}
+
+	t.Run("URL with ACL username", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		mr.RequireUserAuth("app", "secret")
+		initTestRedis(t, func(s *RedisSettings) {
+			s.URL = "redis://app:secret@" + mr.Addr() + "/0"
+		})
+		connected(t, mr)
+	})
+
+	t.Run("TLS with CA and client certificate", func(t *testing.T) {
+		certs := newTestCertificates(t)
+		mr := miniredis.NewMiniRedis()
+		if err := mr.StartTLS(&tls.Config{
+			Certificates: []tls.Certificate{certs.server},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    certs.pool,
+		}); err != nil {
+			t.Fatalf("start TLS redis: %v", err)
+		}
+		t.Cleanup(mr.Close)
+
+		initTestRedis(t, func(s *RedisSettings) {
+			s.URL = "rediss://" + mr.Addr()
+			s.TLSCAFile = certs.caFile
+			s.TLSCertFile = certs.clientCert
+			s.TLSKeyFile = certs.clientKey
+		})
+		connected(t, mr)
+	})
+}
+
+// testCertificates is a CA with a server certificate for 127.0.0.1 and a
+// client certificate; the files are PEM in a temporary directory.
+type testCertificates struct {
+	pool                          *x509.CertPool
+	server                        tls.Certificate
+	caFile, clientCert, clientKey string
+}
+
+func newTestCertificates(t *testing.T) testCertificates {
+	t.Helper()
+	dir := t.TempDir()
+	writePEM := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	issue := func(tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parent == nil {
+			parent, parentKey = tmpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, key
+	}
+	keyDER := func(key *ecdsa.PrivateKey) []byte {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+
+	validity := func(serial int64, name string) x509.Certificate {
+		return x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+	}
+
+	caTmpl := validity(1, "test CA")
+	caTmpl.IsCA, caTmpl.BasicConstraintsValid = true, true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign
+	caDER, caKey := issue(&caTmpl, nil, nil)
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var certs testCertificates
+	certs.caFile = writePEM("ca.pem", "CERTIFICATE", caDER)
+	certs.pool = x509.NewCertPool()
+	certs.pool.AddCert(caCert)
+
+	serverTmpl := validity(2, "redis")
+	serverTmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	serverTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverDER, serverKey := issue(&serverTmpl, caCert, caKey)
+	certs.server = tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}
+
+	clientTmpl := validity(3, "app")
+	clientTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	clientDER, key := issue(&clientTmpl, caCert, caKey)
+	certs.clientCert = writePEM("client.pem", "CERTIFICATE", clientDER)
+	certs.clientKey = writePEM("client-key.pem", "EC PRIVATE KEY", keyDER(key))
+	return certs
+}
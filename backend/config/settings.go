@@ -0,0 +1,485 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultJWTSecret is the historical built-in signing key. Startup warns
+// when it is still in use.
+const DefaultJWTSecret = "your-secret-key-change-this-in-production"
+
+// Settings is the complete backend configuration.
+//
+// Values are resolved in this order (later wins): built-in defaults, the
+// optional config file (YAML or TOML, see Load), `.env`, then the process
+// environment. Each field's `env` tag names its environment variable.
+type Settings struct {
+	Server     ServerSettings     `yaml:"server" toml:"server"`
+	Log        LogSettings        `yaml:"log" toml:"log"`
+	Database   DatabaseSettings   `yaml:"database" toml:"database"`
+	Redis      RedisSettings      `yaml:"redis" toml:"redis"`
+	Cache      CacheSettings      `yaml:"cache" toml:"cache"`
+	Auth       AuthSettings       `yaml:"auth" toml:"auth"`
+	Metrics    MetricsSettings    `yaml:"metrics" toml:"metrics"`
+	Contact    ContactSettings    `yaml:"contact" toml:"contact"`
+	Webhook    WebhookSettings    `yaml:"webhook" toml:"webhook"`
+	Analytics  AnalyticsSettings  `yaml:"analytics" toml:"analytics"`
+	Comments   CommentSettings    `yaml:"comments" toml:"comments"`
+	Mail       MailSettings       `yaml:"mail" toml:"mail"`
+	Newsletter NewsletterSettings `yaml:"newsletter" toml:"newsletter"`
+}
+
+type ServerSettings struct {
+	Port        int      `yaml:"port" toml:"port" env:"PORT"`
+	Mode        string   `yaml:"mode" toml:"mode" env:"GIN_MODE"`
+	CORSOrigins []string `yaml:"cors_origins" toml:"cors_origins" env:"CORS_ORIGINS"`
+	// TrustedProxies lists the reverse proxies (IPs or CIDRs) whose
+	// X-Forwarded-For is believed; empty means the peer address is the
+	// client.
+	TrustedProxies         []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	ReadTimeoutSeconds     int      `yaml:"read_timeout_seconds" toml:"read_timeout_seconds" env:"HTTP_READ_TIMEOUT_SECONDS"`
+	WriteTimeoutSeconds    int      `yaml:"write_timeout_seconds" toml:"write_timeout_seconds" env:"HTTP_WRITE_TIMEOUT_SECONDS"`
+	IdleTimeoutSeconds     int      `yaml:"idle_timeout_seconds" toml:"idle_timeout_seconds" env:"HTTP_IDLE_TIMEOUT_SECONDS"`
+	ShutdownTimeoutSeconds int      `yaml:"shutdown_timeout_seconds" toml:"shutdown_timeout_seconds" env:"SHUTDOWN_TIMEOUT_SECONDS"`
+}
+
+type LogSettings struct {
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"`
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL"`
+}
+
+type DatabaseSettings struct {
+	Path string `yaml:"path" toml:"path" env:"DB_PATH"`
+}
+
+type RedisSettings struct {
+	// URL (redis:// or rediss://) replaces Addr, Username, Password and DB;
+	// its format depends on Mode (see go-redis ParseURL, ParseFailoverURL and
+	// ParseClusterURL).
+	URL string `yaml:"url" toml:"url" env:"REDIS_URL" secret:"true"`
+	// Addr is host:port, or a comma-separated list of sentinel or cluster
+	// nodes.
+	Addr     string `yaml:"addr" toml:"addr" env:"REDIS_ADDR"`
+	Username string `yaml:"username" toml:"username" env:"REDIS_USERNAME"`
+	Password string `yaml:"password" toml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB       int    `yaml:"db" toml:"db" env:"REDIS_DB"`
+	// Mode is single, sentinel or cluster.
+	Mode             string `yaml:"mode" toml:"mode" env:"REDIS_MODE"`
+	SentinelMaster   string `yaml:"sentinel_master" toml:"sentinel_master" env:"REDIS_SENTINEL_MASTER"`
+	SentinelPassword string `yaml:"sentinel_password" toml:"sentinel_password" env:"REDIS_SENTINEL_PASSWORD" secret:"true"`
+	// TLS is implied by a rediss:// URL or any of the TLS files.
+	TLS           bool   `yaml:"tls" toml:"tls" env:"REDIS_TLS"`
+	TLSCAFile     string `yaml:"tls_ca_file" toml:"tls_ca_file" env:"REDIS_TLS_CA_FILE"`
+	TLSCertFile   string `yaml:"tls_cert_file" toml:"tls_cert_file" env:"REDIS_TLS_CERT_FILE"`
+	TLSKeyFile    string `yaml:"tls_key_file" toml:"tls_key_file" env:"REDIS_TLS_KEY_FILE"`
+	TLSServerName string `yaml:"tls_server_name" toml:"tls_server_name" env:"REDIS_TLS_SERVER_NAME"`
+	// HealthIntervalSeconds is how often the background loop pings Redis
+	// to notice it going away or coming back.
+	HealthIntervalSeconds int `yaml:"health_interval_seconds" toml:"health_interval_seconds" env:"REDIS_HEALTH_INTERVAL_SECONDS"`
+	// After BreakerThreshold consecutive errors Redis is skipped for
+	// BreakerCooldownSeconds, so a slow Redis does not delay every request.
+	BreakerThreshold       int `yaml:"breaker_threshold" toml:"breaker_threshold" env:"REDIS_BREAKER_THRESHOLD"`
+	BreakerCooldownSeconds int `yaml:"breaker_cooldown_seconds" toml:"breaker_cooldown_seconds" env:"REDIS_BREAKER_COOLDOWN_SECONDS"`
+}
+
+type CacheSettings struct {
+	TTLSeconds int `yaml:"ttl_seconds" toml:"ttl_seconds" env:"CACHE_TTL_SECONDS"`
+	// Control is the default Cache-Control for public GET responses; routes
+	// may override it with middleware.CacheControl.
+	Control string `yaml:"control" toml:"control" env:"CACHE_CONTROL"`
+	// Backend selects the store: "auto" uses Redis when it is reachable and
+	// falls back to the in-process LRU, "redis" and "memory" force one,
+	// "none" disables the response cache.
+	Backend          string `yaml:"backend" toml:"backend" env:"CACHE_BACKEND"`
+	MemoryMaxEntries int    `yaml:"memory_max_entries" toml:"memory_max_entries" env:"CACHE_MEMORY_MAX_ENTRIES"`
+	MemoryMaxBytes   int    `yaml:"memory_max_bytes" toml:"memory_max_bytes" env:"CACHE_MEMORY_MAX_BYTES"`
+	// StaleSeconds is how long an expired entry is still served while a
+	// single background request refreshes it (0 disables).
+	StaleSeconds int `yaml:"stale_seconds" toml:"stale_seconds" env:"CACHE_STALE_SECONDS"`
+	// WarmPaths are requested again in the background after every purge.
+	WarmPaths []string `yaml:"warm_paths" toml:"warm_paths" env:"CACHE_WARM_PATHS"`
+}
+
+type AuthSettings struct {
+	JWTSecret string `yaml:"jwt_secret" toml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+}
+
+type MetricsSettings struct {
+	Token string `yaml:"token" toml:"token" env:"METRICS_TOKEN" secret:"true"`
+}
+
+type ContactSettings struct {
+	RateLimit          int      `yaml:"rate_limit" toml:"rate_limit" env:"CONTACT_RATE_LIMIT"`
+	RateWindowSeconds  int      `yaml:"rate_window_seconds" toml:"rate_window_seconds" env:"CONTACT_RATE_WINDOW_SECONDS"`
+	MinFillSeconds     int      `yaml:"min_fill_seconds" toml:"min_fill_seconds" env:"CONTACT_MIN_FILL_SECONDS"`
+	TokenMaxAgeSeconds int      `yaml:"token_max_age_seconds" toml:"token_max_age_seconds" env:"CONTACT_TOKEN_MAX_AGE_SECONDS"`
+	TokenSecret        string   `yaml:"token_secret" toml:"token_secret" env:"CONTACT_TOKEN_SECRET" secret:"true"`
+	MaxLinks           int      `yaml:"max_links" toml:"max_links" env:"CONTACT_MAX_LINKS"`
+	SpamKeywords       []string `yaml:"spam_keywords" toml:"spam_keywords" env:"CONTACT_SPAM_KEYWORDS"`
+}
+
+type WebhookSettings struct {
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+}
+
+type AnalyticsSettings struct {
+	// Enabled turns the view beacon on; when off POST /api/track is accepted
+	// but not counted.
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"ANALYTICS_ENABLED"`
+	// FlushSeconds is how often buffered counts are written to the database.
+	FlushSeconds int `yaml:"flush_seconds" toml:"flush_seconds" env:"ANALYTICS_FLUSH_SECONDS"`
+	// RateLimit caps beacons per client IP per minute (0 disables).
+	RateLimit int `yaml:"rate_limit" toml:"rate_limit" env:"ANALYTICS_RATE_LIMIT"`
+}
+
+type CommentSettings struct {
+	// RequireApproval holds new comments for moderation; when off they are
+	// published at once (spam is still held).
+	RequireApproval bool `yaml:"require_approval" toml:"require_approval" env:"COMMENTS_REQUIRE_APPROVAL"`
+	// RateLimit caps comments per client IP per RateWindowSeconds (0 disables).
+	RateLimit         int `yaml:"rate_limit" toml:"rate_limit" env:"COMMENTS_RATE_LIMIT"`
+	RateWindowSeconds int `yaml:"rate_window_seconds" toml:"rate_window_seconds" env:"COMMENTS_RATE_WINDOW_SECONDS"`
+	// MaxDepth is the deepest reply level; 1 allows top-level comments only.
+	MaxDepth int `yaml:"max_depth" toml:"max_depth" env:"COMMENTS_MAX_DEPTH"`
+}
+
+type MailSettings struct {
+	// Driver is "log" (write messages to the log only) or "smtp".
+	Driver       string `yaml:"driver" toml:"driver" env:"MAIL_DRIVER"`
+	From         string `yaml:"from" toml:"from" env:"MAIL_FROM"`
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" toml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+}
+
+type NewsletterSettings struct {
+	// SiteURL is the public frontend; confirmation and unsubscribe links
+	// point at its /newsletter/confirm and /newsletter/unsubscribe pages.
+	SiteURL string `yaml:"site_url" toml:"site_url" env:"NEWSLETTER_SITE_URL"`
+	// Secret signs the links. Empty derives one from JWT_SECRET, so links
+	// keep working across restarts either way.
+	Secret          string `yaml:"secret" toml:"secret" env:"NEWSLETTER_SECRET" secret:"true"`
+	ConfirmTTLHours int    `yaml:"confirm_ttl_hours" toml:"confirm_ttl_hours" env:"NEWSLETTER_CONFIRM_TTL_HOURS"`
+	// RateLimit caps subscribe requests per client IP per RateWindowSeconds
+	// (0 disables).
+	RateLimit         int `yaml:"rate_limit" toml:"rate_limit" env:"NEWSLETTER_RATE_LIMIT"`
+	RateWindowSeconds int `yaml:"rate_window_seconds" toml:"rate_window_seconds" env:"NEWSLETTER_RATE_WINDOW_SECONDS"`
+}
+
+// Defaults returns the built-in configuration.
+func Defaults() Settings {
+	return Settings{
+		Server: ServerSettings{
+			Port:                   8080,
+			Mode:                   "debug",
+			CORSOrigins:            []string{"http://localhost:3000", "http://localhost:3001"},
+			ReadTimeoutSeconds:     300,
+			WriteTimeoutSeconds:    300,
+			IdleTimeoutSeconds:     120,
+			ShutdownTimeoutSeconds: 30,
+		},
+		Log:      LogSettings{Format: "json", Level: "info"},
+		Database: DatabaseSettings{Path: "./data.db"},
+		Redis: RedisSettings{
+			Mode:                   "single",
+			HealthIntervalSeconds:  5,
+			BreakerThreshold:       5,
+			BreakerCooldownSeconds: 30,
+		},
+		Cache: CacheSettings{
+			TTLSeconds:       300,
+			Control:          "public, max-age=0, must-revalidate",
+			Backend:          "auto",
+			MemoryMaxEntries: 1000,
+			MemoryMaxBytes:   32 << 20,
+			StaleSeconds:     60,
+		},
+		Auth: AuthSettings{JWTSecret: DefaultJWTSecret},
+		Contact: ContactSettings{
+			RateLimit:          5,
+			RateWindowSeconds:  600,
+			MinFillSeconds:     3,
+			TokenMaxAgeSeconds: 7200,
+			MaxLinks:           2,
+		},
+		Webhook:   WebhookSettings{MaxAttempts: 6},
+		Analytics: AnalyticsSettings{Enabled: true, FlushSeconds: 10, RateLimit: 60},
+		Comments:  CommentSettings{RequireApproval: true, RateLimit: 5, RateWindowSeconds: 600, MaxDepth: 3},
+		Mail:      MailSettings{Driver: "log", From: "no-reply@localhost", SMTPPort: 587},
+		Newsletter: NewsletterSettings{
+			SiteURL:           "http://localhost:3000",
+			ConfirmTTLHours:   48,
+			RateLimit:         5,
+			RateWindowSeconds: 600,
+		},
+	}
+}
+
+// Current holds the effective configuration. It starts out as Defaults()
+// and is replaced by Load.
+var Current = Defaults()
+
+// Load resolves the configuration, validates it and stores it in Current.
+//
+// file may be empty; otherwise it is parsed as TOML when it ends in .toml
+// and as YAML otherwise. CONFIG_FILE is used when file is empty.
+func Load(file string) (Settings, error) {
+	s := Defaults()
+
+	// .env never overrides variables already set in the environment.
+	_ = godotenv.Load()
+
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		if err := loadFile(file, &s); err != nil {
+			return s, err
+		}
+	}
+
+	var errs []error
+	applyEnv(reflect.ValueOf(&s).Elem(), &errs)
+	errs = append(errs, s.Validate()...)
+	if len(errs) > 0 {
+		return s, fmt.Errorf("invalid configuration:\n  %w", joinLines(errs))
+	}
+
+	Current = s
+	return s, nil
+}
+
+func loadFile(path string, s *Settings) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(s)
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		err = dec.Decode(s)
+		if errors.Is(err, io.EOF) {
+			// Empty file: keep defaults.
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overlays environment variables onto tagged fields, collecting
+// parse errors instead of silently keeping the default.
+func applyEnv(v reflect.Value, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			applyEnv(fv, errs)
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			// Empty means "not set", matching the old `if v == ""` fallbacks.
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(raw)
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %q is not an integer", name, raw))
+				continue
+			}
+			fv.SetInt(int64(n))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %q is not a boolean", name, raw))
+				continue
+			}
+			fv.SetBool(b)
+		case reflect.Slice:
+			var items []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			fv.Set(reflect.ValueOf(items))
+		}
+	}
+}
+
+// Validate checks value ranges and enumerations.
+func (s Settings) Validate() []error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(s.Server.Port >= 1 && s.Server.Port <= 65535, "PORT: must be between 1 and 65535, got %d", s.Server.Port)
+	check(s.Server.Mode == "debug" || s.Server.Mode == "release" || s.Server.Mode == "test", "GIN_MODE: must be debug, release or test, got %q", s.Server.Mode)
+	check(len(s.Server.CORSOrigins) > 0, "CORS_ORIGINS: at least one origin is required")
+	for _, origin := range s.Server.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "CORS_ORIGINS: %q is not an http(s) origin", origin)
+	}
+	for _, proxy := range s.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "TRUSTED_PROXIES: %q is not an IP address or CIDR range", proxy)
+	}
+	check(s.Server.ReadTimeoutSeconds >= 0, "HTTP_READ_TIMEOUT_SECONDS: must not be negative")
+	check(s.Server.WriteTimeoutSeconds >= 0, "HTTP_WRITE_TIMEOUT_SECONDS: must not be negative")
+	check(s.Server.IdleTimeoutSeconds >= 0, "HTTP_IDLE_TIMEOUT_SECONDS: must not be negative")
+	check(s.Server.ShutdownTimeoutSeconds >= 0, "SHUTDOWN_TIMEOUT_SECONDS: must not be negative")
+
+	check(s.Log.Format == "json" || s.Log.Format == "text", "LOG_FORMAT: must be json or text, got %q", s.Log.Format)
+	switch strings.ToLower(s.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		check(false, "LOG_LEVEL: must be debug, info, warn or error, got %q", s.Log.Level)
+	}
+
+	check(s.Database.Path != "", "DB_PATH: must not be empty")
+	check(s.Redis.DB >= 0, "REDIS_DB: must not be negative, got %d", s.Redis.DB)
+	switch s.Redis.Mode {
+	case "single", "sentinel", "cluster":
+	default:
+		check(false, "REDIS_MODE: must be single, sentinel or cluster, got %q", s.Redis.Mode)
+	}
+	if s.Redis.URL != "" {
+		_, err := redisURLOptions(s.Redis.Mode, s.Redis.URL)
+		check(err == nil, "REDIS_URL: %v", err)
+	} else {
+		check(s.Redis.Mode != "sentinel" || s.Redis.SentinelMaster != "", "REDIS_SENTINEL_MASTER: required in sentinel mode")
+		check(s.Redis.Mode != "cluster" || s.Redis.DB == 0, "REDIS_DB: cluster mode only has database 0")
+	}
+	check((s.Redis.TLSCertFile == "") == (s.Redis.TLSKeyFile == ""), "REDIS_TLS_CERT_FILE, REDIS_TLS_KEY_FILE: set both or neither")
+	check(s.Redis.HealthIntervalSeconds > 0, "REDIS_HEALTH_INTERVAL_SECONDS: must be positive, got %d", s.Redis.HealthIntervalSeconds)
+	check(s.Redis.BreakerThreshold > 0, "REDIS_BREAKER_THRESHOLD: must be positive, got %d", s.Redis.BreakerThreshold)
+	check(s.Redis.BreakerCooldownSeconds > 0, "REDIS_BREAKER_COOLDOWN_SECONDS: must be positive, got %d", s.Redis.BreakerCooldownSeconds)
+	check(s.Cache.TTLSeconds > 0, "CACHE_TTL_SECONDS: must be positive, got %d", s.Cache.TTLSeconds)
+	switch s.Cache.Backend {
+	case "auto", "redis", "memory", "none":
+	default:
+		check(false, "CACHE_BACKEND: must be auto, redis, memory or none, got %q", s.Cache.Backend)
+	}
+	check(s.Cache.MemoryMaxEntries > 0, "CACHE_MEMORY_MAX_ENTRIES: must be positive, got %d", s.Cache.MemoryMaxEntries)
+	check(s.Cache.MemoryMaxBytes > 0, "CACHE_MEMORY_MAX_BYTES: must be positive, got %d", s.Cache.MemoryMaxBytes)
+	check(s.Cache.StaleSeconds >= 0, "CACHE_STALE_SECONDS: must not be negative (0 disables)")
+	for _, path := range s.Cache.WarmPaths {
+		check(strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/api/admin"), "CACHE_WARM_PATHS: %q is not a public /api/ path", path)
+	}
+	check(s.Auth.JWTSecret != "", "JWT_SECRET: must not be empty")
+
+	check(s.Contact.RateLimit >= 0, "CONTACT_RATE_LIMIT: must not be negative (0 disables)")
+	check(s.Contact.RateWindowSeconds > 0, "CONTACT_RATE_WINDOW_SECONDS: must be positive")
+	check(s.Contact.MinFillSeconds >= 0, "CONTACT_MIN_FILL_SECONDS: must not be negative")
+	check(s.Contact.TokenMaxAgeSeconds > s.Contact.MinFillSeconds, "CONTACT_TOKEN_MAX_AGE_SECONDS: must be greater than CONTACT_MIN_FILL_SECONDS")
+	check(s.Contact.MaxLinks >= 0, "CONTACT_MAX_LINKS: must not be negative")
+
+	check(s.Webhook.MaxAttempts >= 1, "WEBHOOK_MAX_ATTEMPTS: must be at least 1")
+	check(s.Analytics.FlushSeconds > 0, "ANALYTICS_FLUSH_SECONDS: must be positive, got %d", s.Analytics.FlushSeconds)
+	check(s.Analytics.RateLimit >= 0, "ANALYTICS_RATE_LIMIT: must not be negative (0 disables)")
+
+	check(s.Comments.RateLimit >= 0, "COMMENTS_RATE_LIMIT: must not be negative (0 disables)")
+	check(s.Comments.RateWindowSeconds > 0, "COMMENTS_RATE_WINDOW_SECONDS: must be positive")
+	check(s.Comments.MaxDepth >= 1, "COMMENTS_MAX_DEPTH: must be at least 1")
+
+	check(s.Mail.Driver == "log" || s.Mail.Driver == "smtp", "MAIL_DRIVER: must be log or smtp, got %q", s.Mail.Driver)
+	check(s.Mail.From != "", "MAIL_FROM: must not be empty")
+	if s.Mail.Driver == "smtp" {
+		check(s.Mail.SMTPHost != "", "SMTP_HOST: required when MAIL_DRIVER=smtp")
+		check(s.Mail.SMTPPort >= 1 && s.Mail.SMTPPort <= 65535, "SMTP_PORT: must be between 1 and 65535, got %d", s.Mail.SMTPPort)
+	}
+
+	site, err := url.Parse(s.Newsletter.SiteURL)
+	check(err == nil && (site.Scheme == "http" || site.Scheme == "https") && site.Host != "", "NEWSLETTER_SITE_URL: %q is not an http(s) URL", s.Newsletter.SiteURL)
+	check(s.Newsletter.ConfirmTTLHours > 0, "NEWSLETTER_CONFIRM_TTL_HOURS: must be positive")
+	check(s.Newsletter.RateLimit >= 0, "NEWSLETTER_RATE_LIMIT: must not be negative (0 disables)")
+	check(s.Newsletter.RateWindowSeconds > 0, "NEWSLETTER_RATE_WINDOW_SECONDS: must be positive")
+	return errs
+}
+
+// Seconds converts a *Seconds setting to a time.Duration.
+func Seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+// Redacted returns a copy with secret fields masked, for display.
+func (s Settings) Redacted() Settings {
+	redact(reflect.ValueOf(&s).Elem())
+	return s
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			redact(fv)
+			continue
+		}
+		if t.Field(i).Tag.Get("secret") == "true" && fv.Kind() == reflect.String && fv.String() != "" {
+			fv.SetString("******")
+		}
+	}
+}
+
+// Print writes the effective configuration as YAML with secrets redacted.
+func (s Settings) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(s.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func joinLines(errs []error) error {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return errors.New(strings.Join(msgs, "\n  "))
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"backend/analytics"
+	"backend/apierror"
+	"backend/config"
+	"backend/logger"
+	"backend/models"
+	"backend/repository"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 统计报表默认最近 30 天，最长一年
+const (
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+)
+
+// trackRequest 浏览上报；referrer 为页面的 document.referrer
+type trackRequest struct {
+	Kind     string `json:"kind" binding:"required,oneof=blog solution"`
+	ID       int    `json:"id" binding:"required"`
+	Referrer string `json:"referrer" binding:"max=2048"`
+}
+
+// TrackView 记录一次详情页浏览（公开接口，前端用 navigator.sendBeacon 上报）
+// 请求体：{"kind": "blog", "id": 3, "referrer": "https://www.google.com/"}
+// 爬虫、带 DNT: 1 或 Sec-GPC: 1 的请求以及关闭统计时同样返回 204，只是不计数
+func TrackView(c *gin.Context) {
+	var req trackRequest
+	// sendBeacon 发送的 Content-Type 是 text/plain，这里不看 Content-Type
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	if !countable(c) {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var err error
+	if req.Kind == analytics.KindBlog {
+		_, err = repos.Blogs.Get(ctx, strconv.Itoa(req.ID))
+	} else {
+		_, err = repos.Solutions.Get(ctx, strconv.Itoa(req.ID))
+	}
+	if err != nil {
+		respondError(c, repoError(err, req.Kind))
+		return
+	}
+
+	analytics.Record(ctx, analytics.Hit{
+		Kind:      req.Kind,
+		ItemID:    req.ID,
+		Referrer:  analytics.ReferrerHost(req.Referrer, c.Request.Host, headerHost(c, "Origin"), headerHost(c, "Referer")),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Time:      time.Now(),
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// countable 判断这次浏览是否计数：统计已开启、不是爬虫、访客没有要求不跟踪
+func countable(c *gin.Context) bool {
+	return config.Current.Analytics.Enabled &&
+		!analytics.IsBot(c.Request.UserAgent()) &&
+		c.GetHeader("DNT") != "1" &&
+		c.GetHeader("Sec-GPC") != "1"
+}
+
+// headerHost 取 Origin / Referer 头中的主机名，用来识别站内跳转
+func headerHost(c *gin.Context, name string) string {
+	u, err := url.Parse(c.GetHeader(name))
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// SaveViews 把缓冲的浏览数累加到数据库（analytics 定时任务调用）；调用方需持有数据库读锁
+func SaveViews(ctx context.Context, counts []models.ViewCount) error {
+	return repos.Analytics.AddViews(ctx, counts)
+}
+
+// flushViews 查询报表前先写入缓冲中的浏览数，让报表包含最新数据
+func flushViews(ctx context.Context) {
+	if err := analytics.Flush(ctx, SaveViews); err != nil {
+		logger.FromContext(ctx).Warn("analytics flush failed", "error", err)
+	}
+}
+
+// analyticsFilter 解析报表的公共参数：
+// ?kind=blog|solution、?id=（需同时指定 kind）、?from= / ?to=（YYYY-MM-DD，UTC，包含首尾两天）
+// 默认截至今天的最近 30 天，跨度不能超过 366 天
+func analyticsFilter(c *gin.Context) (repository.AnalyticsFilter, bool) {
+	var f repository.AnalyticsFilter
+
+	switch f.Kind = c.Query("kind"); f.Kind {
+	case "", analytics.KindBlog, analytics.KindSolution:
+	default:
+		respondError(c, apierror.Validation(apierror.Field("kind", "oneof", "blog solution")))
+		return f, false
+	}
+	if v := c.Query("id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, apierror.Validation(apierror.Field("id", "number")))
+			return f, false
+		}
+		if f.Kind == "" {
+			respondError(c, apierror.Validation(apierror.Field("kind", "required")))
+			return f, false
+		}
+		f.ItemID = id
+	}
+
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			respondError(c, apierror.Validation(apierror.Field("to", "invalid")))
+			return f, false
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, 1-analyticsDefaultDays)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil || t.After(to) || to.Sub(t) >= analyticsMaxDays*24*time.Hour {
+			respondError(c, apierror.Validation(apierror.Field("from", "invalid")))
+			return f, false
+		}
+		from = t
+	}
+
+	f.From, f.To = analytics.Day(from), analytics.Day(to)
+	return f, true
+}
+
+// analyticsLimit 解析 ?limit=（默认 10，最大 100）
+func analyticsLimit(c *gin.Context) int {
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+		return min(v, 100)
+	}
+	return 10
+}
+
+// GetTopContent 浏览最多的内容（管理员），参数见 analyticsFilter，另支持 ?limit=
+// 已删除的内容仍会列出，标题和路径为空
+func GetTopContent(c *gin.Context) {
+	f, ok := analyticsFilter(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	flushViews(ctx)
+
+	items, err := repos.Analytics.Top(ctx, f, analyticsLimit(c))
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	for i, item := range items {
+		var err error
+		if item.Kind == analytics.KindBlog {
+			var b models.Blog
+			b, err = repos.Blogs.Get(ctx, strconv.Itoa(item.ID))
+			items[i].Title, items[i].Path = b.Title, b.Path
+		} else {
+			var s models.Solution
+			s, err = repos.Solutions.Get(ctx, strconv.Itoa(item.ID))
+			items[i].Title, items[i].Path = s.Title, s.Path
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			respondError(c, apierror.Internal(err))
+			return
+		}
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, items)
+}
+
+// GetDailyViews 每天的浏览数（管理员），参数见 analyticsFilter；没有浏览的日子补 0
+func GetDailyViews(c *gin.Context) {
+	f, ok := analyticsFilter(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	flushViews(ctx)
+
+	days, err := repos.Analytics.Daily(ctx, f)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	byDay := make(map[string]models.DailyViews, len(days))
+	for _, d := range days {
+		byDay[d.Day] = d
+	}
+
+	series := []models.DailyViews{}
+	from, _ := time.Parse(time.DateOnly, f.From)
+	for t := from; analytics.Day(t) <= f.To; t = t.AddDate(0, 0, 1) {
+		day := analytics.Day(t)
+		d, ok := byDay[day]
+		if !ok {
+			d = models.DailyViews{Day: day}
+		}
+		series = append(series, d)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, series)
+}
+
+// GetReferrers 按来源域名汇总的浏览数（管理员），参数见 analyticsFilter，另支持 ?limit=
+// referrer 为空表示直接访问或站内跳转
+func GetReferrers(c *gin.Context) {
+	f, ok := analyticsFilter(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	flushViews(ctx)
+
+	refs, err := repos.Analytics.Referrers(ctx, f, analyticsLimit(c))
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, refs)
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"backend/apierror"
 	"backend/config"
 	"fmt"
 	"net/http"
@@ -10,7 +11,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key-change-this-in-production")
+// jwtSecret 返回 JWT 签名密钥（JWT_SECRET）
+func jwtSecret() []byte {
+	return []byte(config.Current.Auth.JWTSecret)
+}
 
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -32,15 +36,14 @@ type UpdateCredentialsRequest struct {
 func AdminLogin(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据"})
+		respondError(c, apierror.Bind(err))
 		return
 	}
 
 	// 查询管理员
-	var password string
-	err := config.DB.QueryRow("SELECT password FROM admins WHERE username = ?", req.Username).Scan(&password)
-	if err != nil || password != req.Password {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户名或密码错误"})
+	admin, err := repos.Admins.GetByUsername(c.Request.Context(), req.Username)
+	if err != nil || admin.Password != req.Password {
+		respondError(c, apierror.New(http.StatusUnauthorized, "invalid_credentials"))
 		return
 	}
 
@@ -54,9 +57,9 @@ func AdminLogin(c *gin.Context) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString(jwtSecret())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成token失败"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 
@@ -70,39 +73,37 @@ func AdminLogin(c *gin.Context) {
 func UpdateAdminCredentials(c *gin.Context) {
 	usernameVal, exists := c.Get("username")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "未找到登录信息"})
+		respondError(c, apierror.Unauthorized("session_invalid"))
 		return
 	}
 	currentUsername, ok := usernameVal.(string)
 	if !ok || currentUsername == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的登录信息"})
+		respondError(c, apierror.Unauthorized("session_invalid"))
 		return
 	}
 
 	var req UpdateCredentialsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据"})
+		respondError(c, apierror.Bind(err))
 		return
 	}
 
 	// 查询当前管理员密码
-	var storedPassword string
-	err := config.DB.QueryRow("SELECT password FROM admins WHERE username = ?", currentUsername).Scan(&storedPassword)
+	admin, err := repos.Admins.GetByUsername(c.Request.Context(), currentUsername)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "管理员不存在或登录已失效"})
+		respondError(c, apierror.Unauthorized("session_invalid"))
 		return
 	}
 
-	if storedPassword != req.CurrentPassword {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "当前密码不正确"})
+	if admin.Password != req.CurrentPassword {
+		respondError(c, apierror.Validation(apierror.Field("current_password", "incorrect")))
 		return
 	}
 
 	// 更新用户名和密码
-	_, err = config.DB.Exec("UPDATE admins SET username = ?, password = ? WHERE username = ?",
-		req.NewUsername, req.NewPassword, currentUsername)
+	err = repos.Admins.UpdateCredentials(c.Request.Context(), currentUsername, req.NewUsername, req.NewPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新管理员信息失败: " + err.Error()})
+		respondError(c, repoError(err, "admin"))
 		return
 	}
 
@@ -116,9 +117,9 @@ func UpdateAdminCredentials(c *gin.Context) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString(jwtSecret())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成新的token失败"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 
@@ -134,8 +135,7 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "未提供token"})
-			c.Abort()
+			respondError(c, apierror.Unauthorized("token_missing"))
 			return
 		}
 
@@ -143,8 +143,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
 			tokenString = tokenString[7:]
 		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "token格式错误"})
-			c.Abort()
+			respondError(c, apierror.Unauthorized("token_malformed"))
 			return
 		}
 
@@ -154,18 +153,16 @@ func AuthMiddleware() gin.HandlerFunc {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("无效的签名方法: %v", token.Header["alg"])
 			}
-			return jwtSecret, nil
+			return jwtSecret(), nil
 		})
 
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "token解析失败: " + err.Error()})
-			c.Abort()
+			respondError(c, apierror.Unauthorized("token_invalid"))
 			return
 		}
 
 		if !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的token"})
-			c.Abort()
+			respondError(c, apierror.Unauthorized("token_invalid"))
 			return
 		}
 
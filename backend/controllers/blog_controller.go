@@ -1,70 +1,65 @@
 package controllers
 
 import (
+	"backend/apierror"
 	"backend/cache"
-	"backend/config"
+	"backend/middleware"
+	"backend/models"
+	"backend/repository"
+	"backend/webhook"
+	"context"
+	"errors"
 	"net/http"
-	"strings" // Added for string manipulation in path generation
-	"time"    // Added for time.Time in Blog struct
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Define the Blog struct here as it's used by multiple functions in this package.
-// The original code used `models.Blog`, but the instruction provides a new struct definition.
-type Blog struct {
-	ID              int       `json:"id"`
-	Title           string    `json:"title"`
-	Summary         string    `json:"summary"`
-	Content         string    `json:"content"`
-	Path            string    `json:"path"`
-	MetaTitle       string    `json:"meta_title"`
-	MetaDescription string    `json:"meta_description"`
-	MetaKeywords    string    `json:"meta_keywords"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-}
-
-// 获取所有博客
+// 获取所有博客（按 ?lang= 或 Accept-Language 返回译文，缺失时回退原文）
+// 置顶的在前，其次按 sort_order、创建时间排序；?featured=true 只返回推荐内容
 func GetBlogs(c *gin.Context) {
-	rows, err := config.DB.Query("SELECT id, title, summary, content, path, COALESCE(meta_title, ''), COALESCE(meta_description, ''), COALESCE(meta_keywords, ''), created_at, updated_at FROM blogs ORDER BY created_at DESC")
+	blogs, err := repos.Blogs.List(c.Request.Context(), contentFilter(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, apierror.Internal(err))
 		return
 	}
-	defer rows.Close()
-
-	var blogs []Blog
-	for rows.Next() {
-		var blog Blog
-		if err := rows.Scan(&blog.ID, &blog.Title, &blog.Summary, &blog.Content, &blog.Path, &blog.MetaTitle, &blog.MetaDescription, &blog.MetaKeywords, &blog.CreatedAt, &blog.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		blogs = append(blogs, blog)
+	if err := localizeBlogs(c.Request.Context(), blogs, contentLocale(c)); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
 	}
 
+	setLastModified(c, updatedAts(blogs, func(v models.Blog) time.Time { return v.UpdatedAt })...)
 	c.JSON(http.StatusOK, blogs)
 }
 
-// 获取单个博客
+// 获取单个博客（语言选择同 GetBlogs，并返回 hreflang alternates）
+// ?include=related 时附上相关博客和上一篇/下一篇
 func GetBlog(c *gin.Context) {
-	slug := c.Param("slug")
-	var blog Blog
-	err := config.DB.QueryRow("SELECT id, title, summary, content, path, COALESCE(meta_title, ''), COALESCE(meta_description, ''), COALESCE(meta_keywords, ''), created_at, updated_at FROM blogs WHERE path = ? OR id = ?", slug, slug).Scan(&blog.ID, &blog.Title, &blog.Summary, &blog.Content, &blog.Path, &blog.MetaTitle, &blog.MetaDescription, &blog.MetaKeywords, &blog.CreatedAt, &blog.UpdatedAt)
+	blog, err := repos.Blogs.Get(c.Request.Context(), c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+	if err := localizeBlog(c.Request.Context(), &blog, contentLocale(c)); err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+	times, ok := includeRelatedBlog(c, &blog)
+	if !ok {
 		return
 	}
 
+	setLastModified(c, append(times, blog.UpdatedAt)...)
+	middleware.AddCacheTags(c, cache.EntityTag(cache.TagBlogs, blog.ID))
 	c.JSON(http.StatusOK, blog)
 }
 
 // 创建博客（需要管理员权限）
 func CreateBlog(c *gin.Context) {
-	var blog Blog
+	var blog models.Blog
 	if err := c.ShouldBindJSON(&blog); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, apierror.Bind(err))
 		return
 	}
 
@@ -72,74 +67,127 @@ func CreateBlog(c *gin.Context) {
 	if blog.Path == "" {
 		blog.Path = strings.ToLower(strings.ReplaceAll(blog.Title, " ", "-"))
 	}
+	if err := checkCategories(c.Request.Context(), blog.CategoryIDs); err != nil {
+		respondError(c, err)
+		return
+	}
 
-	result, err := config.DB.Exec("INSERT INTO blogs (title, summary, content, path, meta_title, meta_description, meta_keywords) VALUES (?, ?, ?, ?, ?, ?, ?)",
-		blog.Title, blog.Summary, blog.Content, blog.Path, blog.MetaTitle, blog.MetaDescription, blog.MetaKeywords)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := repos.Blogs.Create(c.Request.Context(), &blog); err != nil {
+		respondError(c, repoError(err, "blog"))
 		return
 	}
 
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:/api/blogs*")
+	updateRelated(c.Request.Context(), refreshRelatedBlogs)
+	cache.Invalidate(c.Request.Context(), cache.TagBlogs)
 
-	id, _ := result.LastInsertId()
-	blog.ID = int(id)
+	webhook.Dispatch(c.Request.Context(), repos.Webhooks, webhook.EventBlogPublished, blog)
 	c.JSON(http.StatusCreated, blog)
 }
 
 // 更新博客（需要管理员权限）
 func UpdateBlog(c *gin.Context) {
-	id := c.Param("id")
-	var blog Blog
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	var blog models.Blog
 	if err := c.ShouldBindJSON(&blog); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, apierror.Bind(err))
 		return
 	}
+	blog.ID = id
 
-	// If path is empty, generate from title (if title is provided)
-	if blog.Path == "" && blog.Title != "" {
+	// Generate path from title if empty
+	if blog.Path == "" {
 		blog.Path = strings.ToLower(strings.ReplaceAll(blog.Title, " ", "-"))
 	}
+	if err := checkCategories(c.Request.Context(), blog.CategoryIDs); err != nil {
+		respondError(c, err)
+		return
+	}
 
-	_, err := config.DB.Exec("UPDATE blogs SET title=?, summary=?, content=?, path=?, meta_title=?, meta_description=?, meta_keywords=?, updated_at=CURRENT_TIMESTAMP WHERE id=?",
-		blog.Title, blog.Summary, blog.Content, blog.Path, blog.MetaTitle, blog.MetaDescription, blog.MetaKeywords, id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := repos.Blogs.Update(c.Request.Context(), &blog); err != nil {
+		respondError(c, repoError(err, "blog"))
 		return
 	}
 
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:/api/blogs*")
+	updateRelated(c.Request.Context(), refreshRelatedBlogs)
+	cache.Invalidate(c.Request.Context(), cache.TagBlogs, id)
+
+	webhook.Dispatch(c.Request.Context(), repos.Webhooks, webhook.EventBlogUpdated, blog)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Blog updated successfully"})
 }
 
 // 删除博客（需要管理员权限）
 func DeleteBlog(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
 
-	_, err := config.DB.Exec("DELETE FROM blogs WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+	if err := repos.Blogs.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, apierror.Internal(err))
 		return
 	}
 
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:/api/blogs*")
+	updateRelated(c.Request.Context(), refreshRelatedBlogs)
+	cache.Invalidate(c.Request.Context(), cache.TagBlogs, id)
+	cache.PurgeTags(c.Request.Context(), cache.EntityTag(cache.TagComments, id))
+
+	webhook.Dispatch(c.Request.Context(), repos.Webhooks, webhook.EventBlogDeleted, gin.H{"id": id})
 
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
 
-// 根据路径获取博客
-func GetBlogByPath(c *gin.Context) {
-	path := c.Param("path")
+// ReorderBlogs 批量调整博客排序（需要管理员权限），所有位置在同一事务中更新
+// 请求体：{"items": [{"id": 3, "sort_order": 0}, {"id": 1, "sort_order": 1}]}
+func ReorderBlogs(c *gin.Context) {
+	positions, ok := bindReorder(c)
+	if !ok {
+		return
+	}
 
-	var blog Blog
-	err := config.DB.QueryRow("SELECT id, title, summary, content, path, COALESCE(meta_title, ''), COALESCE(meta_description, ''), COALESCE(meta_keywords, ''), created_at, updated_at FROM blogs WHERE path = ?", path).
-		Scan(&blog.ID, &blog.Title, &blog.Summary, &blog.Content, &blog.Path, &blog.MetaTitle, &blog.MetaDescription, &blog.MetaKeywords, &blog.CreatedAt, &blog.UpdatedAt)
+	if err := repos.Blogs.Reorder(c.Request.Context(), positions); err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
 
+	cache.Invalidate(c.Request.Context(), cache.TagBlogs, positionIDs(positions)...)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order updated successfully"})
+}
+
+// 根据路径获取博客（参数同 GetBlog）
+func GetBlogByPath(c *gin.Context) {
+	blog, err := repos.Blogs.GetByPath(c.Request.Context(), c.Param("path"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+	if err := localizeBlog(c.Request.Context(), &blog, contentLocale(c)); err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+	times, ok := includeRelatedBlog(c, &blog)
+	if !ok {
 		return
 	}
 
+	setLastModified(c, append(times, blog.UpdatedAt)...)
+	middleware.AddCacheTags(c, cache.EntityTag(cache.TagBlogs, blog.ID))
 	c.JSON(http.StatusOK, blog)
 }
+
+// checkCategories 确认博客引用的分类都存在
+func checkCategories(ctx context.Context, ids []int) error {
+	for _, id := range ids {
+		if _, err := repos.Categories.Get(ctx, id); errors.Is(err, repository.ErrNotFound) {
+			return apierror.Validation(apierror.Field("category_ids", "invalid"))
+		} else if err != nil {
+			return apierror.Internal(err)
+		}
+	}
+	return nil
+}
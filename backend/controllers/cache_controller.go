@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"backend/cache"
+	"backend/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCacheStatus 返回 Redis 连接与熔断状态，以及响应缓存当前使用的存储
+// cache.store 为空表示缓存已关闭；auto 模式下 Redis 不可用时为 memory
+func GetCacheStatus(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"redis": config.GetRedisStatus(),
+		"cache": gin.H{
+			"backend": config.Current.Cache.Backend,
+			"store":   cache.Backend(),
+		},
+	})
+}
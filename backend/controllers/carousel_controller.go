@@ -1,86 +1,102 @@
 package controllers
 
 import (
+	"backend/apierror"
 	"backend/cache"
-	"backend/config"
+	"backend/i18n"
+	"backend/middleware"
 	"backend/models"
-	"database/sql"
+	"backend/repository"
+	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetCarousels 获取轮播图（可按 position 过滤，按排序和创建时间倒序）
+// 只返回已启用、处于展示时间窗口内、语言匹配（?lang= 或 Accept-Language）的轮播图；
+// 缓存在下一个窗口边界（某张轮播图开始或结束展示）时过期，轮播图按时出现和消失
 func GetCarousels(c *gin.Context) {
-	position := c.Query("position")
-
-	var (
-		rows *sql.Rows
-		err  error
-	)
-
-	// 注意：SQL 里增加 rotation 字段，需确保数据库表 carousels 已有该列
-	if position != "" {
-		rows, err = config.DB.Query(`
-			SELECT id, title, image_url, alt_text, description, sort_order, position, rotation, image_width, image_height, created_at, updated_at
-			FROM carousels
-			WHERE position = ?
-			ORDER BY sort_order ASC, created_at DESC
-		`, position)
-	} else {
-		rows, err = config.DB.Query(`
-			SELECT id, title, image_url, alt_text, description, sort_order, position, rotation, image_width, image_height, created_at, updated_at
-			FROM carousels
-			ORDER BY sort_order ASC, created_at DESC
-		`)
-	}
+	carousels, err := repos.Carousels.List(c.Request.Context(), c.Query("position"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取轮播图失败"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
-	defer rows.Close()
-
-	var carousels []models.Carousel
-	for rows.Next() {
-		var item models.Carousel
-		if err := rows.Scan(
-			&item.ID,
-			&item.Title,
-			&item.ImageURL,
-			&item.AltText,
-			&item.Description,
-			&item.SortOrder,
-			&item.Position,
-			&item.Rotation,
-			&item.ImageWidth,
-			&item.ImageHeight,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		); err != nil {
+
+	now, locale := time.Now(), contentLocale(c)
+	visible := []models.Carousel{}
+	// Last-Modified 取可见轮播图的更新时间，以及已经过去的窗口边界：
+	// 轮播图按时出现或消失时内容变了，但 updated_at 不变
+	var modified []time.Time
+	var next time.Time
+	for _, carousel := range carousels {
+		if (carousel.Active != nil && !*carousel.Active) || (carousel.Locale != "" && carousel.Locale != locale) {
 			continue
 		}
-		carousels = append(carousels, item)
+		for _, boundary := range []*time.Time{carousel.StartsAt, carousel.EndsAt} {
+			switch {
+			case boundary == nil:
+			case boundary.After(now):
+				if next.IsZero() || boundary.Before(next) {
+					next = *boundary
+				}
+			default:
+				modified = append(modified, *boundary)
+			}
+		}
+		if carousel.Showing(now) {
+			visible = append(visible, carousel)
+			modified = append(modified, carousel.UpdatedAt)
+		}
 	}
-
-	if carousels == nil {
-		carousels = []models.Carousel{}
+	if !next.IsZero() {
+		middleware.ExpireCacheAt(c, next)
+	}
+	if err := resolveCTAs(c, visible); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
 	}
 
+	setLastModified(c, modified...)
+	c.JSON(http.StatusOK, visible)
+}
+
+// GetAllCarousels 获取全部轮播图（管理员），包括未启用、未到或已过展示时间、只面向某种语言的轮播图
+func GetAllCarousels(c *gin.Context) {
+	carousels, err := repos.Carousels.List(c.Request.Context(), c.Query("position"))
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	if err := resolveCTAs(c, carousels); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
 	c.JSON(http.StatusOK, carousels)
 }
 
-// CreateCarousel 创建轮播图（管理员）
-func CreateCarousel(c *gin.Context) {
+// bindCarousel 解析并规范化创建/更新轮播图的请求体；无效时返回 422 并中止
+func bindCarousel(c *gin.Context) (models.Carousel, bool) {
 	var payload models.Carousel
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的数据格式"})
-		return
+		respondError(c, apierror.Bind(err))
+		return models.Carousel{}, false
 	}
 
-	if payload.ImageURL == "" || payload.Title == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "标题和图片地址不能为空"})
-		return
+	// active 省略时视为启用
+	if payload.Active == nil {
+		active := true
+		payload.Active = &active
+	}
+
+	// 如果未传 position，则默认为顶部轮播图
+	if payload.Position == "" {
+		payload.Position = "top"
 	}
 
 	// 旋转角度防御性处理，仅允许 0/90/180/270
@@ -94,94 +110,181 @@ func CreateCarousel(c *gin.Context) {
 	payload.ImageWidth = normalizePx(payload.ImageWidth)
 	payload.ImageHeight = normalizePx(payload.ImageHeight)
 
-	// 默认位置为顶部轮播图
-	if payload.Position == "" {
-		payload.Position = "top"
+	// 展示时间窗口：统一存 UTC，结束时间必须晚于开始时间
+	for _, t := range []**time.Time{&payload.StartsAt, &payload.EndsAt} {
+		if *t != nil {
+			utc := (*t).UTC()
+			*t = &utc
+		}
+	}
+	if payload.StartsAt != nil && payload.EndsAt != nil && !payload.EndsAt.After(*payload.StartsAt) {
+		respondError(c, apierror.Validation(apierror.Field("ends_at", "gtfield", "starts_at")))
+		return models.Carousel{}, false
 	}
 
-	result, err := config.DB.Exec(
-		"INSERT INTO carousels (title, image_url, alt_text, description, sort_order, position, rotation, image_width, image_height) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		payload.Title,
-		payload.ImageURL,
-		payload.AltText,
-		payload.Description,
-		payload.SortOrder,
-		payload.Position,
-		payload.Rotation,
-		payload.ImageWidth,
-		payload.ImageHeight,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建失败: " + err.Error()})
-		return
+	// 目标语言：空表示所有语言，否则必须是支持的语言
+	if payload.Locale != "" {
+		locale := i18n.Normalize(payload.Locale)
+		if locale == "" {
+			respondError(c, apierror.Validation(apierror.Field("locale", "oneof", strings.Join(i18n.Supported, " "))))
+			return models.Carousel{}, false
+		}
+		payload.Locale = locale
 	}
 
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:/api/carousels*")
+	if err := checkCTA(c.Request.Context(), &payload); err != nil {
+		respondError(c, err)
+		return models.Carousel{}, false
+	}
 
-	id, _ := result.LastInsertId()
-	payload.ID = int(id)
+	return payload, true
+}
 
-	c.JSON(http.StatusCreated, payload)
+// checkCTA 校验按钮跳转目标、清掉与类型无关的字段并填好 cta_href：url 类型必须是 http(s) 链接或以 / 开头的站内路径，
+// blog / solution 类型引用的内容必须存在；有按钮文字时必须指定类型
+func checkCTA(ctx context.Context, payload *models.Carousel) error {
+	payload.CTALabel = strings.TrimSpace(payload.CTALabel)
+	switch payload.CTAType {
+	case "":
+		if payload.CTALabel != "" {
+			return apierror.Validation(apierror.Field("cta_type", "required"))
+		}
+		payload.CTAURL, payload.CTARefID, payload.CTAHref = "", 0, ""
+	case models.CTATargetURL:
+		payload.CTARefID = 0
+		payload.CTAURL = strings.TrimSpace(payload.CTAURL)
+		if !validCTAURL(payload.CTAURL) {
+			return apierror.Validation(apierror.Field("cta_url", "url"))
+		}
+		payload.CTAHref = payload.CTAURL
+	case models.CTATargetBlog, models.CTATargetSolution:
+		payload.CTAURL = ""
+		if payload.CTARefID <= 0 {
+			return apierror.Validation(apierror.Field("cta_ref_id", "required"))
+		}
+		path, err := ctaPath(ctx, payload.CTAType, payload.CTARefID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierror.Validation(apierror.Field("cta_ref_id", "invalid"))
+		}
+		if err != nil {
+			return apierror.Internal(err)
+		}
+		payload.CTAHref = contentHref(payload.CTAType, path)
+	}
+	return nil
 }
 
-// UpdateCarousel 更新轮播图（管理员）
-func UpdateCarousel(c *gin.Context) {
-	id := c.Param("id")
+// validCTAURL 允许 http(s) 绝对地址和以 / 开头的站内路径（不允许 //host 这种协议相对地址）
+func validCTAURL(v string) bool {
+	if strings.HasPrefix(v, "/") {
+		return !strings.HasPrefix(v, "//")
+	}
+	u, err := url.Parse(v)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
 
-	var payload models.Carousel
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的数据格式"})
+// ctaPath 取按钮引用的博客或解决方案当前的路径（未设置路径时用 ID）
+func ctaPath(ctx context.Context, kind string, id int) (string, error) {
+	var path string
+	switch kind {
+	case models.CTATargetBlog:
+		blog, err := repos.Blogs.Get(ctx, strconv.Itoa(id))
+		if err != nil {
+			return "", err
+		}
+		path = blog.Path
+	case models.CTATargetSolution:
+		solution, err := repos.Solutions.Get(ctx, strconv.Itoa(id))
+		if err != nil {
+			return "", err
+		}
+		path = solution.Path
+	}
+	if path == "" {
+		path = strconv.Itoa(id)
+	}
+	return path, nil
+}
+
+// ctaHref 按钮的跳转地址：url 类型原样返回；blog / solution 类型取引用内容当前的路径，
+// 对应前端 /blog/[slug]、/solution/[slug] 页面；引用的内容已被删除时返回空
+func ctaHref(ctx context.Context, carousel models.Carousel) (string, error) {
+	switch carousel.CTAType {
+	case models.CTATargetURL:
+		return carousel.CTAURL, nil
+	case models.CTATargetBlog, models.CTATargetSolution:
+		path, err := ctaPath(ctx, carousel.CTAType, carousel.CTARefID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return contentHref(carousel.CTAType, path), nil
+	}
+	return "", nil
+}
+
+// contentHref 博客或解决方案的前端页面地址
+func contentHref(kind, path string) string {
+	return "/" + kind + "/" + url.PathEscape(path)
+}
+
+// resolveCTAs 在读取时解析每张轮播图的 cta_href，内容改了路径按钮也不会失效；
+// 响应打上被引用内容的缓存标签，内容修改或删除时一起清理
+func resolveCTAs(c *gin.Context, carousels []models.Carousel) error {
+	for i := range carousels {
+		switch carousels[i].CTAType {
+		case models.CTATargetBlog:
+			middleware.AddCacheTags(c, cache.EntityTag(cache.TagBlogs, carousels[i].CTARefID))
+		case models.CTATargetSolution:
+			middleware.AddCacheTags(c, cache.EntityTag(cache.TagSolutions, carousels[i].CTARefID))
+		}
+		href, err := ctaHref(c.Request.Context(), carousels[i])
+		if err != nil {
+			return err
+		}
+		carousels[i].CTAHref = href
+	}
+	return nil
+}
+
+// CreateCarousel 创建轮播图（管理员）
+func CreateCarousel(c *gin.Context) {
+	payload, ok := bindCarousel(c)
+	if !ok {
 		return
 	}
 
-	if payload.ImageURL == "" || payload.Title == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "标题和图片地址不能为空"})
+	if err := repos.Carousels.Create(c.Request.Context(), &payload); err != nil {
+		respondError(c, repoError(err, "carousel"))
 		return
 	}
 
-	// 如果未传 position，则默认仍为顶部轮播图
-	position := payload.Position
-	if position == "" {
-		position = "top"
+	cache.Invalidate(c.Request.Context(), cache.TagCarousels)
+
+	c.JSON(http.StatusCreated, payload)
+}
+
+// UpdateCarousel 更新轮播图（管理员）
+func UpdateCarousel(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
 	}
 
-	// 旋转角度防御性处理，仅允许 0/90/180/270
-	rotation := payload.Rotation
-	switch rotation {
-	case 0, 90, 180, 270:
-	default:
-		rotation = 0
-	}
-
-	imageWidth := normalizePx(payload.ImageWidth)
-	imageHeight := normalizePx(payload.ImageHeight)
-
-	_, err := config.DB.Exec(
-		"UPDATE carousels SET title = ?, image_url = ?, alt_text = ?, description = ?, sort_order = ?, position = ?, rotation = ?, image_width = ?, image_height = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
-		payload.Title,
-		payload.ImageURL,
-		payload.AltText,
-		payload.Description,
-		payload.SortOrder,
-		position,
-		rotation,
-		imageWidth,
-		imageHeight,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新失败: " + err.Error()})
+	payload, ok := bindCarousel(c)
+	if !ok {
 		return
 	}
+	payload.ID = id
 
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:/api/carousels*")
+	if err := repos.Carousels.Update(c.Request.Context(), &payload); err != nil {
+		respondError(c, repoError(err, "carousel"))
+		return
+	}
 
-	idInt, _ := strconv.Atoi(id)
-	payload.ID = idInt
-	payload.Position = position
-	payload.Rotation = rotation
-	payload.ImageWidth = imageWidth
-	payload.ImageHeight = imageHeight
+	cache.Invalidate(c.Request.Context(), cache.TagCarousels, id)
 
 	c.JSON(http.StatusOK, payload)
 }
@@ -202,15 +305,17 @@ func normalizePx(v int) int {
 
 // DeleteCarousel 删除轮播图（管理员）
 func DeleteCarousel(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
 
-	_, err := config.DB.Exec("DELETE FROM carousels WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败"})
+	if err := repos.Carousels.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, apierror.Internal(err))
 		return
 	}
 
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:/api/carousels*")
+	cache.Invalidate(c.Request.Context(), cache.TagCarousels, id)
 
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"backend/antispam"
+	"backend/apierror"
+	"backend/cache"
+	"backend/config"
+	"backend/logger"
+	"backend/middleware"
+	"backend/models"
+	"backend/repository"
+	"backend/webhook"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// commentRequest 发表评论；parent_id 为回复的评论，notify_replies 表示有人回复时通知作者
+type commentRequest struct {
+	Author        string `json:"author" binding:"required,max=100"`
+	Email         string `json:"email" binding:"required,email,max=254"`
+	Content       string `json:"content" binding:"required,max=5000"`
+	ParentID      int    `json:"parent_id"`
+	NotifyReplies bool   `json:"notify_replies"`
+	FormToken     string `json:"form_token"` // 同联系表单，先调用 GET /api/contact/token 获取
+	Website       string `json:"website"`    // 蜜罐字段，正常用户不可见，应始终为空
+}
+
+// GetBlogComments 获取博客已审核通过的评论（公开接口），按楼层嵌套，每层按时间先后排序
+// :id 可以是博客 ID 或路径；不返回邮箱。上级评论未通过审核时，其下的回复也不显示
+func GetBlogComments(c *gin.Context) {
+	ctx := c.Request.Context()
+	blog, err := repos.Blogs.Get(ctx, c.Param("id"))
+	if err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+	comments, err := repos.Comments.List(ctx, repository.CommentFilter{BlogID: blog.ID, Status: models.CommentApproved})
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	// List 按时间倒序返回，楼层内按时间先后显示
+	slices.Reverse(comments)
+	children := map[int][]models.Comment{}
+	for _, comment := range comments {
+		comment.Email, comment.NotifyReplies, comment.SpamReason = "", false, ""
+		children[comment.ParentID] = append(children[comment.ParentID], comment)
+	}
+	var thread func(parentID int) []models.Comment
+	thread = func(parentID int) []models.Comment {
+		replies := children[parentID]
+		for i := range replies {
+			replies[i].Replies = thread(replies[i].ID)
+		}
+		return replies
+	}
+	tree := thread(0)
+	if tree == nil {
+		tree = []models.Comment{}
+	}
+
+	setLastModified(c, updatedAts(comments, func(v models.Comment) time.Time { return v.UpdatedAt })...)
+	middleware.AddCacheTags(c, cache.EntityTag(cache.TagComments, blog.ID))
+	c.JSON(http.StatusOK, tree)
+}
+
+// CreateBlogComment 发表评论（公开接口，按 IP 限流）
+// 默认进入待审核状态；命中反垃圾规则的评论标记为 spam，不会公开
+func CreateBlogComment(c *gin.Context) {
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+
+	// 蜜罐被填写：假装成功，不入库
+	if req.Website != "" {
+		c.JSON(http.StatusCreated, gin.H{"message": "Comment submitted successfully", "status": models.CommentPending})
+		return
+	}
+	if err := antispam.VerifyToken(req.FormToken, time.Now()); err != nil {
+		code := "invalid_form_token"
+		switch {
+		case errors.Is(err, antispam.ErrTooFast):
+			code = "form_too_fast"
+		case errors.Is(err, antispam.ErrTokenExpired):
+			code = "form_token_expired"
+		}
+		respondError(c, apierror.New(http.StatusBadRequest, code))
+		return
+	}
+
+	req.Author = strings.TrimSpace(req.Author)
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Author == "" {
+		respondError(c, apierror.Validation(apierror.Field("author", "required")))
+		return
+	}
+	if req.Content == "" {
+		respondError(c, apierror.Validation(apierror.Field("content", "required")))
+		return
+	}
+
+	ctx := c.Request.Context()
+	blog, err := repos.Blogs.Get(ctx, c.Param("id"))
+	if err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+	if req.ParentID != 0 {
+		if err := checkParent(ctx, blog.ID, req.ParentID); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	comment := models.Comment{
+		BlogID:        blog.ID,
+		ParentID:      req.ParentID,
+		Author:        req.Author,
+		Email:         strings.TrimSpace(req.Email),
+		Content:       req.Content,
+		Status:        models.CommentPending,
+		NotifyReplies: req.NotifyReplies,
+	}
+	if reason := antispam.Classify(req.Author, req.Content); reason != "" {
+		comment.Status, comment.SpamReason = models.CommentSpam, reason
+	} else if !config.Current.Comments.RequireApproval {
+		comment.Status = models.CommentApproved
+	}
+	if err := repos.Comments.Create(ctx, &comment); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	if comment.Status != models.CommentSpam {
+		// 垃圾评论不推送，避免打扰下游
+		webhook.Dispatch(ctx, repos.Webhooks, webhook.EventCommentCreated, gin.H{
+			"id":         comment.ID,
+			"blog_id":    blog.ID,
+			"blog_title": blog.Title,
+			"blog_path":  blog.Path,
+			"parent_id":  comment.ParentID,
+			"author":     comment.Author,
+			"email":      comment.Email,
+			"content":    comment.Content,
+			"status":     comment.Status,
+		})
+	}
+	if comment.Status == models.CommentApproved {
+		cache.InvalidateComments(ctx, blog.ID)
+		notifyReply(ctx, comment)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment submitted successfully", "status": comment.Status})
+}
+
+// checkParent 校验被回复的评论：必须是同一篇博客下已公开的评论，且回复后不超过 COMMENTS_MAX_DEPTH 层
+func checkParent(ctx context.Context, blogID, parentID int) error {
+	invalid := apierror.Validation(apierror.Field("parent_id", "invalid"))
+	parent, err := repos.Comments.Get(ctx, parentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return invalid
+	}
+	if err != nil {
+		return apierror.Internal(err)
+	}
+	if parent.BlogID != blogID || parent.Status != models.CommentApproved {
+		return invalid
+	}
+
+	// 顶层评论是第 1 层，新回复比上级深一层
+	depth := 2
+	for parent.ParentID != 0 {
+		if depth++; depth > config.Current.Comments.MaxDepth {
+			break
+		}
+		if parent, err = repos.Comments.Get(ctx, parent.ParentID); err != nil {
+			return repoError(err, "comment")
+		}
+	}
+	if depth > config.Current.Comments.MaxDepth {
+		return invalid
+	}
+	return nil
+}
+
+// notifyReply 回复公开后推送 comment.replied，供下游给上级评论的作者发邮件
+// 只在第一次公开时推送，撤回后再审核通过不会重复通知；
+// 只有上级作者勾选了 notify_replies 才推送；回复自己的评论不推送
+func notifyReply(ctx context.Context, reply models.Comment) {
+	first, err := repos.Comments.MarkNotified(ctx, reply.ID)
+	if err != nil {
+		logger.FromContext(ctx).Warn("reply notification skipped", "comment_id", reply.ID, "error", err)
+		return
+	}
+	if !first || reply.ParentID == 0 {
+		return
+	}
+	parent, err := repos.Comments.Get(ctx, reply.ParentID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			logger.FromContext(ctx).Warn("reply notification skipped", "comment_id", reply.ID, "error", err)
+		}
+		return
+	}
+	if !parent.NotifyReplies || strings.EqualFold(parent.Email, reply.Email) {
+		return
+	}
+	blog, err := repos.Blogs.Get(ctx, strconv.Itoa(reply.BlogID))
+	if err != nil {
+		logger.FromContext(ctx).Warn("reply notification skipped", "comment_id", reply.ID, "error", err)
+		return
+	}
+
+	webhook.Dispatch(ctx, repos.Webhooks, webhook.EventCommentReplied, gin.H{
+		"id":         reply.ID,
+		"blog_id":    blog.ID,
+		"blog_title": blog.Title,
+		"blog_path":  blog.Path,
+		"author":     reply.Author,
+		"content":    reply.Content,
+		"parent": gin.H{
+			"id":     parent.ID,
+			"author": parent.Author,
+			"email":  parent.Email,
+		},
+	})
+}
+
+// GetComments 获取评论（管理员），最新的在前；?status=pending|approved|spam、?blog_id= 筛选
+func GetComments(c *gin.Context) {
+	var f repository.CommentFilter
+	switch f.Status = c.Query("status"); f.Status {
+	case "", models.CommentPending, models.CommentApproved, models.CommentSpam:
+	default:
+		respondError(c, apierror.Validation(apierror.Field("status", "oneof", "pending approved spam")))
+		return
+	}
+	if v := c.Query("blog_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, apierror.Validation(apierror.Field("blog_id", "number")))
+			return
+		}
+		f.BlogID = id
+	}
+
+	ctx := c.Request.Context()
+	comments, err := repos.Comments.List(ctx, f)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	blogs, err := repos.Blogs.List(ctx, repository.ContentFilter{})
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	titles := make(map[int]string, len(blogs))
+	for _, b := range blogs {
+		titles[b.ID] = b.Title
+	}
+	for i := range comments {
+		comments[i].BlogTitle = titles[comments[i].BlogID]
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// UpdateCommentStatus 审核评论（管理员），请求体：{"status": "approved"}
+// 评论公开与否发生变化时清理该博客的评论和博客列表缓存（评论数）；回复首次公开时推送 comment.replied
+func UpdateCommentStatus(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Status string `json:"status" binding:"required,oneof=pending approved spam"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	comment, err := repos.Comments.Get(ctx, id)
+	if err != nil {
+		respondError(c, repoError(err, "comment"))
+		return
+	}
+	if err := repos.Comments.SetStatus(ctx, id, req.Status); err != nil {
+		respondError(c, repoError(err, "comment"))
+		return
+	}
+
+	wasApproved := comment.Status == models.CommentApproved
+	comment.Status = req.Status
+	if wasApproved != (req.Status == models.CommentApproved) {
+		cache.InvalidateComments(ctx, comment.BlogID)
+		if !wasApproved {
+			notifyReply(ctx, comment)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment updated successfully"})
+}
+
+// DeleteComment 删除评论及其下所有回复（管理员）
+func DeleteComment(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	comment, err := repos.Comments.Get(ctx, id)
+	if err != nil {
+		respondError(c, repoError(err, "comment"))
+		return
+	}
+	if err := repos.Comments.Delete(ctx, id); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	cache.InvalidateComments(ctx, comment.BlogID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
@@ -1,32 +1,111 @@
 package controllers
 
 import (
-	"backend/config"
+	"backend/antispam"
+	"backend/apierror"
+	"backend/models"
+	"backend/repository"
+	"backend/webhook"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// GetContactToken 获取联系表单令牌（记录表单打开时间，用于最短填写时间校验）
+func GetContactToken(c *gin.Context) {
+	// 令牌带时间戳，不能被响应缓存复用
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"token": antispam.IssueToken(time.Now())})
+}
+
 // CreateContact 创建联系请求
 func CreateContact(c *gin.Context) {
 	var req struct {
-		Name    string `json:"name" binding:"required"`
-		Email   string `json:"email" binding:"required"`
-		Subject string `json:"subject" binding:"required"`
-		Message string `json:"message" binding:"required"`
+		Name      string `json:"name" binding:"required"`
+		Email     string `json:"email" binding:"required"`
+		Subject   string `json:"subject" binding:"required"`
+		Message   string `json:"message" binding:"required"`
+		FormToken string `json:"form_token"`
+		Website   string `json:"website"` // 蜜罐字段，正常用户不可见，应始终为空
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		antispam.RecordRejected(antispam.ReasonInvalidInput)
+		respondError(c, apierror.Bind(err))
 		return
 	}
 
-	_, err := config.DB.Exec("INSERT INTO contacts (name, email, subject, message) VALUES (?, ?, ?, ?)", req.Name, req.Email, req.Subject, req.Message)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit contact form"})
+	// 蜜罐被填写：假装成功，不入库，避免机器人据此调整策略
+	if req.Website != "" {
+		antispam.RecordRejected(antispam.ReasonHoneypot)
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Contact form submitted successfully",
+			"status":  "success",
+		})
+		return
+	}
+
+	if err := antispam.VerifyToken(req.FormToken, time.Now()); err != nil {
+		code := "invalid_form_token"
+		switch {
+		case errors.Is(err, antispam.ErrTooFast):
+			antispam.RecordRejected(antispam.ReasonTooFast)
+			code = "form_too_fast"
+		case errors.Is(err, antispam.ErrTokenExpired):
+			antispam.RecordRejected(antispam.ReasonInvalidToken)
+			code = "form_token_expired"
+		default:
+			antispam.RecordRejected(antispam.ReasonInvalidToken)
+		}
+		respondError(c, apierror.New(http.StatusBadRequest, code))
 		return
 	}
 
+	req.Name = strings.TrimSpace(req.Name)
+	req.Email = strings.TrimSpace(req.Email)
+	req.Subject = strings.TrimSpace(req.Subject)
+	if err := antispam.ValidateFields(req.Name, req.Email, req.Subject, req.Message); err != nil {
+		antispam.RecordRejected(antispam.ReasonInvalidInput)
+		var fe *antispam.FieldError
+		if errors.As(err, &fe) {
+			respondError(c, apierror.Validation(apierror.Field(fe.Field, fe.Rule, fe.Param)))
+			return
+		}
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	// 命中垃圾信息规则的留言仍然入库，只做标记
+	spamReason := antispam.Classify(req.Subject, req.Message)
+
+	contact := models.Contact{
+		Name:       req.Name,
+		Email:      req.Email,
+		Subject:    req.Subject,
+		Message:    req.Message,
+		IsSpam:     spamReason != "",
+		SpamReason: spamReason,
+	}
+	if err := repos.Contacts.Create(c.Request.Context(), &contact); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	if contact.IsSpam {
+		antispam.RecordFlagged()
+	} else {
+		// 被标记为垃圾信息的留言不推送，避免打扰下游（Slack/CRM 等）
+		webhook.Dispatch(c.Request.Context(), repos.Webhooks, webhook.EventContactCreated, gin.H{
+			"id":      contact.ID,
+			"name":    contact.Name,
+			"email":   contact.Email,
+			"subject": contact.Subject,
+			"message": contact.Message,
+		})
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Contact form submitted successfully",
 		"status":  "success",
@@ -35,30 +114,21 @@ func CreateContact(c *gin.Context) {
 
 // GetContacts 获取所有联系请求（管理员用）
 func GetContacts(c *gin.Context) {
-	rows, err := config.DB.Query("SELECT id, name, email, subject, message, created_at, updated_at FROM contacts ORDER BY created_at DESC")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取联系消息失败"})
-		return
+	var filter repository.ContactFilter
+	// ?spam=1 只看垃圾信息，?spam=0 只看正常留言
+	switch c.Query("spam") {
+	case "1", "true":
+		spam := true
+		filter.Spam = &spam
+	case "0", "false":
+		spam := false
+		filter.Spam = &spam
 	}
-	defer rows.Close()
-
-	var contacts []map[string]interface{}
-	for rows.Next() {
-		var id int
-		var name, email, subject, message, createdAt, updatedAt string
-		if err := rows.Scan(&id, &name, &email, &subject, &message, &createdAt, &updatedAt); err != nil {
-			continue
-		}
 
-		contacts = append(contacts, map[string]interface{}{
-			"id":         id,
-			"name":       name,
-			"email":      email,
-			"subject":    subject,
-			"message":    message,
-			"created_at": createdAt,
-			"updated_at": updatedAt,
-		})
+	contacts, err := repos.Contacts.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
 	}
 
 	c.JSON(http.StatusOK, contacts)
@@ -66,13 +136,20 @@ func GetContacts(c *gin.Context) {
 
 // DeleteContact 删除联系请求（管理员用）
 func DeleteContact(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
 
-	_, err := config.DB.Exec("DELETE FROM contacts WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete contact"})
+	if err := repos.Contacts.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, apierror.Internal(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Contact deleted successfully"})
-}
\ No newline at end of file
+}
+
+// GetContactStats 获取联系表单反垃圾统计（管理员用）
+func GetContactStats(c *gin.Context) {
+	c.JSON(http.StatusOK, antispam.Snapshot())
+}
@@ -3,11 +3,15 @@ package controllers
 import (
 	"archive/tar"
 	"archive/zip"
+	"backend/apierror"
 	"backend/cache"
 	"backend/config"
+	"backend/metrics"
 	"backend/middleware"
+	"backend/webhook"
 	"bytes"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,13 +21,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const maxRestoreUploadBytes = 300 << 20 // 300MB
 
 func BackupDatabase(c *gin.Context) {
+	defer observeDuration(c, metrics.BackupDuration, time.Now())
+
 	if config.DBPath == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "DB_PATH not configured"})
+		respondError(c, apierror.Internal(errors.New("DB_PATH not configured")))
 		return
 	}
 
@@ -32,7 +39,7 @@ func BackupDatabase(c *gin.Context) {
 
 	f, err := os.Open(config.DBPath)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open database"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 	defer f.Close()
@@ -46,13 +53,25 @@ func BackupDatabase(c *gin.Context) {
 
 	if _, err := io.Copy(gz, f); err != nil {
 		// response already started; just stop
+		c.Error(err)
 		return
 	}
 }
 
+// observeDuration 按响应结果记录备份/恢复耗时
+func observeDuration(c *gin.Context, h *prometheus.HistogramVec, start time.Time) {
+	result := "success"
+	if c.Writer.Status() >= http.StatusBadRequest || len(c.Errors) > 0 {
+		result = "error"
+	}
+	h.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
 func RestoreDatabase(c *gin.Context) {
+	defer observeDuration(c, metrics.RestoreDuration, time.Now())
+
 	if config.DBPath == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "DB_PATH not configured"})
+		respondError(c, apierror.Internal(errors.New("DB_PATH not configured")))
 		return
 	}
 
@@ -60,7 +79,7 @@ func RestoreDatabase(c *gin.Context) {
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing upload file (field name: file)"})
+		respondError(c, apierror.Validation(apierror.Field("file", "required")))
 		return
 	}
 	defer file.Close()
@@ -68,7 +87,7 @@ func RestoreDatabase(c *gin.Context) {
 	// Write upload to temp file
 	uploadTmp, err := os.CreateTemp("", "db-upload-*")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp file"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 	uploadTmpPath := uploadTmp.Name()
@@ -76,20 +95,22 @@ func RestoreDatabase(c *gin.Context) {
 
 	if _, err := io.Copy(uploadTmp, file); err != nil {
 		uploadTmp.Close()
-		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read upload"})
+		e := apierror.BadRequest("upload_failed")
+		e.Err = err
+		respondError(c, e)
 		return
 	}
 	_ = uploadTmp.Close()
 
 	extractedPath, err := extractDatabaseFile(uploadTmpPath, header.Filename)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, apierror.BadRequest("invalid_backup", err.Error()))
 		return
 	}
 	defer os.Remove(extractedPath)
 
 	if err := validateSQLiteFile(extractedPath); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, apierror.BadRequest("invalid_backup", err.Error()))
 		return
 	}
 
@@ -108,12 +129,12 @@ func RestoreDatabase(c *gin.Context) {
 	// Atomic replace: write to tmp in same dir then rename
 	dir := filepath.Dir(config.DBPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to prepare db directory"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 	tmpDst, err := os.CreateTemp(dir, "data.db.restore-*")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create temp db"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 	tmpDstPath := tmpDst.Name()
@@ -121,22 +142,26 @@ func RestoreDatabase(c *gin.Context) {
 	if err := copyReaderToFile(extractedPath, tmpDst); err != nil {
 		_ = tmpDst.Close()
 		_ = os.Remove(tmpDstPath)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write restored db"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 	_ = tmpDst.Close()
 
 	if err := os.Rename(tmpDstPath, config.DBPath); err != nil {
 		_ = os.Remove(tmpDstPath)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to replace db"})
+		respondError(c, apierror.Internal(err))
 		return
 	}
 
 	// Re-init DB (will run migrations)
 	config.InitDB()
+	updateRelated(c.Request.Context(), RefreshRelated)
 
 	// Clear cache
-	cache.PurgePatterns(c.Request.Context(), "cache:v1:GET:*")
+	cache.PurgeAll(c.Request.Context())
+
+	// 订阅来自恢复后的数据库
+	webhook.Dispatch(c.Request.Context(), repos.Webhooks, webhook.EventDBRestored, gin.H{"filename": header.Filename})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "database restored",
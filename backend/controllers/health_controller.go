@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"backend/cache"
+	"backend/config"
+	"backend/middleware"
+	"backend/version"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz 存活检查：进程能响应即返回 200
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪检查：数据库可用且已迁移、不在恢复数据库中；缓存后端为 redis 时还要求 Redis 可达且未熔断
+// 任一检查失败返回 503，checks 中给出各项结果；Redis 非必需（auto）时不可用只报告 degraded，缓存退回内存
+func Readyz(c *gin.Context) {
+	checks := gin.H{}
+	ready := true
+
+	fail := func(name, reason string) {
+		checks[name] = reason
+		ready = false
+	}
+
+	// 恢复数据库期间 RestoreDatabase 持有写锁，此时拿不到读锁
+	if !middleware.TryLockDBRead() {
+		fail("restore", "in progress")
+		fail("database", "unavailable during restore")
+	} else {
+		checks["restore"] = "ok"
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		switch {
+		case config.DB == nil:
+			fail("database", "not initialized")
+		case config.DB.PingContext(ctx) != nil:
+			fail("database", "ping failed")
+		case !config.MigrationsApplied():
+			fail("database", "migrations not applied")
+		default:
+			checks["database"] = "ok"
+		}
+		cancel()
+		middleware.UnlockDBRead()
+	}
+
+	// 只有强制使用 Redis 缓存时 Redis 不可用才算未就绪，auto 会退回内存缓存
+	redisDown := func(reason string) {
+		if config.Current.Cache.Backend == "redis" {
+			fail("redis", reason)
+		} else {
+			checks["redis"] = "degraded: " + reason
+		}
+	}
+	switch {
+	case !config.RedisConfigured():
+		checks["redis"] = "disabled"
+	case config.Redis == nil:
+		redisDown("unavailable")
+	default:
+		// 顺带更新后台健康检查的状态，Redis 恢复后无需等到下一次轮询
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second)
+		if err := config.PingRedis(ctx); err != nil {
+			redisDown("ping failed")
+		} else if config.GetRedisStatus().State == config.RedisCircuitOpen {
+			redisDown("circuit open")
+		} else {
+			checks["redis"] = "ok"
+		}
+		cancel()
+	}
+
+	// 响应缓存使用的存储（redis / memory），仅作说明，不影响就绪状态
+	if backend := cache.Backend(); backend != "" {
+		checks["cache"] = backend
+	} else {
+		checks["cache"] = "disabled"
+	}
+
+	status := http.StatusOK
+	body := gin.H{"status": "ready", "checks": checks}
+	if !ready {
+		status = http.StatusServiceUnavailable
+		body["status"] = "not ready"
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, body)
+}
+
+// Version 返回编译时嵌入的版本信息
+func Version(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"backend/apierror"
+	"backend/logger"
+	"backend/mailer"
+	"backend/middleware"
+	"backend/models"
+	"backend/newsletter"
+	"backend/repository"
+	"backend/webhook"
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 发送确认邮件最长等待时间
+const mailTimeout = 15 * time.Second
+
+// 订阅接口无论地址是否已订阅、是否在禁止发送列表中都返回同样的响应，避免被用来探测邮箱
+var subscribeAccepted = gin.H{"message": "Please check your inbox to confirm your subscription"}
+
+// normalizeEmail 邮箱统一去掉首尾空白并转小写后存储和比较
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Subscribe 订阅邮件（公开接口，按 IP 限流），请求体：{"email": "ada@example.com"}
+// 保存为待确认并发送带签名确认链接的邮件，返回 202；语言取 ?lang= 或 Accept-Language
+// 已确认的地址和禁止发送列表中的地址不发邮件，但响应相同
+func Subscribe(c *gin.Context) {
+	var req struct {
+		Email   string `json:"email" binding:"required,email,max=254"`
+		Website string `json:"website"` // 蜜罐字段，正常用户不可见，应始终为空
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	if req.Website != "" {
+		c.JSON(http.StatusAccepted, subscribeAccepted)
+		return
+	}
+
+	ctx := c.Request.Context()
+	email := normalizeEmail(req.Email)
+	suppressed, err := repos.Newsletter.IsSuppressed(ctx, email)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	if suppressed {
+		logger.FromContext(ctx).Info("newsletter subscribe ignored: address is suppressed")
+		c.JSON(http.StatusAccepted, subscribeAccepted)
+		return
+	}
+	sub, err := repos.Newsletter.GetSubscriber(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	if sub.Status == models.SubscriberConfirmed {
+		c.JSON(http.StatusAccepted, subscribeAccepted)
+		return
+	}
+
+	sub = models.Subscriber{Email: email, Status: models.SubscriberPending, Locale: contentLocale(c)}
+	if err := repos.Newsletter.SaveSubscriber(ctx, &sub); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	// 发信最多等待 mailTimeout，先释放数据库读锁，避免拖住数据库恢复
+	middleware.ReleaseDBRead(c)
+	mailCtx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+	if err := mailer.Send(mailCtx, newsletter.ConfirmationMessage(email, time.Now())); err != nil {
+		respondError(c, &apierror.Error{Status: http.StatusServiceUnavailable, Code: "mail_unavailable", Err: err})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, subscribeAccepted)
+}
+
+// newsletterToken 解析确认/退订请求中的令牌，返回其中的邮箱；无效或过期时返回 400 并中止
+func newsletterToken(c *gin.Context, purpose string) (string, bool) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return "", false
+	}
+	email, err := newsletter.Verify(req.Token, purpose, time.Now())
+	if err != nil {
+		code := "invalid_newsletter_token"
+		if errors.Is(err, newsletter.ErrTokenExpired) {
+			code = "newsletter_token_expired"
+		}
+		respondError(c, apierror.New(http.StatusBadRequest, code))
+		return "", false
+	}
+	return email, true
+}
+
+// ConfirmSubscription 确认订阅（公开接口），请求体：{"token": "..."}，令牌来自确认邮件中的链接
+// 由前端确认页面 POST 调用，避免邮件安全扫描器打开链接时误确认；重复确认不报错
+func ConfirmSubscription(c *gin.Context) {
+	email, ok := newsletterToken(c, newsletter.PurposeConfirm)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	suppressed, err := repos.Newsletter.IsSuppressed(ctx, email)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	if suppressed {
+		// 确认邮件发出后又退订了：旧链接不能让地址重新订阅
+		respondError(c, apierror.New(http.StatusConflict, "email_suppressed"))
+		return
+	}
+	sub, err := repos.Newsletter.GetSubscriber(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		sub = models.Subscriber{Email: email, Locale: contentLocale(c)}
+	} else if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	if sub.Status != models.SubscriberConfirmed {
+		now := time.Now().UTC()
+		sub.Status, sub.ConfirmedAt, sub.UnsubscribedAt = models.SubscriberConfirmed, &now, nil
+		if err := repos.Newsletter.SaveSubscriber(ctx, &sub); err != nil {
+			respondError(c, apierror.Internal(err))
+			return
+		}
+		webhook.Dispatch(ctx, repos.Webhooks, webhook.EventNewsletterConfirmed, gin.H{"email": sub.Email, "locale": sub.Locale})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Subscription confirmed", "email": sub.Email})
+}
+
+// Unsubscribe 退订（公开接口），请求体：{"token": "..."}，令牌来自邮件中的退订链接，长期有效
+// 地址加入禁止发送列表，之后再订阅不会发送确认邮件；重复退订不报错
+func Unsubscribe(c *gin.Context) {
+	email, ok := newsletterToken(c, newsletter.PurposeUnsubscribe)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	suppressed, err := repos.Newsletter.IsSuppressed(ctx, email)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	if !suppressed {
+		if err := repos.Newsletter.Suppress(ctx, &models.Suppression{Email: email, Reason: "unsubscribed"}); err != nil {
+			respondError(c, apierror.Internal(err))
+			return
+		}
+		webhook.Dispatch(ctx, repos.Webhooks, webhook.EventNewsletterUnsubscribed, gin.H{"email": email})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "You have been unsubscribed", "email": email})
+}
+
+// subscriberStatus 解析 ?status=pending|confirmed|unsubscribed（可省略）；无效时返回 422 并中止
+func subscriberStatus(c *gin.Context) (string, bool) {
+	switch status := c.Query("status"); status {
+	case "", models.SubscriberPending, models.SubscriberConfirmed, models.SubscriberUnsubscribed:
+		return status, true
+	}
+	respondError(c, apierror.Validation(apierror.Field("status", "oneof", "pending confirmed unsubscribed")))
+	return "", false
+}
+
+// GetSubscribers 订阅者列表（管理员），最新的在前；?status= 筛选
+func GetSubscribers(c *gin.Context) {
+	status, ok := subscriberStatus(c)
+	if !ok {
+		return
+	}
+	subscribers, err := repos.Newsletter.ListSubscribers(c.Request.Context(), status)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	c.JSON(http.StatusOK, subscribers)
+}
+
+// ExportSubscribers 导出订阅者 CSV（管理员），?status= 同 GetSubscribers，例如 ?status=confirmed 导出可发送的地址
+func ExportSubscribers(c *gin.Context) {
+	status, ok := subscriberStatus(c)
+	if !ok {
+		return
+	}
+	subscribers, err := repos.Newsletter.ListSubscribers(c.Request.Context(), status)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	filename := "newsletter-subscribers-" + time.Now().Format("20060102") + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"email", "status", "locale", "created_at", "confirmed_at", "unsubscribed_at"})
+	for _, s := range subscribers {
+		w.Write([]string{csvSafe(s.Email), s.Status, s.Locale, csvTime(&s.CreatedAt), csvTime(s.ConfirmedAt), csvTime(s.UnsubscribedAt)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("subscriber export interrupted", "error", err)
+	}
+}
+
+// csvSafe 防止表格软件把 = + - @ 开头的单元格当作公式执行（邮箱的本地部分允许这些字符）
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// GetSuppressions 禁止发送列表（管理员），最新的在前
+func GetSuppressions(c *gin.Context) {
+	suppressions, err := repos.Newsletter.ListSuppressions(c.Request.Context())
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	c.JSON(http.StatusOK, suppressions)
+}
+
+// CreateSuppression 把地址加入禁止发送列表（管理员），例如退信或投诉
+// 请求体：{"email": "ada@example.com", "reason": "bounce"}；已订阅的地址同时改为已退订
+func CreateSuppression(c *gin.Context) {
+	var req struct {
+		Email  string `json:"email" binding:"required,email,max=254"`
+		Reason string `json:"reason" binding:"max=200"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	s := models.Suppression{Email: normalizeEmail(req.Email), Reason: strings.TrimSpace(req.Reason)}
+	if s.Reason == "" {
+		s.Reason = "manual"
+	}
+	if err := repos.Newsletter.Suppress(c.Request.Context(), &s); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// DeleteSuppression 把地址移出禁止发送列表（管理员），之后该地址可以重新订阅（仍需确认）
+func DeleteSuppression(c *gin.Context) {
+	if err := repos.Newsletter.Unsuppress(c.Request.Context(), normalizeEmail(c.Param("email"))); err != nil {
+		respondError(c, repoError(err, "suppression"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Suppression removed"})
+}
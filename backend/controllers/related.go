@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"backend/apierror"
+	"backend/cache"
+	"backend/logger"
+	"backend/middleware"
+	"backend/models"
+	"backend/related"
+	"backend/repository"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// relatedLimit 每篇内容最多推荐的相关内容数
+const relatedLimit = 5
+
+// 相关内容索引按类型分开存储
+const (
+	relatedKindBlogs     = "blogs"
+	relatedKindSolutions = "solutions"
+)
+
+// relatedMu 串行化重新计算，避免并发写入时较早的计算结果覆盖较新的
+var relatedMu sync.Mutex
+
+// RefreshRelated 重新计算博客和解决方案的相关内容索引；启动和恢复数据库后调用
+func RefreshRelated(ctx context.Context) error {
+	return errors.Join(refreshRelatedBlogs(ctx), refreshRelatedSolutions(ctx))
+}
+
+// refreshRelatedBlogs 用原文（默认语言）的标题、摘要、正文和分类重新计算全部博客的索引
+// 标题重复一次，提高标题用词的权重
+func refreshRelatedBlogs(ctx context.Context) error {
+	relatedMu.Lock()
+	defer relatedMu.Unlock()
+
+	blogs, err := repos.Blogs.List(ctx, repository.ContentFilter{})
+	if err != nil {
+		return err
+	}
+	docs := make([]related.Document, len(blogs))
+	for i, b := range blogs {
+		docs[i] = related.Document{
+			ID:         b.ID,
+			Text:       strings.Join([]string{b.Title, b.Title, b.Summary, b.Content}, "\n"),
+			Categories: b.CategoryIDs,
+			Published:  b.CreatedAt,
+		}
+	}
+	return repos.Related.Replace(ctx, relatedKindBlogs, related.Compute(docs, relatedLimit))
+}
+
+// refreshRelatedSolutions 同 refreshRelatedBlogs；解决方案没有分类，只比较文本
+func refreshRelatedSolutions(ctx context.Context) error {
+	relatedMu.Lock()
+	defer relatedMu.Unlock()
+
+	solutions, err := repos.Solutions.List(ctx, repository.ContentFilter{})
+	if err != nil {
+		return err
+	}
+	docs := make([]related.Document, len(solutions))
+	for i, s := range solutions {
+		docs[i] = related.Document{
+			ID:        s.ID,
+			Text:      strings.Join([]string{s.Title, s.Title, s.Description}, "\n"),
+			Published: s.CreatedAt,
+		}
+	}
+	return repos.Related.Replace(ctx, relatedKindSolutions, related.Compute(docs, relatedLimit))
+}
+
+// updateRelated 写入后重新计算索引（须在清理缓存之前）；失败只记日志，不影响本次写入
+func updateRelated(ctx context.Context, refresh func(context.Context) error) {
+	if err := refresh(ctx); err != nil {
+		logger.FromContext(ctx).Warn("related content refresh failed", "error", err)
+	}
+}
+
+// wantRelated 报告请求是否带 ?include=related（include 可用逗号分隔多项）
+func wantRelated(c *gin.Context) bool {
+	for _, v := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(v) == "related" {
+			return true
+		}
+	}
+	return false
+}
+
+// includeRelatedBlog ?include=related 时附上相关博客和上一篇/下一篇
+// 返回这些内容的更新时间（参与 Last-Modified）；出错时已写入错误响应并返回 false
+func includeRelatedBlog(c *gin.Context, blog *models.Blog) ([]time.Time, bool) {
+	if !wantRelated(c) {
+		return nil, true
+	}
+	ctx := c.Request.Context()
+	content, times, err := buildRelated(ctx, relatedKindBlogs, blog.ID,
+		func(id int) (models.Blog, error) { return repos.Blogs.Get(ctx, strconv.Itoa(id)) },
+		func(items []models.Blog) error { return localizeBlogs(ctx, items, contentLocale(c)) },
+		func(b models.Blog) (models.RelatedLink, time.Time) {
+			return models.RelatedLink{ID: b.ID, Title: b.Title, Path: b.Path, Summary: b.Summary}, b.UpdatedAt
+		})
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return nil, false
+	}
+	blog.Related = content
+	middleware.AddCacheTags(c, cache.RelatedTag(cache.TagBlogs))
+	return times, true
+}
+
+// includeRelatedSolution 同 includeRelatedBlog
+func includeRelatedSolution(c *gin.Context, solution *models.Solution) ([]time.Time, bool) {
+	if !wantRelated(c) {
+		return nil, true
+	}
+	ctx := c.Request.Context()
+	content, times, err := buildRelated(ctx, relatedKindSolutions, solution.ID,
+		func(id int) (models.Solution, error) { return repos.Solutions.Get(ctx, strconv.Itoa(id)) },
+		func(items []models.Solution) error { return localizeSolutions(ctx, items, contentLocale(c)) },
+		func(s models.Solution) (models.RelatedLink, time.Time) {
+			return models.RelatedLink{ID: s.ID, Title: s.Title, Path: s.Path, Summary: s.Description}, s.UpdatedAt
+		})
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return nil, false
+	}
+	solution.Related = content
+	middleware.AddCacheTags(c, cache.RelatedTag(cache.TagSolutions))
+	return times, true
+}
+
+// buildRelated 读取预计算的索引并加载其中的内容；索引里已被删除的条目直接跳过
+func buildRelated[T any](
+	ctx context.Context,
+	kind string,
+	id int,
+	load func(id int) (T, error),
+	localize func([]T) error,
+	link func(T) (models.RelatedLink, time.Time),
+) (*models.RelatedContent, []time.Time, error) {
+	entry, err := repos.Related.Get(ctx, kind, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int, 0, len(entry.Related)+2)
+	for _, m := range entry.Related {
+		ids = append(ids, m.ID)
+	}
+	ids = append(ids, entry.Previous, entry.Next)
+
+	var items []T
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+		item, err := load(id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+	if err := localize(items); err != nil {
+		return nil, nil, err
+	}
+
+	links := make(map[int]models.RelatedLink, len(items))
+	times := make([]time.Time, 0, len(items))
+	for _, item := range items {
+		l, updated := link(item)
+		links[l.ID] = l
+		times = append(times, updated)
+	}
+
+	content := &models.RelatedContent{Items: []models.RelatedLink{}}
+	for _, m := range entry.Related {
+		if l, ok := links[m.ID]; ok {
+			l.Score = m.Score
+			content.Items = append(content.Items, l)
+		}
+	}
+	if l, ok := links[entry.Previous]; ok {
+		content.Previous = &l
+	}
+	if l, ok := links[entry.Next]; ok {
+		content.Next = &l
+	}
+	return content, times, nil
+}
@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/repository"
+	"database/sql"
+)
+
+// repos 为处理器使用的数据访问层，默认使用 SQLite（config.DB）
+var repos = repository.NewSQLite(func() *sql.DB { return config.DB })
+
+// UseRepositories 替换处理器使用的数据访问层，例如在单元测试中换成 repository.NewMemory()
+func UseRepositories(s repository.Set) {
+	repos = s
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"backend/apierror"
+	"backend/middleware"
+	"backend/models"
+	"backend/repository"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError 写入统一格式的错误响应（见 apierror）并中止后续处理
+func respondError(c *gin.Context, err error) {
+	middleware.AbortWithError(c, err)
+}
+
+// repoError 把 repository 错误映射为 API 错误：不存在 404，唯一约束冲突 409，其余 500
+func repoError(err error, resource string) error {
+	var conflict *repository.ConflictError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return apierror.NotFound(resource)
+	case errors.As(err, &conflict):
+		return apierror.Conflict(resource, conflict.Field)
+	default:
+		return apierror.Internal(err)
+	}
+}
+
+// paramID 解析路径参数 :id；无效时返回 400 并中止
+func paramID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apierror.InvalidID())
+		return 0, false
+	}
+	return id, true
+}
+
+// setLastModified 以最新的 updated_at 设置 Last-Modified，供 If-Modified-Since 条件请求使用
+func setLastModified(c *gin.Context, times ...time.Time) {
+	var newest time.Time
+	for _, t := range times {
+		if t.After(newest) {
+			newest = t
+		}
+	}
+	if !newest.IsZero() {
+		c.Header("Last-Modified", newest.UTC().Format(http.TimeFormat))
+	}
+}
+
+// updatedAts 取出列表中每一项的更新时间
+func updatedAts[T any](items []T, updatedAt func(T) time.Time) []time.Time {
+	times := make([]time.Time, len(items))
+	for i, item := range items {
+		times[i] = updatedAt(item)
+	}
+	return times
+}
+
+// contentFilter 解析博客/解决方案列表的 ?featured=：true/1 只要推荐内容，false/0 只要非推荐内容
+func contentFilter(c *gin.Context) repository.ContentFilter {
+	var f repository.ContentFilter
+	switch c.Query("featured") {
+	case "1", "true":
+		featured := true
+		f.Featured = &featured
+	case "0", "false":
+		featured := false
+		f.Featured = &featured
+	}
+	return f
+}
+
+// reorderRequest 批量排序的请求体
+type reorderRequest struct {
+	Items []models.SortPosition `json:"items" binding:"required,min=1,dive"`
+}
+
+// bindReorder 解析并校验批量排序请求；同一 ID 出现多次返回 422
+func bindReorder(c *gin.Context) ([]models.SortPosition, bool) {
+	var req reorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return nil, false
+	}
+
+	seen := make(map[int]bool, len(req.Items))
+	for _, p := range req.Items {
+		if seen[p.ID] {
+			respondError(c, apierror.Validation(apierror.Field("items", "invalid")))
+			return nil, false
+		}
+		seen[p.ID] = true
+	}
+	return req.Items, true
+}
+
+// positionIDs 返回批量排序涉及的 ID，用于按条目清理缓存
+func positionIDs(positions []models.SortPosition) []int {
+	ids := make([]int, len(positions))
+	for i, p := range positions {
+		ids[i] = p.ID
+	}
+	return ids
+}
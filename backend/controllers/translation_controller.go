@@ -0,0 +1,336 @@
+package controllers
+
+import (
+	"backend/apierror"
+	"backend/cache"
+	"backend/i18n"
+	"backend/models"
+	"backend/repository"
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentLocale 返回本次请求的内容语言：?lang= 优先，其次 Accept-Language，都不匹配时为默认语言
+func contentLocale(c *gin.Context) string {
+	return i18n.Select(c.Query("lang"), c.GetHeader("Accept-Language"))
+}
+
+// alternates 生成 hreflang 链接：默认语言、已有译文的语言，以及 x-default（不带 lang，由前端按浏览器语言选择）
+func alternates(page string, locales []string) []models.Alternate {
+	out := []models.Alternate{{Hreflang: i18n.Default, Href: page + "?lang=" + i18n.Default}}
+	for _, l := range locales {
+		out = append(out, models.Alternate{Hreflang: l, Href: page + "?lang=" + l})
+	}
+	return append(out, models.Alternate{Hreflang: "x-default", Href: page})
+}
+
+// localizeBlog 把博客详情替换为 lang 的译文（没有译文时保留原文），并填充 alternates
+func localizeBlog(ctx context.Context, b *models.Blog, lang string) error {
+	translations, err := repos.Blogs.Translations(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+	b.Locale = i18n.Default
+	locales := make([]string, 0, len(translations))
+	for _, t := range translations {
+		locales = append(locales, t.Locale)
+		if t.Locale == lang {
+			b.Localize(t)
+		}
+	}
+	b.Alternates = alternates("/blog/"+url.PathEscape(b.Path), locales)
+	return nil
+}
+
+// localizeBlogs 列表版本：一次查出 lang 的全部译文，不生成 alternates
+func localizeBlogs(ctx context.Context, blogs []models.Blog, lang string) error {
+	for i := range blogs {
+		blogs[i].Locale = i18n.Default
+	}
+	if lang == i18n.Default {
+		return nil
+	}
+	translations, err := repos.Blogs.TranslationsByLocale(ctx, lang)
+	if err != nil {
+		return err
+	}
+	for i := range blogs {
+		if t, ok := translations[blogs[i].ID]; ok {
+			blogs[i].Localize(t)
+		}
+	}
+	return nil
+}
+
+// localizeSolution 同 localizeBlog
+func localizeSolution(ctx context.Context, s *models.Solution, lang string) error {
+	translations, err := repos.Solutions.Translations(ctx, s.ID)
+	if err != nil {
+		return err
+	}
+	s.Locale = i18n.Default
+	locales := make([]string, 0, len(translations))
+	for _, t := range translations {
+		locales = append(locales, t.Locale)
+		if t.Locale == lang {
+			s.Localize(t)
+		}
+	}
+	s.Alternates = alternates("/solution/"+url.PathEscape(s.Path), locales)
+	return nil
+}
+
+// localizeSolutions 同 localizeBlogs
+func localizeSolutions(ctx context.Context, solutions []models.Solution, lang string) error {
+	for i := range solutions {
+		solutions[i].Locale = i18n.Default
+	}
+	if lang == i18n.Default {
+		return nil
+	}
+	translations, err := repos.Solutions.TranslationsByLocale(ctx, lang)
+	if err != nil {
+		return err
+	}
+	for i := range solutions {
+		if t, ok := translations[solutions[i].ID]; ok {
+			solutions[i].Localize(t)
+		}
+	}
+	return nil
+}
+
+// parseTranslationLocale 校验语言代码；默认语言的内容就是原文，不能作为译文
+func parseTranslationLocale(v string) (string, error) {
+	locale := i18n.Normalize(v)
+	if !slices.Contains(i18n.Translations(), locale) {
+		return "", apierror.Validation(apierror.Field("locale", "oneof", strings.Join(i18n.Translations(), " ")))
+	}
+	return locale, nil
+}
+
+// paramLocale 解析路径参数 :locale；无效时返回 422 并中止
+func paramLocale(c *gin.Context) (string, bool) {
+	locale, err := parseTranslationLocale(c.Param("locale"))
+	if err != nil {
+		respondError(c, err)
+		return "", false
+	}
+	return locale, true
+}
+
+// GetBlogTranslations 获取博客的全部译文（管理员）
+func GetBlogTranslations(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := repos.Blogs.Translations(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// SaveBlogTranslation 新增或覆盖博客某个语言的译文（管理员）
+func SaveBlogTranslation(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	locale, ok := paramLocale(c)
+	if !ok {
+		return
+	}
+
+	var t models.BlogTranslation
+	if err := c.ShouldBindJSON(&t); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	t.BlogID, t.Locale = id, locale
+
+	if err := repos.Blogs.SaveTranslation(c.Request.Context(), &t); err != nil {
+		respondError(c, repoError(err, "blog"))
+		return
+	}
+
+	cache.Invalidate(c.Request.Context(), cache.TagBlogs, id)
+
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteBlogTranslation 删除博客某个语言的译文（管理员）
+func DeleteBlogTranslation(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	locale, ok := paramLocale(c)
+	if !ok {
+		return
+	}
+
+	if err := repos.Blogs.DeleteTranslation(c.Request.Context(), id, locale); err != nil {
+		respondError(c, repoError(err, "translation"))
+		return
+	}
+
+	cache.Invalidate(c.Request.Context(), cache.TagBlogs, id)
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetSolutionTranslations 获取解决方案的全部译文（管理员）
+func GetSolutionTranslations(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := repos.Solutions.Translations(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, repoError(err, "solution"))
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// SaveSolutionTranslation 新增或覆盖解决方案某个语言的译文（管理员）
+func SaveSolutionTranslation(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	locale, ok := paramLocale(c)
+	if !ok {
+		return
+	}
+
+	var t models.SolutionTranslation
+	if err := c.ShouldBindJSON(&t); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	t.SolutionID, t.Locale = id, locale
+
+	if err := repos.Solutions.SaveTranslation(c.Request.Context(), &t); err != nil {
+		respondError(c, repoError(err, "solution"))
+		return
+	}
+
+	cache.Invalidate(c.Request.Context(), cache.TagSolutions, id)
+
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteSolutionTranslation 删除解决方案某个语言的译文（管理员）
+func DeleteSolutionTranslation(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	locale, ok := paramLocale(c)
+	if !ok {
+		return
+	}
+
+	if err := repos.Solutions.DeleteTranslation(c.Request.Context(), id, locale); err != nil {
+		respondError(c, repoError(err, "translation"))
+		return
+	}
+
+	cache.Invalidate(c.Request.Context(), cache.TagSolutions, id)
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// missingTranslation 缺少译文的一条内容
+type missingTranslation struct {
+	ID      int      `json:"id"`
+	Title   string   `json:"title"`
+	Path    string   `json:"path"`
+	Missing []string `json:"missing"`
+}
+
+// GetMissingTranslations 列出缺少译文的博客和解决方案（管理员）
+// 支持 ?locale= 只检查一种语言；默认检查所有非默认语言
+func GetMissingTranslations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	locales := i18n.Translations()
+	if v := c.Query("locale"); v != "" {
+		locale, err := parseTranslationLocale(v)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		locales = []string{locale}
+	}
+
+	blogs, err := repos.Blogs.List(ctx, repository.ContentFilter{})
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+	solutions, err := repos.Solutions.List(ctx, repository.ContentFilter{})
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	blogsMissing := map[int][]string{}
+	solutionsMissing := map[int][]string{}
+	for _, l := range locales {
+		bt, err := repos.Blogs.TranslationsByLocale(ctx, l)
+		if err != nil {
+			respondError(c, apierror.Internal(err))
+			return
+		}
+		for _, b := range blogs {
+			if _, ok := bt[b.ID]; !ok {
+				blogsMissing[b.ID] = append(blogsMissing[b.ID], l)
+			}
+		}
+
+		st, err := repos.Solutions.TranslationsByLocale(ctx, l)
+		if err != nil {
+			respondError(c, apierror.Internal(err))
+			return
+		}
+		for _, s := range solutions {
+			if _, ok := st[s.ID]; !ok {
+				solutionsMissing[s.ID] = append(solutionsMissing[s.ID], l)
+			}
+		}
+	}
+
+	blogReport := []missingTranslation{}
+	for _, b := range blogs {
+		if missing := blogsMissing[b.ID]; len(missing) > 0 {
+			blogReport = append(blogReport, missingTranslation{ID: b.ID, Title: b.Title, Path: b.Path, Missing: missing})
+		}
+	}
+	solutionReport := []missingTranslation{}
+	for _, s := range solutions {
+		if missing := solutionsMissing[s.ID]; len(missing) > 0 {
+			solutionReport = append(solutionReport, missingTranslation{ID: s.ID, Title: s.Title, Path: s.Path, Missing: missing})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"default_locale": i18n.Default,
+		"locales":        locales,
+		"blogs":          blogReport,
+		"solutions":      solutionReport,
+	})
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"backend/apierror"
+	"backend/models"
+	"backend/repository"
+	"backend/webhook"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type webhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// normalize 校验 URL 和事件类型，失败时返回字段级校验错误
+func (req *webhookRequest) normalize() error {
+	u, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apierror.Validation(apierror.Field("url", "url"))
+	}
+	req.URL = u.String()
+
+	if len(req.Events) == 0 {
+		req.Events = []string{"*"}
+	}
+	for i, e := range req.Events {
+		e = strings.TrimSpace(e)
+		if !webhook.ValidEvent(e) {
+			return apierror.Validation(apierror.Field("events", "oneof", "* "+strings.Join(webhook.Events, " ")))
+		}
+		req.Events[i] = e
+	}
+	return nil
+}
+
+// toModel 转换为入库用的模型；未传 active 时默认启用
+func (req *webhookRequest) toModel(id int) models.Webhook {
+	return models.Webhook{
+		ID:     id,
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: req.Active == nil || *req.Active,
+	}
+}
+
+// GetWebhooks 获取所有 Webhook 订阅（管理员）
+func GetWebhooks(c *gin.Context) {
+	hooks, err := repos.Webhooks.List(c.Request.Context())
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": hooks, "available_events": webhook.Events})
+}
+
+// CreateWebhook 创建 Webhook 订阅（管理员）
+// 未指定 secret 时自动生成并在响应中返回，之后不再返回
+func CreateWebhook(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	if err := req.normalize(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// 每次投递都要签名：未指定 secret 时自动生成，只在这次响应中返回
+	generated := req.Secret == ""
+	if generated {
+		req.Secret = webhook.NewSecret()
+	}
+	hook := req.toModel(0)
+	if err := repos.Webhooks.Create(c.Request.Context(), &hook); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	if !generated {
+		hook.Secret = ""
+	}
+	c.JSON(http.StatusCreated, hook)
+}
+
+// UpdateWebhook 更新 Webhook 订阅（管理员）
+// secret 为空时保留原有密钥
+func UpdateWebhook(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apierror.Bind(err))
+		return
+	}
+	if err := req.normalize(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	hook := req.toModel(id)
+	if err := repos.Webhooks.Update(c.Request.Context(), &hook); err != nil {
+		respondError(c, repoError(err, "webhook"))
+		return
+	}
+
+	hook.Secret = ""
+	c.JSON(http.StatusOK, hook)
+}
+
+// DeleteWebhook 删除 Webhook 订阅及其投递记录（管理员）
+func DeleteWebhook(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	if err := repos.Webhooks.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetWebhookDeliveries 获取 Webhook 投递日志（管理员，用于排查）
+// 支持 ?webhook_id=、?status=pending|success|failed、?limit=（默认 50，最大 500）
+func GetWebhookDeliveries(c *gin.Context) {
+	filter := repository.DeliveryFilter{Status: c.Query("status"), Limit: 50}
+
+	if v := c.Query("webhook_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, apierror.Validation(apierror.Field("webhook_id", "number")))
+			return
+		}
+		filter.WebhookID = id
+	}
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+		filter.Limit = min(v, 500)
+	}
+
+	deliveries, err := repos.Webhooks.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, apierror.Internal(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RetryWebhookDelivery 立即重新投递一条记录（管理员）
+func RetryWebhookDelivery(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+
+	if err := repos.Webhooks.RetryDelivery(c.Request.Context(), id); err != nil {
+		respondError(c, repoError(err, "delivery"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已重新加入投递队列"})
+}
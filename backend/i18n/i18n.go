@@ -0,0 +1,90 @@
+// Package i18n negotiates the response language for API messages.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales. English is the default because the public site is
+// English-first; the admin UI and Chinese visitors send zh via
+// Accept-Language.
+const (
+	English = "en"
+	Chinese = "zh"
+
+	Default = English
+)
+
+// Supported lists the locales that have message catalogs.
+var Supported = []string{English, Chinese}
+
+// Normalize maps a language tag such as "zh-CN" or "EN_us" to a supported
+// locale, or returns "" when none matches.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return ""
+	}
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	for _, l := range Supported {
+		if base == l {
+			return l
+		}
+	}
+	return ""
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header,
+// honouring q-values, and falls back to Default.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale := Normalize(tag)
+		if locale == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// Select returns the locale for a request: an explicit ?lang= value wins
+// when it names a supported locale, otherwise Accept-Language is negotiated.
+func Select(lang, acceptLanguage string) string {
+	if l := Normalize(lang); l != "" {
+		return l
+	}
+	return Negotiate(acceptLanguage)
+}
+
+// Translations lists the locales content can be translated into: every
+// supported locale except Default, which is the language of the original
+// rows.
+func Translations() []string {
+	var out []string
+	for _, l := range Supported {
+		if l != Default {
+			out = append(out, l)
+		}
+	}
+	return out
+}
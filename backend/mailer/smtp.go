@@ -0,0 +1,125 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"mime"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP sends mail through an SMTP server, upgrading to TLS with STARTTLS
+// when the server offers it. Authentication is used when Username is set.
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// dialTimeout bounds connecting to the server; a request waiting on mail
+// must not hang.
+const dialTimeout = 10 * time.Second
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	msg, err := s.format(m)
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(tlsConfig(s.Host)); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(m.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// format renders m as an RFC 5322 message with a UTF-8 plain-text body.
+func (s *SMTP) format(m Message) ([]byte, error) {
+	headers := map[string]string{
+		"From":                      s.From,
+		"To":                        m.To,
+		"Subject":                   mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":                      time.Now().Format(time.RFC1123Z),
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding": "8bit",
+	}
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+
+	names := make([]string, 0, len(headers))
+	for k, v := range headers {
+		// Values come from our own templates and validated addresses, but a
+		// line break would let a header value inject further headers.
+		if strings.ContainsAny(k+v, "\r\n") {
+			return nil, errors.New("mailer: line break in header " + k)
+		}
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, k := range names {
+		b.WriteString(k + ": " + headers[k] + "\r\n")
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(m.Text, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes(), nil
+}
+
+func tlsConfig(host string) *tls.Config {
+	return &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+}
@@ -1,47 +1,120 @@
 package main
 
 import (
+	"backend/analytics"
+	"backend/antispam"
+	"backend/cache"
 	"backend/config"
+	"backend/controllers"
+	"backend/logger"
+	"backend/mailer"
+	"backend/metrics"
 	"backend/middleware"
+	"backend/newsletter"
 	"backend/routes"
-	"log"
+	"backend/webhook"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	// 加载环境变量
-	godotenv.Load()
+	// 子命令：config print / config check
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (default $CONFIG_FILE)")
+	_ = fs.Parse(os.Args[1:])
+	if fs.NArg() > 0 {
+		os.Exit(runCommand(fs.Args(), *configFile))
+	}
+
+	// 加载配置：默认值 < 配置文件 < .env < 环境变量
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// 初始化结构化日志（LOG_FORMAT / LOG_LEVEL）
+	logger.Init(cfg.Log.Format, cfg.Log.Level)
+	gin.SetMode(cfg.Server.Mode)
+	if cfg.Auth.JWTSecret == config.DefaultJWTSecret {
+		slog.Warn("JWT_SECRET is using the built-in default; set a strong random value in production")
+	}
+
+	antispam.Configure(antispam.Config{
+		MinFillTime:  config.Seconds(cfg.Contact.MinFillSeconds),
+		TokenMaxAge:  config.Seconds(cfg.Contact.TokenMaxAgeSeconds),
+		MaxLinks:     cfg.Contact.MaxLinks,
+		SpamKeywords: cfg.Contact.SpamKeywords,
+		Secret:       []byte(cfg.Contact.TokenSecret),
+	})
+
+	// 邮件订阅链接的签名密钥：未配置时由 JWT_SECRET 派生，重启后旧链接仍然有效
+	newsletterSecret := []byte(cfg.Newsletter.Secret)
+	if len(newsletterSecret) == 0 {
+		sum := sha256.Sum256([]byte("newsletter:" + cfg.Auth.JWTSecret))
+		newsletterSecret = sum[:]
+	}
+	newsletter.Configure(newsletter.Config{
+		Secret:     newsletterSecret,
+		ConfirmTTL: time.Duration(cfg.Newsletter.ConfirmTTLHours) * time.Hour,
+		SiteURL:    cfg.Newsletter.SiteURL,
+	})
+	// 选择邮件发送方式（MAIL_DRIVER=log 时只写日志）
+	mailer.Init()
 
 	// 初始化数据库
 	config.InitDB()
-	defer config.CloseDB()
+
+	// 重新计算相关内容索引（数据库可能在服务之外被修改过）
+	if err := controllers.RefreshRelated(context.Background()); err != nil {
+		slog.Warn("related content refresh failed", "error", err)
+	}
 
 	// 初始化Redis（可选）
 	config.InitRedis()
-	defer config.CloseRedis()
+
+	// 选择响应缓存存储（Redis 不可用时可回退到进程内 LRU）
+	cache.Init()
+
+	// 启动 Webhook 投递队列
+	webhook.StartWorker()
+
+	// 定时把缓冲的浏览数写入数据库
+	analytics.StartWorker(controllers.SaveViews, config.Seconds(cfg.Analytics.FlushSeconds))
 
 	// 创建Gin路由
-	r := gin.Default()
+	gin.DebugPrintRouteFunc = func(method, path, handler string, nHandlers int) {
+		slog.Debug("route registered", "method", method, "path", path, "handler", handler)
+	}
+	r := gin.New()
 	r.MaxMultipartMemory = 64 << 20
 
-	// 从环境变量获取 CORS 允许的源
-	corsOrigins := os.Getenv("CORS_ORIGINS")
-	if corsOrigins == "" {
-		corsOrigins = "http://localhost:3000,http://localhost:3001"
-	}
-	allowedOrigins := strings.Split(corsOrigins, ",")
+	// 请求 ID、访问日志、panic 恢复
+	r.Use(middleware.RequestID())
+	r.Use(middleware.AccessLog())
+	r.Use(middleware.Recovery())
+	r.Use(metrics.Middleware())
 
 	// 配置CORS
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     allowedOrigins,
+		AllowOrigins:     cfg.Server.CORSOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
@@ -51,15 +124,115 @@ func main() {
 	// 设置路由
 	routes.SetupRoutes(r)
 
-	// 从环境变量获取端口
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Prometheus 指标（设置 METRICS_TOKEN 后需要 Bearer Token）
+	metrics.RegisterDBStats(func() (sql.DBStats, bool) {
+		if !middleware.TryLockDBRead() {
+			return sql.DBStats{}, false
+		}
+		defer middleware.UnlockDBRead()
+		if config.DB == nil {
+			return sql.DBStats{}, false
+		}
+		return config.DB.Stats(), true
+	})
+	metrics.RegisterCounterFunc("contact_rejected_total", "Contact form submissions rejected by anti-spam checks.", "reason", func() map[string]int64 {
+		return antispam.Snapshot().Rejected
+	})
+	r.GET("/metrics", metrics.Handler(cfg.Metrics.Token))
+
+	port := strconv.Itoa(cfg.Server.Port)
+
+	// 超时配置：读/写超时需覆盖数据库备份下载和恢复上传
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       config.Seconds(cfg.Server.ReadTimeoutSeconds),
+		WriteTimeout:      config.Seconds(cfg.Server.WriteTimeoutSeconds),
+		IdleTimeout:       config.Seconds(cfg.Server.IdleTimeoutSeconds),
 	}
 
 	// 启动服务器
-	log.Printf("服务器启动在 http://localhost:%s\n", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("服务器启动失败:", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "addr", "http://localhost:"+port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// 等待 SIGINT/SIGTERM（容器停止时发送 SIGTERM）
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
+		slog.Error("server failed", "error", err)
+		exitCode = 1
+	case <-ctx.Done():
+		slog.Info("shutdown signal received; draining in-flight requests")
+	}
+	stop()
+
+	shutdown(srv, config.Seconds(cfg.Server.ShutdownTimeoutSeconds))
+	os.Exit(exitCode)
+}
+
+// shutdown 停止接收新连接，等待进行中的请求（包括数据库恢复）完成，
+// 然后依次停止 Webhook 投递、写入缓冲的浏览数、关闭 Redis 和 SQLite
+func shutdown(srv *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Warn("server shutdown deadline exceeded; some requests were cut off", "error", err)
+	} else {
+		slog.Info("server stopped accepting requests; in-flight requests finished")
+	}
+
+	webhook.StopWorker()
+	analytics.StopWorker()
+	config.CloseRedis()
+
+	// 拿到写锁说明没有请求（或恢复）仍在使用数据库；超时仍未拿到则不关闭，
+	// 避免在恢复中途关掉连接
+	locked := make(chan struct{})
+	go func() {
+		middleware.LockDBWrite()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		config.CloseDB()
+		middleware.UnlockDBWrite()
+	case <-time.After(5 * time.Second):
+		slog.Warn("database still in use after shutdown deadline; exiting without closing it")
+	}
+}
+
+// runCommand 处理子命令，返回进程退出码
+//
+//	config print  打印生效配置（密钥脱敏）
+//	config check  仅校验配置
+func runCommand(args []string, configFile string) int {
+	if len(args) != 2 || args[0] != "config" || (args[1] != "print" && args[1] != "check") {
+		fmt.Fprintln(os.Stderr, "usage: server [-config file] [config print|config check]")
+		return 2
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if args[1] == "check" {
+		fmt.Println("configuration OK")
+		return 0
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
+	return 0
 }
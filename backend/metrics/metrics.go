@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "yan"
+
+// Registry holds every backend metric. It is separate from the global
+// default registry so tests and restarts don't trip over duplicate
+// registrations.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	CacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "response_cache_lookups_total",
+		Help:      "Public GET response cache lookups by result (hit, miss, stale or coalesced).",
+	}, []string{"result"})
+
+	RedisErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_errors_total",
+		Help:      "Redis command errors by operation.",
+	}, []string{"op"})
+
+	DBLockWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_lock_wait_seconds",
+		Help:      "Time spent waiting for the database restore lock by mode (read or write).",
+		Buckets:   []float64{.0001, .001, .01, .1, .5, 1, 5, 15, 60},
+	}, []string{"mode"})
+
+	BackupDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_backup_duration_seconds",
+		Help:      "Database backup duration by result.",
+		Buckets:   []float64{.1, .5, 1, 5, 15, 60, 300},
+	}, []string{"result"})
+
+	RestoreDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_restore_duration_seconds",
+		Help:      "Database restore duration by result.",
+		Buckets:   []float64{.1, .5, 1, 5, 15, 60, 300},
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		CacheLookups,
+		RedisErrors,
+		DBLockWait,
+		BackupDuration,
+		RestoreDuration,
+	)
+}
+
+// Result maps an error to the "result" label value.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// Middleware records request counts and latency per matched route.
+// Unmatched paths are grouped under "unmatched" to keep cardinality bounded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		HTTPDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the Prometheus exposition format. When token is non-empty
+// the scraper must send "Authorization: Bearer <token>".
+func Handler(token string) gin.HandlerFunc {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	return func(c *gin.Context) {
+		if token != "" {
+			got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+		}
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// dbStatsCollector reports database/sql pool stats at scrape time. The DB
+// handle is looked up through a callback because a restore swaps it.
+type dbStatsCollector struct {
+	stats func() (sql.DBStats, bool)
+
+	openConns    *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+	maxOpen      *prometheus.Desc
+}
+
+// RegisterDBStats registers pool metrics; stats returns false when the
+// pool is temporarily unavailable (e.g. during a restore).
+func RegisterDBStats(stats func() (sql.DBStats, bool)) {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", name), help, nil, nil)
+	}
+	Registry.MustRegister(&dbStatsCollector{
+		stats:        stats,
+		openConns:    desc("open_connections", "Number of established connections."),
+		inUse:        desc("in_use_connections", "Number of connections currently in use."),
+		idle:         desc("idle_connections", "Number of idle connections."),
+		waitCount:    desc("wait_count_total", "Total number of connections waited for."),
+		waitDuration: desc("wait_duration_seconds_total", "Total time blocked waiting for a new connection."),
+		maxOpen:      desc("max_open_connections", "Maximum number of open connections."),
+	})
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openConns
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxOpen
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	s, ok := c.stats()
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.openConns, prometheus.GaugeValue, float64(s.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(s.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, s.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(s.MaxOpenConnections))
+}
+
+// RegisterCounterFunc exposes a snapshot-based counter vector, e.g. for
+// subsystems that keep their own counters.
+func RegisterCounterFunc(name, help, label string, snapshot func() map[string]int64) {
+	Registry.MustRegister(&funcCounterCollector{
+		desc:     prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, []string{label}, nil),
+		snapshot: snapshot,
+	})
+}
+
+type funcCounterCollector struct {
+	desc     *prometheus.Desc
+	snapshot func() map[string]int64
+}
+
+func (c *funcCounterCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+func (c *funcCounterCollector) Collect(ch chan<- prometheus.Metric) {
+	for k, v := range c.snapshot() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(v), k)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"backend/cache"
+)
+
+// negotiateEncoding picks the content coding for Accept-Encoding among
+// available (in order of preference), falling back to identity. Codings
+// with q=0 are refused; "*" covers any coding not listed explicitly.
+func negotiateEncoding(acceptEncoding string, available []string) string {
+	if acceptEncoding == "" {
+		return cache.Identity
+	}
+
+	q := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		q[name] = weight
+	}
+
+	best, bestQ := cache.Identity, 0.0
+	for _, coding := range available {
+		weight, ok := q[coding]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > bestQ {
+			best, bestQ = coding, weight
+		}
+	}
+	return best
+}
+
+// encodedETag derives the entity tag of one coding of a response: the
+// representations differ byte for byte, so strong tags must differ too.
+func encodedETag(etag, coding string) string {
+	if coding == cache.Identity || etag == "" {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + coding + `"`
+}
@@ -1,16 +1,47 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"sync"
+	"time"
+
+	"backend/metrics"
 
 	"github.com/gin-gonic/gin"
 )
 
 var dbMu sync.RWMutex
 
+// dbUnlockKey holds the function that releases the request's read lock.
+const dbUnlockKey = "db_read_unlock"
+
 func LockDBRead() {
+	start := time.Now()
 	dbMu.RLock()
+	metrics.DBLockWait.WithLabelValues("read").Observe(time.Since(start).Seconds())
+}
+
+// TryLockDBRead takes the read lock only if no restore holds it.
+func TryLockDBRead() bool {
+	return dbMu.TryRLock()
+}
+
+// LockDBReadContext takes the read lock, giving up once ctx is done, and
+// reports whether it got the lock. Background workers use it so a restore
+// holding the write lock cannot stall their shutdown.
+func LockDBReadContext(ctx context.Context) bool {
+	start := time.Now()
+	for !dbMu.TryRLock() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	metrics.DBLockWait.WithLabelValues("read").Observe(time.Since(start).Seconds())
+	return true
 }
 
 func UnlockDBRead() {
@@ -18,7 +49,9 @@ func UnlockDBRead() {
 }
 
 func LockDBWrite() {
+	start := time.Now()
 	dbMu.Lock()
+	metrics.DBLockWait.WithLabelValues("write").Observe(time.Since(start).Seconds())
 }
 
 func UnlockDBWrite() {
@@ -26,7 +59,8 @@ func UnlockDBWrite() {
 }
 
 // DBReadLock blocks DB operations during a restore.
-// It skips locking for the restore/backup endpoints themselves.
+// It skips locking for the restore/backup endpoints themselves and for
+// non-API endpoints (metrics, health checks) that must answer mid-restore.
 func DBReadLock() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		path := c.Request.URL.Path
@@ -38,9 +72,26 @@ func DBReadLock() gin.HandlerFunc {
 			c.Next()
 			return
 		}
+		if !strings.HasPrefix(path, "/api/") {
+			c.Next()
+			return
+		}
 
-		dbMu.RLock()
-		defer dbMu.RUnlock()
+		LockDBRead()
+		var once sync.Once
+		unlock := func() { once.Do(UnlockDBRead) }
+		c.Set(dbUnlockKey, unlock)
+		defer unlock()
 		c.Next()
 	}
 }
+
+// ReleaseDBRead releases the request's DB read lock before the handler
+// returns, so slow work that does not touch the database (such as sending
+// mail) does not hold up a restore. The handler must not use the database
+// afterwards. It does nothing when the request holds no lock.
+func ReleaseDBRead(c *gin.Context) {
+	if unlock, ok := c.Get(dbUnlockKey); ok {
+		unlock.(func())()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"backend/apierror"
+	"backend/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbortWithError writes err as the API error envelope in the language
+// negotiated from Accept-Language. Errors that are not *apierror.Error are
+// reported as internal errors; their text is recorded on the context (and so
+// in the access log) but never sent to the client.
+func AbortWithError(c *gin.Context, err error) {
+	e := apierror.From(err)
+	if e.Err != nil {
+		_ = c.Error(e.Err)
+	}
+
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	body := e.Body(lang)
+	body.RequestID = GetRequestID(c)
+
+	c.Header("Content-Language", lang)
+	c.AbortWithStatusJSON(e.Status, body)
+}
+
+// Recovery turns a panic into a 500 envelope instead of gin's empty body.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		AbortWithError(c, apierror.Internal(fmt.Errorf("panic: %v", recovered)))
+	})
+}
+
+// NoRoute answers unknown paths with the error envelope.
+func NoRoute(c *gin.Context) {
+	AbortWithError(c, apierror.New(http.StatusNotFound, apierror.CodeRouteNotFound))
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimitByIP allows at most limit requests per client IP within each
+// fixed window. Rejected requests get 429 and onLimited (if set) is called
+// before aborting.
+func RateLimitByIP(limit int, window time.Duration, onLimited func(c *gin.Context)) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	var (
+		mu        sync.Mutex
+		clients   = map[string]*rateWindow{}
+		lastSweep = time.Now()
+	)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		// Drop expired windows occasionally so the map doesn't grow unbounded.
+		if now.Sub(lastSweep) > window {
+			for k, w := range clients {
+				if now.Sub(w.start) >= window {
+					delete(clients, k)
+				}
+			}
+			lastSweep = now
+		}
+
+		w, ok := clients[ip]
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			clients[ip] = w
+		}
+		w.count++
+		allowed := w.count <= limit
+		retryAfter := window - now.Sub(w.start)
+		mu.Unlock()
+
+		if !allowed {
+			if onLimited != nil {
+				onLimited(c)
+			}
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			AbortWithError(c, apierror.RateLimited())
+			return
+		}
+
+		c.Next()
+	}
+}
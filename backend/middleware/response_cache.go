@@ -2,86 +2,390 @@ package middleware
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"backend/cache"
+	"backend/i18n"
+	"backend/logger"
+	"backend/metrics"
 
 	"github.com/gin-gonic/gin"
 )
 
+// bodyCaptureWriter buffers the response body so validators (ETag,
+// Last-Modified) can be added after the handler has run and a 304 can be sent
+// instead of the body.
 type bodyCaptureWriter struct {
 	gin.ResponseWriter
 	body bytes.Buffer
 }
 
 func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
-	_, _ = w.body.Write(b)
-	return w.ResponseWriter.Write(b)
+	return w.body.Write(b)
 }
 
-func CachePublicGetResponses(ttl time.Duration, maxBodyBytes int) gin.HandlerFunc {
-	if ttl <= 0 {
-		ttl = 5 * time.Minute
-	}
-	if maxBodyBytes <= 0 {
-		maxBodyBytes = 1024 * 1024 // 1MB
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// CacheControl sets the Cache-Control header for a route, overriding the
+// default applied by CachePublicGetResponses. Handlers can still override it.
+func CacheControl(value string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", value)
+		c.Next()
 	}
+}
 
+const cacheTagsKey = "cache_tags"
+
+// CacheTags tags a route's cached responses with the collections it reads
+// (cache.TagBlogs, ...), so cache.Invalidate can purge them after a write.
+// Responses without tags only expire with the TTL.
+func CacheTags(tags ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.Request.Method != http.MethodGet {
-			c.Next()
-			return
-		}
+		AddCacheTags(c, tags...)
+		c.Next()
+	}
+}
 
-		// Avoid caching authenticated/admin endpoints.
-		if strings.HasPrefix(c.Request.URL.Path, "/api/admin") {
-			c.Next()
-			return
-		}
+// AddCacheTags adds tags from inside a handler, typically the
+// cache.EntityTag of the record it returned.
+func AddCacheTags(c *gin.Context, tags ...string) {
+	c.Set(cacheTagsKey, append(c.GetStringSlice(cacheTagsKey), tags...))
+}
+
+const cacheExpiresKey = "cache_expires"
+
+// ExpireCacheAt stops the response from being served from the cache, even
+// stale, from t on; handlers call it when their output changes at a known
+// time (e.g. a scheduled carousel slide). The earliest of several calls wins.
+func ExpireCacheAt(c *gin.Context, t time.Time) {
+	if prev, ok := c.Get(cacheExpiresKey); ok && prev.(time.Time).Before(t) {
+		return
+	}
+	c.Set(cacheExpiresKey, t)
+}
+
+// ResponseCacheOptions configures CachePublicGetResponses.
+type ResponseCacheOptions struct {
+	TTL          time.Duration
+	MaxBodyBytes int
+	// DefaultControl is the Cache-Control for routes that do not set one.
+	DefaultControl string
+	// Stale is how long an expired entry may still be served while a single
+	// background request refreshes it; 0 disables stale-while-revalidate.
+	Stale time.Duration
+	// WarmPaths are requested in the background after every purge so the
+	// first visitor does not pay for the miss.
+	WarmPaths []string
+	// Handler serves refresh and warm-up requests; normally the router the
+	// middleware is installed on. Without it entries are never served stale
+	// and nothing is warmed.
+	Handler http.Handler
+}
+
+// CachePublicGetResponses caches public 200 JSON GET responses and answers
+// conditional requests: every cacheable response carries a strong ETag
+// computed from its body, the handler's Last-Modified (if any) and a
+// Cache-Control header (DefaultControl unless the route sets its own), and
+// If-None-Match / If-Modified-Since are answered with 304 Not Modified.
+//
+// Concurrent misses for one key are coalesced: the first request runs the
+// handler and the others wait for its response instead of all hitting the
+// database.
+func CachePublicGetResponses(opts ResponseCacheOptions) gin.HandlerFunc {
+	if opts.TTL <= 0 {
+		opts.TTL = 5 * time.Minute
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 1024 * 1024 // 1MB
+	}
+	rc := &responseCache{opts: opts, flights: make(map[string]*flight)}
+	if len(opts.WarmPaths) > 0 && opts.Handler != nil {
+		cache.OnPurge(rc.warm)
+	} else {
+		cache.OnPurge(nil)
+	}
+	return rc.handle
+}
+
+type responseCache struct {
+	opts    ResponseCacheOptions
+	mu      sync.Mutex
+	flights map[string]*flight // in-flight handler runs by cache key
+}
+
+func (rc *responseCache) handle(c *gin.Context) {
+	if c.Request.Method != http.MethodGet {
+		c.Next()
+		return
+	}
 
-		key := cache.CacheKey(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery)
+	// Avoid caching authenticated/admin endpoints.
+	if strings.HasPrefix(c.Request.URL.Path, "/api/admin") {
+		c.Next()
+		return
+	}
+
+	// Localized handlers pick the language from ?lang= or Accept-Language,
+	// so the negotiated locale is part of the key.
+	c.Header("Vary", "Accept-Language")
+	locale := i18n.Select(c.Query("lang"), c.GetHeader("Accept-Language"))
+	key := cache.CacheKey(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery+"#"+locale)
 
-		if cached, ok := cache.Get(c.Request.Context(), key); ok {
-			body, err := base64.StdEncoding.DecodeString(cached.BodyB64)
-			if err == nil {
-				if cached.ContentType != "" {
-					c.Header("Content-Type", cached.ContentType)
+	// A background refresh already owns the flight for its key.
+	f, _ := c.Request.Context().Value(refreshKey{}).(*flight)
+	if f == nil && cache.Enabled() {
+		if cached, fresh := lookup(c.Request.Context(), key); cached != nil {
+			switch {
+			case fresh:
+				if rc.serve(c, cached, "hit") {
+					return
 				}
-				c.Header("X-Cache", "HIT")
-				c.Status(cached.Status)
-				_, _ = c.Writer.Write(body)
+			case rc.opts.Handler != nil:
+				rc.refresh(c.Request, key)
+				if rc.serve(c, cached, "stale") {
+					return
+				}
+			}
+		}
+
+		var leader bool
+		if f, leader = rc.join(key); !leader {
+			select {
+			case <-f.done:
+				if f.resp != nil && rc.serve(c, f.resp, "coalesced") {
+					return
+				}
+			case <-c.Request.Context().Done():
 				c.Abort()
 				return
 			}
+			// The leader's response was not cacheable; run the handler.
+			f = nil
+		} else {
+			defer rc.finish(key, f)
+			// The previous leader may have stored its response between the
+			// lookup above and join; serve that instead of running again.
+			if cached, fresh := lookup(c.Request.Context(), key); cached != nil && fresh && rc.serve(c, cached, "hit") {
+				f.resp = cached
+				return
+			}
 		}
+	}
 
-		bw := &bodyCaptureWriter{ResponseWriter: c.Writer}
-		c.Writer = bw
+	if cache.Enabled() {
+		c.Header("X-Cache", "MISS")
+		metrics.CacheLookups.WithLabelValues("miss").Inc()
+	}
 
-		c.Next()
+	w := c.Writer
+	bw := &bodyCaptureWriter{ResponseWriter: w}
+	c.Writer = bw
+	// Restore the real writer even if the handler panics, so Recovery's
+	// error response is not swallowed by the buffer.
+	defer func() { c.Writer = w }()
+
+	c.Next()
+
+	body := bw.body.Bytes()
+	h := w.Header()
+	ct := h.Get("Content-Type")
+
+	// Handlers opt out of caching for per-request responses (e.g. form
+	// tokens); only successful JSON gets validators, to keep behavior
+	// predictable.
+	if bw.Status() != http.StatusOK || len(body) == 0 ||
+		strings.Contains(h.Get("Cache-Control"), "no-store") ||
+		(ct != "" && !strings.HasPrefix(ct, "application/json")) {
+		_, _ = w.Write(body)
+		return
+	}
 
-		status := bw.Status()
-		if status != http.StatusOK {
-			return
+	control := h.Get("Cache-Control")
+	if control == "" {
+		control = rc.opts.DefaultControl
+	}
+	entry := &cache.CachedResponse{
+		Status:       http.StatusOK,
+		ContentType:  ct,
+		ETag:         ETag(body),
+		LastModified: h.Get("Last-Modified"),
+		CacheControl: control,
+	}
+	if err := entry.SetBody(body); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("response compression failed", "error", err)
+		_, _ = w.Write(body)
+		return
+	}
+
+	if v, ok := c.Get(cacheExpiresKey); ok {
+		entry.ExpiresAt = v.(time.Time).Unix()
+	}
+
+	if len(body) <= rc.opts.MaxBodyBytes {
+		ttl := rc.opts.TTL
+		if rc.opts.Stale > 0 && rc.opts.Handler != nil {
+			entry.FreshUntil = time.Now().Add(ttl).Unix()
+			ttl += rc.opts.Stale
 		}
+		// The store TTL is left alone so tag sets keep their lifetime;
+		// lookups drop the entry once ExpiresAt has passed.
+		if entry.ExpiresAt != 0 && entry.FreshUntil > entry.ExpiresAt {
+			entry.FreshUntil = entry.ExpiresAt
+		}
+		cache.Set(c.Request.Context(), key, entry, ttl, c.GetStringSlice(cacheTagsKey)...)
+		if f != nil {
+			f.resp = entry
+		}
+	}
 
-		if bw.body.Len() == 0 || bw.body.Len() > maxBodyBytes {
-			return
+	_ = send(c.Request, w, entry)
+}
+
+// lookup returns the entry stored for key, or nil if there is none or its
+// ExpiresAt has passed; fresh reports whether it is within FreshUntil.
+func lookup(ctx context.Context, key string) (entry *cache.CachedResponse, fresh bool) {
+	cached, ok := cache.Get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	now := time.Now().Unix()
+	if cached.ExpiresAt != 0 && now >= cached.ExpiresAt {
+		return nil, false
+	}
+	return cached, cached.FreshUntil == 0 || now < cached.FreshUntil
+}
+
+// serve writes a stored entry; result is the metrics label and, upper-cased,
+// the X-Cache value. It reports false if the entry is unusable.
+func (rc *responseCache) serve(c *gin.Context, cached *cache.CachedResponse, result string) bool {
+	c.Header("X-Cache", strings.ToUpper(result))
+	if err := send(c.Request, c.Writer, cached); err != nil {
+		c.Writer.Header().Del("X-Cache")
+		return false
+	}
+	metrics.CacheLookups.WithLabelValues(result).Inc()
+	c.Abort()
+	return true
+}
+
+// send writes entry in the coding negotiated from Accept-Encoding, or a 304
+// when the request's validators match. Nothing is written on error.
+func send(r *http.Request, w gin.ResponseWriter, entry *cache.CachedResponse) error {
+	coding := negotiateEncoding(r.Header.Get("Accept-Encoding"), entry.Encodings())
+	h := w.Header()
+	h.Set("Vary", "Accept-Language, Accept-Encoding")
+	if entry.ContentType != "" {
+		h.Set("Content-Type", entry.ContentType)
+	}
+	setValidators(h, encodedETag(entry.ETag, coding), entry.LastModified, capMaxAge(entry.CacheControl, entry.ExpiresAt))
+
+	if notModified(r, entry.ETag, entry.LastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		w.WriteHeaderNow()
+		return nil
+	}
+
+	body, err := entry.Encoded(coding)
+	if err != nil {
+		return err
+	}
+	if coding != cache.Identity {
+		h.Set("Content-Encoding", coding)
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(entry.Status)
+	_, _ = w.Write(body)
+	return nil
+}
+
+// ETag returns a strong entity tag for body.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func setValidators(h http.Header, etag, lastModified, control string) {
+	h.Set("ETag", etag)
+	if lastModified != "" {
+		h.Set("Last-Modified", lastModified)
+	}
+	if control != "" {
+		h.Set("Cache-Control", control)
+	}
+}
+
+// capMaxAge lowers max-age and s-maxage in control to the whole seconds left
+// until expiresAt (unix seconds, 0 for none), so browsers and proxies stop
+// reusing the response when the server-side entry expires.
+func capMaxAge(control string, expiresAt int64) string {
+	if expiresAt == 0 {
+		return control
+	}
+	left := int64(time.Until(time.Unix(expiresAt, 0)) / time.Second)
+	if left < 0 {
+		left = 0
+	}
+	parts := strings.Split(control, ",")
+	for i, part := range parts {
+		directive := strings.TrimSpace(part)
+		name, value, ok := strings.Cut(directive, "=")
+		if !ok || (!strings.EqualFold(name, "max-age") && !strings.EqualFold(name, "s-maxage")) {
+			continue
 		}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil && n > left {
+			parts[i] = strings.Replace(part, directive, name+"="+strconv.FormatInt(left, 10), 1)
+		}
+	}
+	return strings.Join(parts, ",")
+}
 
-		ct := bw.Header().Get("Content-Type")
-		if ct != "" && !strings.HasPrefix(ct, "application/json") {
-			// Only cache JSON responses to keep behavior predictable.
-			return
+// stripCoding removes the suffix added by encodedETag.
+func stripCoding(tag string) string {
+	for _, coding := range []string{cache.Gzip, cache.Brotli} {
+		if base, ok := strings.CutSuffix(tag, "-"+coding+`"`); ok {
+			return base + `"`
 		}
+	}
+	return tag
+}
 
-		cache.Set(c.Request.Context(), key, &cache.CachedResponse{
-			Status:      status,
-			ContentType: ct,
-			BodyB64:     base64.StdEncoding.EncodeToString(bw.body.Bytes()),
-		}, ttl)
+// notModified evaluates the request preconditions as in RFC 9110 section
+// 13.2.2: If-None-Match takes precedence, If-Modified-Since is only
+// considered when it is absent.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			// GET uses the weak comparison, so W/"x" matches "x"; every
+			// content coding of the entity matches too.
+			if tag == "*" || stripCoding(strings.TrimPrefix(tag, "W/")) == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
 	}
+	return !modified.After(since)
 }
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"backend/cache"
+	"backend/i18n"
+)
+
+// flight is one handler run whose response other requests for the same key
+// wait for. resp stays nil when the response was not cacheable.
+type flight struct {
+	done chan struct{}
+	resp *cache.CachedResponse
+}
+
+// refreshKey marks a background refresh request; its value is the flight
+// the request completes.
+type refreshKey struct{}
+
+// join returns the in-flight run for key, starting one if there is none;
+// leader reports whether the caller started it and must call finish.
+func (rc *responseCache) join(key string) (f *flight, leader bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if f, ok := rc.flights[key]; ok {
+		return f, false
+	}
+	f = &flight{done: make(chan struct{})}
+	rc.flights[key] = f
+	return f, true
+}
+
+func (rc *responseCache) finish(key string, f *flight) {
+	rc.mu.Lock()
+	delete(rc.flights, key)
+	rc.mu.Unlock()
+	close(f.done)
+}
+
+// refresh re-runs the request for a stale entry in the background, unless a
+// run for key is already in flight. Only the path, query and language are
+// replayed: credentials and conditional headers are not.
+func (rc *responseCache) refresh(r *http.Request, key string) {
+	f, leader := rc.join(key)
+	if !leader {
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), refreshKey{}, f)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL.RequestURI(), nil)
+	if err != nil {
+		rc.finish(key, f)
+		return
+	}
+	if lang := r.Header.Get("Accept-Language"); lang != "" {
+		req.Header.Set("Accept-Language", lang)
+	}
+
+	go func() {
+		defer rc.finish(key, f)
+		rc.opts.Handler.ServeHTTP(newDiscardWriter(), req)
+	}()
+}
+
+// warm requests every WarmPaths entry once per supported language; entries
+// still cached are plain hits, purged ones are rebuilt.
+func (rc *responseCache) warm() {
+	for _, path := range rc.opts.WarmPaths {
+		for _, locale := range i18n.Supported {
+			req, err := http.NewRequest(http.MethodGet, path, nil)
+			if err != nil {
+				slog.Warn("cache warm-up skipped", "path", path, "error", err)
+				break
+			}
+			req.Header.Set("Accept-Language", locale)
+			rc.opts.Handler.ServeHTTP(newDiscardWriter(), req)
+		}
+	}
+}
+
+// discardWriter is the ResponseWriter for internal requests whose only
+// purpose is to fill the cache.
+type discardWriter struct {
+	header http.Header
+}
+
+func newDiscardWriter() *discardWriter {
+	return &discardWriter{header: make(http.Header)}
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
@@ -0,0 +1,36 @@
+package models
+
+// ViewCount 某篇内容某天来自某个来源的浏览数，写入时累加到已有数据上
+// Referrer 为外部来源域名，直接访问或站内跳转为空；Visitors 为当天去重后的访客数
+type ViewCount struct {
+	Kind     string
+	ItemID   int
+	Day      string
+	Referrer string
+	Views    int
+	Visitors int
+}
+
+// ContentViews 热门内容排行中的一项（内容已删除时标题和路径为空）
+type ContentViews struct {
+	Kind     string `json:"kind"`
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Path     string `json:"path"`
+	Views    int    `json:"views"`
+	Visitors int    `json:"visitors"`
+}
+
+// DailyViews 每天的浏览数
+type DailyViews struct {
+	Day      string `json:"day"`
+	Views    int    `json:"views"`
+	Visitors int    `json:"visitors"`
+}
+
+// ReferrerViews 按来源域名汇总的浏览数，空字符串表示直接访问或站内跳转
+type ReferrerViews struct {
+	Referrer string `json:"referrer"`
+	Views    int    `json:"views"`
+	Visitors int    `json:"visitors"`
+}
@@ -4,24 +4,58 @@ import (
 	"time"
 )
 
+// Blog 博客文章；Path 为 URL 路径（slug），Meta* 为 SEO 字段
+// 列表排序：Pinned 置顶的在前，其次按 SortOrder 升序，最后按创建时间倒序；Featured 用于 ?featured=true 筛选
 type Blog struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Summary   string    `json:"summary"`
-	Content   string    `json:"content"`
-	Path      string    `json:"path"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              int       `json:"id"`
+	Title           string    `json:"title" binding:"required"`
+	Summary         string    `json:"summary"`
+	Content         string    `json:"content"`
+	Path            string    `json:"path"`
+	MetaTitle       string    `json:"meta_title"`
+	MetaDescription string    `json:"meta_description"`
+	MetaKeywords    string    `json:"meta_keywords"`
+	Featured        bool      `json:"featured"`
+	Pinned          bool      `json:"pinned"`
+	SortOrder       int       `json:"sort_order"`
+	CategoryIDs     []int     `json:"category_ids"`
+	CommentCount    int       `json:"comment_count"` // 已审核通过的评论数，只读
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// 以下字段只出现在公开接口的响应里：实际返回的语言和其他语言版本，
+	// 以及 ?include=related 时的相关内容
+	Locale     string          `json:"locale,omitempty"`
+	Alternates []Alternate     `json:"alternates,omitempty"`
+	Related    *RelatedContent `json:"related,omitempty"`
 }
 
+// Solution 解决方案；字段含义同 Blog
 type Solution struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"image_url"`
-	Path        string    `json:"path"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              int       `json:"id"`
+	Title           string    `json:"title" binding:"required"`
+	Description     string    `json:"description"`
+	ImageURL        string    `json:"image_url"`
+	Path            string    `json:"path"`
+	MetaTitle       string    `json:"meta_title"`
+	MetaDescription string    `json:"meta_description"`
+	MetaKeywords    string    `json:"meta_keywords"`
+	Featured        bool      `json:"featured"`
+	Pinned          bool      `json:"pinned"`
+	SortOrder       int       `json:"sort_order"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// 同 Blog，仅公开接口返回
+	Locale     string          `json:"locale,omitempty"`
+	Alternates []Alternate     `json:"alternates,omitempty"`
+	Related    *RelatedContent `json:"related,omitempty"`
+}
+
+// SortPosition 批量排序中的一项：把 ID 对应的博客或解决方案的 sort_order 设为 SortOrder
+type SortPosition struct {
+	ID        int `json:"id" binding:"required"`
+	SortOrder int `json:"sort_order"`
 }
 
 type Admin struct {
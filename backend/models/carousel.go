@@ -2,19 +2,44 @@ package models
 
 import "time"
 
+// 轮播图按钮（CTA）的跳转目标类型
+const (
+	CTATargetURL      = "url"      // 外部链接或站内路径，见 CTAURL 字段
+	CTATargetBlog     = "blog"     // 博客，按 ID 引用
+	CTATargetSolution = "solution" // 解决方案，按 ID 引用
+)
+
 // Carousel 表示首页轮播图配置
 // Position 用于区分顶部和底部轮播图，例如 "top" 或 "bottom"
+// 公开接口只返回 Active 为 true、处于 [StartsAt, EndsAt) 时间窗口内、语言匹配的轮播图
 type Carousel struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	ImageURL    string    `json:"image_url"`
-	AltText     string    `json:"alt_text"`
-	Description string    `json:"description"`
-	SortOrder   int       `json:"sort_order"`
-	Position    string    `json:"position"`
-	Rotation    int       `json:"rotation"`     // 图片旋转角度：0、90、180、270
-	ImageWidth  int       `json:"image_width"`  // 卡片宽度(px)，用于主页 Our Solutions 容器；0 表示默认
-	ImageHeight int       `json:"image_height"` // 卡片高度(px)，用于主页 Our Solutions 容器；0 表示正方形
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title" binding:"required"`
+	ImageURL    string     `json:"image_url" binding:"required"`
+	AltText     string     `json:"alt_text"`
+	Description string     `json:"description"`
+	SortOrder   int        `json:"sort_order"`
+	Position    string     `json:"position"`
+	Rotation    int        `json:"rotation"`     // 图片旋转角度：0、90、180、270
+	ImageWidth  int        `json:"image_width"`  // 卡片宽度(px)，用于主页 Our Solutions 容器；0 表示默认
+	ImageHeight int        `json:"image_height"` // 卡片高度(px)，用于主页 Our Solutions 容器；0 表示正方形
+	Active      *bool      `json:"active"`       // 设为 false 后不公开，不必删除；创建/更新时省略视为 true
+	StartsAt    *time.Time `json:"starts_at"`    // 开始展示时间；空表示立即
+	EndsAt      *time.Time `json:"ends_at"`      // 结束展示时间（不含）；空表示一直展示
+	Locale      string     `json:"locale"`       // 只向该语言的访客展示（en / zh）；空表示所有语言
+	// 按钮：CTAType 为空表示没有按钮；url 类型跳转 CTAURL，blog / solution 类型引用 CTARefID 对应的内容
+	CTALabel  string    `json:"cta_label" binding:"max=100"`
+	CTAType   string    `json:"cta_type" binding:"omitempty,oneof=url blog solution"`
+	CTAURL    string    `json:"cta_url" binding:"max=2000"`
+	CTARefID  int       `json:"cta_ref_id"`
+	CTAHref   string    `json:"cta_href"` // 只读，读取时解析出的跳转地址；引用的内容被删除后为空
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Showing 判断轮播图在 now 时是否处于展示时间窗口 [StartsAt, EndsAt) 内且已启用（不考虑语言）
+func (c *Carousel) Showing(now time.Time) bool {
+	return (c.Active == nil || *c.Active) &&
+		(c.StartsAt == nil || !now.Before(*c.StartsAt)) &&
+		(c.EndsAt == nil || now.Before(*c.EndsAt))
 }
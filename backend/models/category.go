@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Category 博客分类；Count 为关联的文章数量（只读）
+type Category struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Icon      string    `json:"icon"`
+	Color     string    `json:"color"`
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
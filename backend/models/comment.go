@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// 评论状态：新评论默认待审核，审核通过后才公开显示
+const (
+	CommentPending  = "pending"
+	CommentApproved = "approved"
+	CommentSpam     = "spam"
+)
+
+// Comment 博客评论；ParentID 为 0 表示顶层评论，否则是对该评论的回复
+// Email 和 NotifyReplies 只在管理接口中返回；公开接口按楼层返回，回复放在 Replies 中
+type Comment struct {
+	ID            int       `json:"id"`
+	BlogID        int       `json:"blog_id"`
+	ParentID      int       `json:"parent_id"`
+	Author        string    `json:"author"`
+	Email         string    `json:"email,omitempty"`
+	Content       string    `json:"content"`
+	Status        string    `json:"status"`
+	SpamReason    string    `json:"spam_reason,omitempty"`
+	NotifyReplies bool      `json:"notify_replies,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// BlogTitle 只出现在管理员的评论列表里
+	BlogTitle string    `json:"blog_title,omitempty"`
+	Replies   []Comment `json:"replies,omitempty"`
+}
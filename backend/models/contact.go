@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Contact 联系表单留言
+// 命中反垃圾规则的留言仍然保存，IsSpam 为 true，SpamReason 记录命中的规则
+type Contact struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Subject    string    `json:"subject"`
+	Message    string    `json:"message"`
+	IsSpam     bool      `json:"is_spam"`
+	SpamReason string    `json:"spam_reason"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// 订阅状态：订阅后先是待确认，点击邮件中的确认链接后才算订阅成功
+const (
+	SubscriberPending      = "pending"
+	SubscriberConfirmed    = "confirmed"
+	SubscriberUnsubscribed = "unsubscribed"
+)
+
+// Subscriber 邮件订阅者；Email 统一存小写
+type Subscriber struct {
+	ID             int        `json:"id"`
+	Email          string     `json:"email"`
+	Status         string     `json:"status"`
+	Locale         string     `json:"locale"`
+	ConfirmedAt    *time.Time `json:"confirmed_at"`
+	UnsubscribedAt *time.Time `json:"unsubscribed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Suppression 禁止发送列表：退订或被管理员加入的地址，再次订阅不会发送确认邮件，
+// 只有管理员移出列表后才能重新订阅
+type Suppression struct {
+	Email     string    `json:"email"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
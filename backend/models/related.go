@@ -0,0 +1,30 @@
+package models
+
+// RelatedIndex 一篇内容预先计算好的导航：按相似度排序的 Related，以及按发布时间的上一篇/下一篇（0 表示没有）
+type RelatedIndex struct {
+	Related  []RelatedMatch
+	Previous int
+	Next     int
+}
+
+// RelatedMatch 相关内容及其得分（TF-IDF 余弦相似度加上共同分类的加权）
+type RelatedMatch struct {
+	ID    int
+	Score float64
+}
+
+// RelatedContent 详情接口 ?include=related 时返回的导航块
+type RelatedContent struct {
+	Items    []RelatedLink `json:"items"`
+	Previous *RelatedLink  `json:"previous"`
+	Next     *RelatedLink  `json:"next"`
+}
+
+// RelatedLink 导航中的一项，标题和摘要按请求语言返回
+type RelatedLink struct {
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	Path    string  `json:"path"`
+	Summary string  `json:"summary,omitempty"`
+	Score   float64 `json:"score,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SocialLink 页脚社交媒体链接
+type SocialLink struct {
+	ID        int       `json:"id"`
+	Platform  string    `json:"platform" binding:"required"` // facebook, twitter, linkedin, instagram, youtube, github, wechat, weibo
+	URL       string    `json:"url" binding:"required"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Alternate 同一内容的其他语言版本，前端据此输出 <link rel="alternate" hreflang="...">
+type Alternate struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+}
+
+// BlogTranslation 博客的某个语言版本；原文（blogs 表）为默认语言，空字段回退到原文
+type BlogTranslation struct {
+	BlogID          int       `json:"blog_id"`
+	Locale          string    `json:"locale"`
+	Title           string    `json:"title" binding:"required"`
+	Summary         string    `json:"summary"`
+	Content         string    `json:"content"`
+	MetaTitle       string    `json:"meta_title"`
+	MetaDescription string    `json:"meta_description"`
+	MetaKeywords    string    `json:"meta_keywords"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SolutionTranslation 解决方案的某个语言版本；字段含义同 BlogTranslation
+type SolutionTranslation struct {
+	SolutionID      int       `json:"solution_id"`
+	Locale          string    `json:"locale"`
+	Title           string    `json:"title" binding:"required"`
+	Description     string    `json:"description"`
+	MetaTitle       string    `json:"meta_title"`
+	MetaDescription string    `json:"meta_description"`
+	MetaKeywords    string    `json:"meta_keywords"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Localize 用译文覆盖博客的非空字段，并记录实际使用的语言；译文更新更晚时 updated_at 取译文的
+func (b *Blog) Localize(t BlogTranslation) {
+	b.Locale = t.Locale
+	b.Title = t.Title
+	b.Summary = orDefault(t.Summary, b.Summary)
+	b.Content = orDefault(t.Content, b.Content)
+	b.MetaTitle = orDefault(t.MetaTitle, b.MetaTitle)
+	b.MetaDescription = orDefault(t.MetaDescription, b.MetaDescription)
+	b.MetaKeywords = orDefault(t.MetaKeywords, b.MetaKeywords)
+	if t.UpdatedAt.After(b.UpdatedAt) {
+		b.UpdatedAt = t.UpdatedAt
+	}
+}
+
+// Localize 用译文覆盖解决方案的非空字段，并记录实际使用的语言；updated_at 规则同上
+func (s *Solution) Localize(t SolutionTranslation) {
+	s.Locale = t.Locale
+	s.Title = t.Title
+	s.Description = orDefault(t.Description, s.Description)
+	s.MetaTitle = orDefault(t.MetaTitle, s.MetaTitle)
+	s.MetaDescription = orDefault(t.MetaDescription, s.MetaDescription)
+	s.MetaKeywords = orDefault(t.MetaKeywords, s.MetaKeywords)
+	if t.UpdatedAt.After(s.UpdatedAt) {
+		s.UpdatedAt = t.UpdatedAt
+	}
+}
+
+func orDefault(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
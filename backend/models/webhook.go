@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook 表示一个出站 Webhook 订阅
+// Events 为事件类型列表，例如 "blog.published"；包含 "*" 表示订阅全部事件
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // 用于 HMAC-SHA256 签名；列表接口不返回
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Listens 判断订阅是否接收 event 类型的事件
+func (w *Webhook) Listens(event string) bool {
+	for _, e := range w.Events {
+		if e = strings.TrimSpace(e); e == "*" || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery 表示一次事件投递（同时作为重试队列和投递日志）
+// Status: pending（等待投递/重试）、success、failed（超过最大重试次数）
+type WebhookDelivery struct {
+	ID             int       `json:"id"`
+	WebhookID      int       `json:"webhook_id"`
+	Event          string    `json:"event"`
+	Payload        string    `json:"payload"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	ResponseStatus int       `json:"response_status"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
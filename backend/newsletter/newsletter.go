@@ -0,0 +1,131 @@
+// Package newsletter signs the links in newsletter emails and writes those
+// emails.
+//
+// A token binds an email address to one purpose (confirming a subscription
+// or unsubscribing) with an HMAC-SHA256 over purpose, address and issue
+// time, so links need no server-side state and cannot be forged or reused
+// for the other purpose. Confirmation links expire; unsubscribe links never
+// do, because they sit in mailboxes indefinitely.
+package newsletter
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/mailer"
+)
+
+// Token purposes.
+const (
+	PurposeConfirm     = "confirm"
+	PurposeUnsubscribe = "unsubscribe"
+)
+
+var (
+	ErrTokenInvalid = errors.New("invalid newsletter token")
+	ErrTokenExpired = errors.New("newsletter token expired")
+)
+
+// Config holds the settings for links and tokens.
+type Config struct {
+	Secret     []byte
+	ConfirmTTL time.Duration
+	// SiteURL is the frontend the links point at.
+	SiteURL string
+}
+
+var (
+	cfgMu sync.RWMutex
+	cfg   = Config{Secret: randomSecret(), ConfirmTTL: 48 * time.Hour, SiteURL: "http://localhost:3000"}
+)
+
+// Configure replaces the settings. An empty Secret keeps a random
+// per-process key, which invalidates every link on restart.
+func Configure(c Config) {
+	if len(c.Secret) == 0 {
+		c.Secret = randomSecret()
+	}
+	c.SiteURL = strings.TrimRight(c.SiteURL, "/")
+	cfgMu.Lock()
+	cfg = c
+	cfgMu.Unlock()
+}
+
+func settings() Config {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
+	return cfg
+}
+
+func randomSecret() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// Token returns a signed token for email and purpose:
+// base64url(email) "." unix-time "." base64url(hmac).
+func Token(purpose, email string, now time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(email)) + "." + strconv.FormatInt(now.Unix(), 10)
+	return payload + "." + sign(purpose, payload)
+}
+
+// Verify checks a token issued for purpose and returns its email address.
+func Verify(token, purpose string, now time.Time) (string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 || !hmac.Equal([]byte(token[i+1:]), []byte(sign(purpose, token[:i]))) {
+		return "", ErrTokenInvalid
+	}
+	encoded, ts, ok := strings.Cut(token[:i], ".")
+	if !ok {
+		return "", ErrTokenInvalid
+	}
+	email, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+	if ttl := settings().ConfirmTTL; purpose == PurposeConfirm && ttl > 0 && now.Sub(time.Unix(unix, 0)) > ttl {
+		return "", ErrTokenExpired
+	}
+	return string(email), nil
+}
+
+func sign(purpose, payload string) string {
+	mac := hmac.New(sha256.New, settings().Secret)
+	mac.Write([]byte(purpose + "\n" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Link returns the frontend page for purpose carrying a fresh token, e.g.
+// https://example.com/newsletter/confirm?token=...
+func Link(purpose, email string, now time.Time) string {
+	return settings().SiteURL + "/newsletter/" + purpose + "?token=" + url.QueryEscape(Token(purpose, email, now))
+}
+
+// ConfirmationMessage is the double opt-in email sent after subscribing.
+func ConfirmationMessage(email string, now time.Time) mailer.Message {
+	unsubscribe := Link(PurposeUnsubscribe, email, now)
+	return mailer.Message{
+		To:      email,
+		Subject: "Please confirm your subscription",
+		Text: "Thanks for subscribing to our newsletter.\n\n" +
+			"Please confirm your email address by opening this link:\n" +
+			Link(PurposeConfirm, email, now) + "\n\n" +
+			"The link is valid for " + strconv.Itoa(int(settings().ConfirmTTL.Hours())) + " hours. " +
+			"If you did not subscribe, ignore this email and you will not hear from us again.\n\n" +
+			"Unsubscribe: " + unsubscribe + "\n",
+		Headers: map[string]string{"List-Unsubscribe": "<" + unsubscribe + ">"},
+	}
+}
@@ -0,0 +1,155 @@
+// Package related ranks blogs and solutions against each other so detail
+// pages can link to similar content. It only computes: the controllers run
+// Compute after every write and store the result through
+// repository.RelatedRepository, so reads never pay for it.
+package related
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"backend/models"
+)
+
+// CategoryWeight is added to the text similarity of two documents with
+// identical category sets; partial overlaps get a share of it by their
+// Jaccard index.
+const CategoryWeight = 0.5
+
+// Document is one item of a collection.
+type Document struct {
+	ID         int
+	Text       string
+	Categories []int
+	Published  time.Time
+}
+
+// Compute returns, for every document, up to limit related documents by
+// descending score (TF-IDF cosine similarity plus category overlap, zero
+// scores excluded) and its neighbours by publish date.
+func Compute(docs []Document, limit int) map[int]models.RelatedIndex {
+	index := make(map[int]models.RelatedIndex, len(docs))
+	vectors := weigh(docs)
+	published := make(map[int]time.Time, len(docs))
+	for _, d := range docs {
+		published[d.ID] = d.Published
+	}
+
+	for i, d := range docs {
+		var matches []models.RelatedMatch
+		for j, other := range docs {
+			if i == j {
+				continue
+			}
+			score := cosine(vectors[i], vectors[j]) + CategoryWeight*jaccard(d.Categories, other.Categories)
+			if score > 0 {
+				matches = append(matches, models.RelatedMatch{ID: other.ID, Score: math.Round(score*1e4) / 1e4})
+			}
+		}
+		sort.SliceStable(matches, func(a, b int) bool {
+			if matches[a].Score != matches[b].Score {
+				return matches[a].Score > matches[b].Score
+			}
+			return published[matches[a].ID].After(published[matches[b].ID])
+		})
+		if len(matches) > limit {
+			matches = matches[:limit]
+		}
+		index[d.ID] = models.RelatedIndex{Related: matches}
+	}
+
+	// Previous is the next older document, Next the next newer one.
+	byDate := make([]Document, len(docs))
+	copy(byDate, docs)
+	sort.SliceStable(byDate, func(a, b int) bool {
+		if !byDate[a].Published.Equal(byDate[b].Published) {
+			return byDate[a].Published.Before(byDate[b].Published)
+		}
+		return byDate[a].ID < byDate[b].ID
+	})
+	for i, d := range byDate {
+		entry := index[d.ID]
+		if i > 0 {
+			entry.Previous = byDate[i-1].ID
+		}
+		if i < len(byDate)-1 {
+			entry.Next = byDate[i+1].ID
+		}
+		index[d.ID] = entry
+	}
+	return index
+}
+
+// weigh turns each document into an L2-normalised TF-IDF vector. Term
+// frequency is dampened logarithmically so long posts repeating a word do
+// not drown out short ones. IDF is smoothed (+1) so terms every document
+// shares still count in small collections.
+func weigh(docs []Document) []map[string]float64 {
+	counts := make([]map[string]int, len(docs))
+	df := map[string]int{}
+	for i, d := range docs {
+		counts[i] = map[string]int{}
+		for _, t := range Tokenize(d.Text) {
+			if counts[i][t] == 0 {
+				df[t]++
+			}
+			counts[i][t]++
+		}
+	}
+
+	n := float64(len(docs))
+	vectors := make([]map[string]float64, len(docs))
+	for i, c := range counts {
+		v := make(map[string]float64, len(c))
+		var norm float64
+		for t, k := range c {
+			w := (1 + math.Log(float64(k))) * (1 + math.Log((1+n)/(1+float64(df[t]))))
+			v[t] = w
+			norm += w * w
+		}
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for t := range v {
+				v[t] /= norm
+			}
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var dot float64
+	for t, w := range a {
+		dot += w * b[t]
+	}
+	return dot
+}
+
+func jaccard(a, b []int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[int]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	shared, union := 0, len(set)
+	seen := map[int]bool{}
+	for _, id := range b {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if set[id] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,86 @@
+package related
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"backend/models"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"The Solar inverter, and the 2 panels!", []string{"solar", "inverter", "panels"}},
+		{"See https://www.example.com/a.png", []string{"see", "example"}},
+		{"智能电网", []string{"智能", "能电", "电网"}},
+		{"储能 grid 网", []string{"储能", "grid", "网"}},
+		{"v2 model 42", []string{"v2", "model"}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC) }
+	const (
+		inverter = iota + 1
+		wind
+		solar
+		grid
+		storage
+	)
+	docs := []Document{
+		{inverter, "Solar inverter maintenance. Cleaning inverter fans keeps solar output high.", []int{1}, day(1)},
+		{wind, "Wind turbine blades. Blade inspection with drones.", []int{2}, day(2)},
+		{solar, "Solar panel and inverter efficiency. Pairing panels with the right inverter.", []int{1}, day(3)},
+		{grid, "智能电网调度 智能电网的负荷调度与预测", nil, day(4)},
+		{storage, "智能电网储能 储能系统接入智能电网", nil, day(5)},
+	}
+	index := Compute(docs, 5)
+	ids := func(matches []models.RelatedMatch) []int {
+		var out []int
+		for _, m := range matches {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	if got := ids(index[solar].Related); !slices.Equal(got, []int{inverter}) {
+		t.Errorf("related to solar = %v, want only the inverter post", got)
+	}
+	// CJK text is compared by character bigrams.
+	if got := ids(index[grid].Related); !slices.Equal(got, []int{storage}) {
+		t.Errorf("related to grid = %v, want [%d]", got, storage)
+	}
+	if got := index[wind].Related; len(got) != 0 {
+		t.Errorf("related to wind = %v, want none", ids(got))
+	}
+
+	// A shared category adds to the score without any common words.
+	withCategory := Compute([]Document{
+		{1, "alpha", []int{7}, day(1)},
+		{2, "beta", []int{7}, day(2)},
+		{3, "gamma", []int{8}, day(3)},
+	}, 5)
+	if got := withCategory[1].Related; len(got) != 1 || got[0].ID != 2 || got[0].Score != CategoryWeight {
+		t.Errorf("related by category = %+v, want 2 with score %v", got, CategoryWeight)
+	}
+
+	// Previous and next follow the publish date.
+	if e := index[solar]; e.Previous != wind || e.Next != grid {
+		t.Errorf("solar previous/next = %d/%d, want %d/%d", e.Previous, e.Next, wind, grid)
+	}
+	if e := index[inverter]; e.Previous != 0 || e.Next != wind {
+		t.Errorf("inverter previous/next = %d/%d, want 0/%d", e.Previous, e.Next, wind)
+	}
+
+	if got := Compute(docs, 0)[solar].Related; len(got) != 0 {
+		t.Errorf("limit 0 returned %v", ids(got))
+	}
+}
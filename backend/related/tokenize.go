@@ -0,0 +1,68 @@
+package related
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopwords are dropped from Latin-script text. Markdown link noise (http,
+// www, com) is included because post bodies are Markdown.
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a about after all also an and any are as at be been but by can could do does
+		for from had has have how if in into is it its may more most no not of on or our out over so some such
+		than that the their them then there these they this those to up us was we were what when where which
+		while who will with would you your http https www com html png jpg`) {
+		stopwords[w] = true
+	}
+}
+
+// Tokenize splits text into lower-case terms. Runs of letters and digits
+// form words (at least two characters, one of them a letter, stopwords
+// removed). Chinese, Japanese and Korean have no spaces between words, so
+// runs of those scripts become overlapping character bigrams ("智能电网" →
+// 智能, 能电, 电网); a lone character is kept as is.
+func Tokenize(text string) []string {
+	var tokens []string
+	var word, cjk []rune
+
+	flushWord := func() {
+		if len(word) >= 2 && strings.IndexFunc(string(word), unicode.IsLetter) >= 0 {
+			if w := string(word); !stopwords[w] {
+				tokens = append(tokens, w)
+			}
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			tokens = append(tokens, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			tokens = append(tokens, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
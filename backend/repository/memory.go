@@ -0,0 +1,1067 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NewMemory returns in-memory repositories for tests. Like a freshly
+// initialised database it contains the default admin account
+// (admin/admin123) and the default blog categories, and it enforces the same
+// unique columns (blog and solution paths, admin usernames).
+func NewMemory() Set {
+	now := time.Now().UTC()
+	categories := &memoryCategories{}
+	for i, c := range []models.Category{
+		{Name: "Industry Trends", Slug: "industry-trends", Icon: "FaIndustry", Color: "from-sky-500 to-cyan-400"},
+		{Name: "Product Updates", Slug: "product-updates", Icon: "FaBoxOpen", Color: "from-indigo-500 to-sky-500"},
+		{Name: "Solutions", Slug: "solutions", Icon: "FaLightbulb", Color: "from-blue-500 to-emerald-400"},
+		{Name: "Tech Insights", Slug: "tech-insights", Icon: "FaMicrochip", Color: "from-cyan-500 to-blue-600"},
+	} {
+		c.ID, c.CreatedAt, c.UpdatedAt = i+1, now, now
+		categories.items = append(categories.items, c)
+	}
+
+	admins := &memoryAdmins{}
+	admins.items = []models.Admin{{ID: 1, Username: "admin", Password: "admin123", CreatedAt: now}}
+
+	comments := &memoryComments{}
+	return Set{
+		Blogs:       &memoryBlogs{comments: comments},
+		Solutions:   &memorySolutions{},
+		Carousels:   &memoryCarousels{},
+		Categories:  categories,
+		SocialLinks: &memorySocialLinks{},
+		Contacts:    &memoryContacts{},
+		Comments:    comments,
+		Newsletter:  &memoryNewsletter{},
+		Admins:      admins,
+		Webhooks:    &memoryWebhooks{},
+		Related:     &memoryRelated{},
+		Analytics:   &memoryAnalytics{},
+	}
+}
+
+// memoryTable is a mutex-guarded slice with an auto-increment ID, shared by
+// the fakes below.
+type memoryTable[T any] struct {
+	mu     sync.Mutex
+	items  []T
+	nextID int
+}
+
+func (t *memoryTable[T]) newID() int {
+	if t.nextID == 0 {
+		t.nextID = len(t.items)
+	}
+	t.nextID++
+	return t.nextID
+}
+
+func (t *memoryTable[T]) find(match func(*T) bool) (int, bool) {
+	for i := range t.items {
+		if match(&t.items[i]) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (t *memoryTable[T]) remove(match func(*T) bool) {
+	kept := t.items[:0]
+	for _, item := range t.items {
+		if !match(&item) {
+			kept = append(kept, item)
+		}
+	}
+	t.items = kept
+}
+
+// snapshot returns a sorted copy so callers never share the backing array.
+func (t *memoryTable[T]) snapshot(filter func(*T) bool, less func(a, b *T) bool) []T {
+	out := []T{}
+	for _, item := range t.items {
+		if filter == nil || filter(&item) {
+			out = append(out, item)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return less(&out[i], &out[j]) })
+	return out
+}
+
+// newestFirst orders by creation time, falling back to ID for rows created
+// within the same instant.
+func newestFirst(ta, tb time.Time, ida, idb int) bool {
+	if !ta.Equal(tb) {
+		return ta.After(tb)
+	}
+	return ida > idb
+}
+
+// contentFirst is the blog and solution list order: pinned first, then by
+// ascending sort order, then newest first.
+func contentFirst(pa, pb bool, sa, sb int, ta, tb time.Time, ida, idb int) bool {
+	if pa != pb {
+		return pa
+	}
+	if sa != sb {
+		return sa < sb
+	}
+	return newestFirst(ta, tb, ida, idb)
+}
+
+// featured returns a snapshot filter for f, or nil to keep every row.
+func featured[T any](f ContentFilter, isFeatured func(*T) bool) func(*T) bool {
+	if f.Featured == nil {
+		return nil
+	}
+	return func(v *T) bool { return isFeatured(v) == *f.Featured }
+}
+
+// reorderItems applies positions to items only after checking every ID
+// exists, matching the all-or-nothing SQLite transaction.
+func reorderItems[T any](items []T, positions []models.SortPosition, id func(*T) int, set func(*T, int)) error {
+	index := make(map[int]int, len(items))
+	for i := range items {
+		index[id(&items[i])] = i
+	}
+	for _, p := range positions {
+		if _, ok := index[p.ID]; !ok {
+			return ErrNotFound
+		}
+	}
+	for _, p := range positions {
+		set(&items[index[p.ID]], p.SortOrder)
+	}
+	return nil
+}
+
+// sortedIDs returns a sorted copy of ids without duplicates, never nil, like
+// the SQLite repository reads category links back.
+func sortedIDs(ids []int) []int {
+	out := []int{}
+	for _, id := range ids {
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// ---- blogs ----
+
+// memoryBlogs reads comment counts from comments; it always locks itself
+// before comments, never the other way round.
+type memoryBlogs struct {
+	memoryTable[models.Blog]
+	translations []models.BlogTranslation
+	comments     *memoryComments
+}
+
+// withCount returns b with its approved comment count filled in.
+func (r *memoryBlogs) withCount(b models.Blog) models.Blog {
+	b.CommentCount = r.comments.approved(b.ID)
+	return b
+}
+
+func (r *memoryBlogs) List(ctx context.Context, f ContentFilter) ([]models.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blogs := r.snapshot(featured(f, func(b *models.Blog) bool { return b.Featured }), func(a, b *models.Blog) bool {
+		return contentFirst(a.Pinned, b.Pinned, a.SortOrder, b.SortOrder, a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	for i := range blogs {
+		blogs[i] = r.withCount(blogs[i])
+	}
+	return blogs, nil
+}
+
+func (r *memoryBlogs) Get(ctx context.Context, key string) (models.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(b *models.Blog) bool { return b.Path == key || strconv.Itoa(b.ID) == key }); ok {
+		return r.withCount(r.items[i]), nil
+	}
+	return models.Blog{}, ErrNotFound
+}
+
+func (r *memoryBlogs) GetByPath(ctx context.Context, path string) (models.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(b *models.Blog) bool { return b.Path == path }); ok {
+		return r.withCount(r.items[i]), nil
+	}
+	return models.Blog{}, ErrNotFound
+}
+
+func (r *memoryBlogs) Create(ctx context.Context, b *models.Blog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, taken := r.find(func(x *models.Blog) bool { return x.Path == b.Path }); taken {
+		return &ConflictError{Field: "path"}
+	}
+	b.ID = r.newID()
+	b.CreatedAt = time.Now().UTC()
+	b.UpdatedAt = b.CreatedAt
+	b.CategoryIDs = sortedIDs(b.CategoryIDs)
+	b.CommentCount = 0
+	r.items = append(r.items, *b)
+	return nil
+}
+
+func (r *memoryBlogs) Update(ctx context.Context, b *models.Blog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(x *models.Blog) bool { return x.ID == b.ID })
+	if !ok {
+		return ErrNotFound
+	}
+	if _, taken := r.find(func(x *models.Blog) bool { return x.ID != b.ID && x.Path == b.Path }); taken {
+		return &ConflictError{Field: "path"}
+	}
+	b.CreatedAt = r.items[i].CreatedAt
+	b.UpdatedAt = time.Now().UTC()
+	b.CategoryIDs = sortedIDs(b.CategoryIDs)
+	r.items[i] = *b
+	return nil
+}
+
+func (r *memoryBlogs) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(b *models.Blog) bool { return b.ID == id })
+	r.removeTranslations(id)
+	r.comments.removeBlog(id)
+	return nil
+}
+
+func (r *memoryBlogs) Reorder(ctx context.Context, positions []models.SortPosition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	return reorderItems(r.items, positions, func(b *models.Blog) int { return b.ID }, func(b *models.Blog, order int) {
+		b.SortOrder, b.UpdatedAt = order, now
+	})
+}
+
+// ---- blog translations ----
+
+func (r *memoryBlogs) exists(id int) bool {
+	_, ok := r.find(func(x *models.Blog) bool { return x.ID == id })
+	return ok
+}
+
+func (r *memoryBlogs) Translations(ctx context.Context, id int) ([]models.BlogTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.exists(id) {
+		return nil, ErrNotFound
+	}
+	out := []models.BlogTranslation{}
+	for _, t := range r.translations {
+		if t.BlogID == id {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Locale < out[j].Locale })
+	return out, nil
+}
+
+func (r *memoryBlogs) TranslationsByLocale(ctx context.Context, locale string) (map[int]models.BlogTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := map[int]models.BlogTranslation{}
+	for _, t := range r.translations {
+		if t.Locale == locale {
+			out[t.BlogID] = t
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryBlogs) SaveTranslation(ctx context.Context, t *models.BlogTranslation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.exists(t.BlogID) {
+		return ErrNotFound
+	}
+	t.UpdatedAt = time.Now().UTC()
+	t.CreatedAt = t.UpdatedAt
+	for i, x := range r.translations {
+		if x.BlogID == t.BlogID && x.Locale == t.Locale {
+			t.CreatedAt = x.CreatedAt
+			r.translations[i] = *t
+			return nil
+		}
+	}
+	r.translations = append(r.translations, *t)
+	return nil
+}
+
+func (r *memoryBlogs) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, x := range r.translations {
+		if x.BlogID == id && x.Locale == locale {
+			r.translations = append(r.translations[:i], r.translations[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryBlogs) removeTranslations(id int) {
+	kept := r.translations[:0]
+	for _, t := range r.translations {
+		if t.BlogID != id {
+			kept = append(kept, t)
+		}
+	}
+	r.translations = kept
+}
+
+// ---- solutions ----
+
+type memorySolutions struct {
+	memoryTable[models.Solution]
+	translations []models.SolutionTranslation
+}
+
+func (r *memorySolutions) List(ctx context.Context, f ContentFilter) ([]models.Solution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(featured(f, func(s *models.Solution) bool { return s.Featured }), func(a, b *models.Solution) bool {
+		return contentFirst(a.Pinned, b.Pinned, a.SortOrder, b.SortOrder, a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}), nil
+}
+
+func (r *memorySolutions) Get(ctx context.Context, key string) (models.Solution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(s *models.Solution) bool { return s.Path == key || strconv.Itoa(s.ID) == key }); ok {
+		return r.items[i], nil
+	}
+	return models.Solution{}, ErrNotFound
+}
+
+func (r *memorySolutions) GetByPath(ctx context.Context, path string) (models.Solution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(s *models.Solution) bool { return s.Path == path }); ok {
+		return r.items[i], nil
+	}
+	return models.Solution{}, ErrNotFound
+}
+
+func (r *memorySolutions) Create(ctx context.Context, s *models.Solution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, taken := r.find(func(x *models.Solution) bool { return x.Path == s.Path }); taken {
+		return &ConflictError{Field: "path"}
+	}
+	s.ID = r.newID()
+	s.CreatedAt = time.Now().UTC()
+	s.UpdatedAt = s.CreatedAt
+	r.items = append(r.items, *s)
+	return nil
+}
+
+func (r *memorySolutions) Update(ctx context.Context, s *models.Solution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(x *models.Solution) bool { return x.ID == s.ID })
+	if !ok {
+		return ErrNotFound
+	}
+	if _, taken := r.find(func(x *models.Solution) bool { return x.ID != s.ID && x.Path == s.Path }); taken {
+		return &ConflictError{Field: "path"}
+	}
+	s.CreatedAt = r.items[i].CreatedAt
+	s.UpdatedAt = time.Now().UTC()
+	r.items[i] = *s
+	return nil
+}
+
+func (r *memorySolutions) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(s *models.Solution) bool { return s.ID == id })
+	r.removeTranslations(id)
+	return nil
+}
+
+func (r *memorySolutions) Reorder(ctx context.Context, positions []models.SortPosition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	return reorderItems(r.items, positions, func(s *models.Solution) int { return s.ID }, func(s *models.Solution, order int) {
+		s.SortOrder, s.UpdatedAt = order, now
+	})
+}
+
+// ---- solution translations ----
+
+func (r *memorySolutions) exists(id int) bool {
+	_, ok := r.find(func(x *models.Solution) bool { return x.ID == id })
+	return ok
+}
+
+func (r *memorySolutions) Translations(ctx context.Context, id int) ([]models.SolutionTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.exists(id) {
+		return nil, ErrNotFound
+	}
+	out := []models.SolutionTranslation{}
+	for _, t := range r.translations {
+		if t.SolutionID == id {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Locale < out[j].Locale })
+	return out, nil
+}
+
+func (r *memorySolutions) TranslationsByLocale(ctx context.Context, locale string) (map[int]models.SolutionTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := map[int]models.SolutionTranslation{}
+	for _, t := range r.translations {
+		if t.Locale == locale {
+			out[t.SolutionID] = t
+		}
+	}
+	return out, nil
+}
+
+func (r *memorySolutions) SaveTranslation(ctx context.Context, t *models.SolutionTranslation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.exists(t.SolutionID) {
+		return ErrNotFound
+	}
+	t.UpdatedAt = time.Now().UTC()
+	t.CreatedAt = t.UpdatedAt
+	for i, x := range r.translations {
+		if x.SolutionID == t.SolutionID && x.Locale == t.Locale {
+			t.CreatedAt = x.CreatedAt
+			r.translations[i] = *t
+			return nil
+		}
+	}
+	r.translations = append(r.translations, *t)
+	return nil
+}
+
+func (r *memorySolutions) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, x := range r.translations {
+		if x.SolutionID == id && x.Locale == locale {
+			r.translations = append(r.translations[:i], r.translations[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memorySolutions) removeTranslations(id int) {
+	kept := r.translations[:0]
+	for _, t := range r.translations {
+		if t.SolutionID != id {
+			kept = append(kept, t)
+		}
+	}
+	r.translations = kept
+}
+
+// ---- carousels ----
+
+type memoryCarousels struct{ memoryTable[models.Carousel] }
+
+func (r *memoryCarousels) List(ctx context.Context, position string) ([]models.Carousel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var filter func(*models.Carousel) bool
+	if position != "" {
+		filter = func(c *models.Carousel) bool { return c.Position == position }
+	}
+	return r.snapshot(filter, func(a, b *models.Carousel) bool {
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}), nil
+}
+
+func (r *memoryCarousels) Create(ctx context.Context, c *models.Carousel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = r.newID()
+	c.CreatedAt = time.Now().UTC()
+	c.UpdatedAt = c.CreatedAt
+	r.items = append(r.items, *c)
+	return nil
+}
+
+func (r *memoryCarousels) Update(ctx context.Context, c *models.Carousel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(x *models.Carousel) bool { return x.ID == c.ID })
+	if !ok {
+		return ErrNotFound
+	}
+	c.CreatedAt = r.items[i].CreatedAt
+	c.UpdatedAt = time.Now().UTC()
+	r.items[i] = *c
+	return nil
+}
+
+func (r *memoryCarousels) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(c *models.Carousel) bool { return c.ID == id })
+	return nil
+}
+
+// ---- categories ----
+
+type memoryCategories struct{ memoryTable[models.Category] }
+
+func (r *memoryCategories) List(ctx context.Context) ([]models.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(nil, func(a, b *models.Category) bool { return a.ID < b.ID }), nil
+}
+
+func (r *memoryCategories) Get(ctx context.Context, id int) (models.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(c *models.Category) bool { return c.ID == id }); ok {
+		return r.items[i], nil
+	}
+	return models.Category{}, ErrNotFound
+}
+
+// ---- social links ----
+
+type memorySocialLinks struct{ memoryTable[models.SocialLink] }
+
+func (r *memorySocialLinks) List(ctx context.Context) ([]models.SocialLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(nil, func(a, b *models.SocialLink) bool { return a.SortOrder < b.SortOrder }), nil
+}
+
+func (r *memorySocialLinks) Create(ctx context.Context, l *models.SocialLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l.ID = r.newID()
+	l.CreatedAt = time.Now().UTC()
+	l.UpdatedAt = l.CreatedAt
+	r.items = append(r.items, *l)
+	return nil
+}
+
+func (r *memorySocialLinks) Update(ctx context.Context, l *models.SocialLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(x *models.SocialLink) bool { return x.ID == l.ID })
+	if !ok {
+		return ErrNotFound
+	}
+	l.CreatedAt = r.items[i].CreatedAt
+	l.UpdatedAt = time.Now().UTC()
+	r.items[i] = *l
+	return nil
+}
+
+func (r *memorySocialLinks) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(l *models.SocialLink) bool { return l.ID == id })
+	return nil
+}
+
+// ---- contacts ----
+
+type memoryContacts struct{ memoryTable[models.Contact] }
+
+func (r *memoryContacts) List(ctx context.Context, f ContactFilter) ([]models.Contact, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var filter func(*models.Contact) bool
+	if f.Spam != nil {
+		filter = func(c *models.Contact) bool { return c.IsSpam == *f.Spam }
+	}
+	return r.snapshot(filter, func(a, b *models.Contact) bool { return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID) }), nil
+}
+
+func (r *memoryContacts) Create(ctx context.Context, c *models.Contact) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = r.newID()
+	c.CreatedAt = time.Now().UTC()
+	c.UpdatedAt = c.CreatedAt
+	r.items = append(r.items, *c)
+	return nil
+}
+
+func (r *memoryContacts) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(c *models.Contact) bool { return c.ID == id })
+	return nil
+}
+
+// ---- comments ----
+
+type memoryComments struct {
+	memoryTable[models.Comment]
+	notified map[int]bool
+}
+
+func (r *memoryComments) List(ctx context.Context, f CommentFilter) ([]models.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(func(c *models.Comment) bool {
+		return (f.BlogID == 0 || c.BlogID == f.BlogID) && (f.Status == "" || c.Status == f.Status)
+	}, func(a, b *models.Comment) bool { return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID) }), nil
+}
+
+func (r *memoryComments) Get(ctx context.Context, id int) (models.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(c *models.Comment) bool { return c.ID == id }); ok {
+		return r.items[i], nil
+	}
+	return models.Comment{}, ErrNotFound
+}
+
+func (r *memoryComments) Create(ctx context.Context, c *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ID = r.newID()
+	c.CreatedAt = time.Now().UTC()
+	c.UpdatedAt = c.CreatedAt
+	r.items = append(r.items, *c)
+	return nil
+}
+
+func (r *memoryComments) SetStatus(ctx context.Context, id int, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(c *models.Comment) bool { return c.ID == id })
+	if !ok {
+		return ErrNotFound
+	}
+	r.items[i].Status = status
+	r.items[i].UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (r *memoryComments) MarkNotified(ctx context.Context, id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.find(func(c *models.Comment) bool { return c.ID == id }); !ok || r.notified[id] {
+		return false, nil
+	}
+	if r.notified == nil {
+		r.notified = map[int]bool{}
+	}
+	r.notified[id] = true
+	return true, nil
+}
+
+func (r *memoryComments) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	thread := map[int]bool{id: true}
+	// Replies always have larger IDs than their parents, so one pass in
+	// insertion order collects the whole subtree.
+	for _, c := range r.items {
+		if thread[c.ParentID] {
+			thread[c.ID] = true
+		}
+	}
+	r.remove(func(c *models.Comment) bool { return thread[c.ID] })
+	return nil
+}
+
+// approved counts the post's approved comments.
+func (r *memoryComments) approved(blogID int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.items {
+		if c.BlogID == blogID && c.Status == models.CommentApproved {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *memoryComments) removeBlog(blogID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(c *models.Comment) bool { return c.BlogID == blogID })
+}
+
+// ---- newsletter ----
+
+type memoryNewsletter struct {
+	memoryTable[models.Subscriber]
+	suppressions []models.Suppression
+}
+
+func (r *memoryNewsletter) ListSubscribers(ctx context.Context, status string) ([]models.Subscriber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(func(s *models.Subscriber) bool { return status == "" || s.Status == status },
+		func(a, b *models.Subscriber) bool { return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID) }), nil
+}
+
+func (r *memoryNewsletter) GetSubscriber(ctx context.Context, email string) (models.Subscriber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(s *models.Subscriber) bool { return s.Email == email }); ok {
+		return r.items[i], nil
+	}
+	return models.Subscriber{}, ErrNotFound
+}
+
+func (r *memoryNewsletter) SaveSubscriber(ctx context.Context, s *models.Subscriber) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	if i, ok := r.find(func(x *models.Subscriber) bool { return x.Email == s.Email }); ok {
+		s.ID, s.CreatedAt, s.UpdatedAt = r.items[i].ID, r.items[i].CreatedAt, now
+		r.items[i] = *s
+		return nil
+	}
+	s.ID, s.CreatedAt, s.UpdatedAt = r.newID(), now, now
+	r.items = append(r.items, *s)
+	return nil
+}
+
+func (r *memoryNewsletter) ListSuppressions(ctx context.Context) ([]models.Suppression, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := append([]models.Suppression{}, r.suppressions...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].Email < out[j].Email
+	})
+	return out, nil
+}
+
+func (r *memoryNewsletter) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.ContainsFunc(r.suppressions, func(s models.Suppression) bool { return s.Email == email }), nil
+}
+
+func (r *memoryNewsletter) Suppress(ctx context.Context, s *models.Suppression) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	s.CreatedAt = now
+	r.suppressions = slices.DeleteFunc(r.suppressions, func(x models.Suppression) bool { return x.Email == s.Email })
+	r.suppressions = append(r.suppressions, *s)
+	if i, ok := r.find(func(x *models.Subscriber) bool { return x.Email == s.Email }); ok && r.items[i].Status != models.SubscriberUnsubscribed {
+		r.items[i].Status = models.SubscriberUnsubscribed
+		r.items[i].UnsubscribedAt = &now
+		r.items[i].UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *memoryNewsletter) Unsuppress(ctx context.Context, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.suppressions)
+	r.suppressions = slices.DeleteFunc(r.suppressions, func(x models.Suppression) bool { return x.Email == email })
+	if len(r.suppressions) == n {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ---- admins ----
+
+type memoryAdmins struct{ memoryTable[models.Admin] }
+
+func (r *memoryAdmins) GetByUsername(ctx context.Context, username string) (models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.find(func(a *models.Admin) bool { return a.Username == username }); ok {
+		return r.items[i], nil
+	}
+	return models.Admin{}, ErrNotFound
+}
+
+func (r *memoryAdmins) UpdateCredentials(ctx context.Context, username, newUsername, newPassword string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(a *models.Admin) bool { return a.Username == username })
+	if !ok {
+		return ErrNotFound
+	}
+	if _, taken := r.find(func(a *models.Admin) bool { return a.Username == newUsername && a.Username != username }); taken {
+		return &ConflictError{Field: "username"}
+	}
+	r.items[i].Username = newUsername
+	r.items[i].Password = newPassword
+	return nil
+}
+
+// ---- webhooks ----
+
+type memoryWebhooks struct {
+	memoryTable[models.Webhook]
+	deliveries     []models.WebhookDelivery
+	lastDeliveryID int
+}
+
+func (r *memoryWebhooks) List(ctx context.Context) ([]models.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hooks := r.snapshot(nil, func(a, b *models.Webhook) bool { return a.ID < b.ID })
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, nil
+}
+
+func (r *memoryWebhooks) Create(ctx context.Context, w *models.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w.ID = r.newID()
+	w.CreatedAt = time.Now().UTC()
+	w.UpdatedAt = w.CreatedAt
+	r.items = append(r.items, *w)
+	return nil
+}
+
+func (r *memoryWebhooks) Update(ctx context.Context, w *models.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.find(func(x *models.Webhook) bool { return x.ID == w.ID })
+	if !ok {
+		return ErrNotFound
+	}
+	stored := *w
+	if stored.Secret == "" {
+		stored.Secret = r.items[i].Secret
+	}
+	stored.CreatedAt = r.items[i].CreatedAt
+	stored.UpdatedAt = time.Now().UTC()
+	r.items[i] = stored
+	return nil
+}
+
+func (r *memoryWebhooks) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(func(w *models.Webhook) bool { return w.ID == id })
+	kept := r.deliveries[:0]
+	for _, d := range r.deliveries {
+		if d.WebhookID != id {
+			kept = append(kept, d)
+		}
+	}
+	r.deliveries = kept
+	return nil
+}
+
+func (r *memoryWebhooks) ListDeliveries(ctx context.Context, f DeliveryFilter) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.WebhookDelivery{}
+	for i := len(r.deliveries) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		d := r.deliveries[i]
+		if (f.WebhookID == 0 || d.WebhookID == f.WebhookID) && (f.Status == "" || d.Status == f.Status) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryWebhooks) RetryDelivery(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == id {
+			now := time.Now().UTC()
+			r.deliveries[i].Status = "pending"
+			r.deliveries[i].Attempts = 0
+			r.deliveries[i].NextAttemptAt = now
+			r.deliveries[i].UpdatedAt = now
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryWebhooks) Enqueue(ctx context.Context, event, payload string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	queued := 0
+	for _, h := range r.items {
+		if !h.Active || !h.Listens(event) {
+			continue
+		}
+		r.lastDeliveryID++
+		r.deliveries = append(r.deliveries, models.WebhookDelivery{
+			ID:            r.lastDeliveryID,
+			WebhookID:     h.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        "pending",
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		queued++
+	}
+	return queued, nil
+}
+
+// ---- related content ----
+
+type memoryRelated struct {
+	mu      sync.Mutex
+	indexes map[string]map[int]models.RelatedIndex
+}
+
+func (r *memoryRelated) Replace(ctx context.Context, kind string, index map[int]models.RelatedIndex) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.indexes == nil {
+		r.indexes = map[string]map[int]models.RelatedIndex{}
+	}
+	r.indexes[kind] = index
+	return nil
+}
+
+func (r *memoryRelated) Get(ctx context.Context, kind string, id int) (models.RelatedIndex, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.indexes[kind][id], nil
+}
+
+// ---- analytics ----
+
+type memoryAnalytics struct {
+	mu     sync.Mutex
+	counts []models.ViewCount
+}
+
+func (r *memoryAnalytics) AddViews(ctx context.Context, counts []models.ViewCount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range counts {
+		i := slices.IndexFunc(r.counts, func(v models.ViewCount) bool {
+			return v.Kind == c.Kind && v.ItemID == c.ItemID && v.Day == c.Day && v.Referrer == c.Referrer
+		})
+		if i < 0 {
+			r.counts = append(r.counts, c)
+			continue
+		}
+		r.counts[i].Views += c.Views
+		r.counts[i].Visitors += c.Visitors
+	}
+	return nil
+}
+
+// matching returns the counts f selects. Callers hold mu.
+func (r *memoryAnalytics) matching(f AnalyticsFilter) []models.ViewCount {
+	var out []models.ViewCount
+	for _, c := range r.counts {
+		if c.Day < f.From || c.Day > f.To || (f.Kind != "" && c.Kind != f.Kind) || (f.ItemID != 0 && c.ItemID != f.ItemID) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (r *memoryAnalytics) Top(ctx context.Context, f AnalyticsFilter, limit int) ([]models.ContentViews, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := []models.ContentViews{}
+	for _, c := range r.matching(f) {
+		i := slices.IndexFunc(items, func(v models.ContentViews) bool { return v.Kind == c.Kind && v.ID == c.ItemID })
+		if i < 0 {
+			items = append(items, models.ContentViews{Kind: c.Kind, ID: c.ItemID})
+			i = len(items) - 1
+		}
+		items[i].Views += c.Views
+		items[i].Visitors += c.Visitors
+	}
+	sort.Slice(items, func(a, b int) bool {
+		x, y := items[a], items[b]
+		if x.Views != y.Views {
+			return x.Views > y.Views
+		}
+		if x.Visitors != y.Visitors {
+			return x.Visitors > y.Visitors
+		}
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		return x.ID < y.ID
+	})
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *memoryAnalytics) Daily(ctx context.Context, f AnalyticsFilter) ([]models.DailyViews, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	days := []models.DailyViews{}
+	for _, c := range r.matching(f) {
+		i := slices.IndexFunc(days, func(d models.DailyViews) bool { return d.Day == c.Day })
+		if i < 0 {
+			days = append(days, models.DailyViews{Day: c.Day})
+			i = len(days) - 1
+		}
+		days[i].Views += c.Views
+		days[i].Visitors += c.Visitors
+	}
+	sort.Slice(days, func(a, b int) bool { return days[a].Day < days[b].Day })
+	return days, nil
+}
+
+func (r *memoryAnalytics) Referrers(ctx context.Context, f AnalyticsFilter, limit int) ([]models.ReferrerViews, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refs := []models.ReferrerViews{}
+	for _, c := range r.matching(f) {
+		i := slices.IndexFunc(refs, func(v models.ReferrerViews) bool { return v.Referrer == c.Referrer })
+		if i < 0 {
+			refs = append(refs, models.ReferrerViews{Referrer: c.Referrer})
+			i = len(refs) - 1
+		}
+		refs[i].Views += c.Views
+		refs[i].Visitors += c.Visitors
+	}
+	sort.Slice(refs, func(a, b int) bool {
+		if refs[a].Views != refs[b].Views {
+			return refs[a].Views > refs[b].Views
+		}
+		return refs[a].Referrer < refs[b].Referrer
+	})
+	return refs[:min(limit, len(refs))], nil
+}
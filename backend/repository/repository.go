@@ -0,0 +1,249 @@
+// Package repository separates data access from the HTTP handlers.
+//
+// Each entity has a repository interface with two implementations: a SQLite
+// one used in production (NewSQLite) and an in-memory fake for handler unit
+// tests (NewMemory). Both return the canonical types from package models.
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the requested row does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is matched by ConflictError via errors.Is.
+var ErrConflict = errors.New("conflict")
+
+// ConflictError is returned when a write would violate a unique column such
+// as a blog path or an admin username.
+type ConflictError struct {
+	Field string
+}
+
+func (e *ConflictError) Error() string { return "duplicate " + e.Field }
+
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+// ContentFilter narrows BlogRepository.List and SolutionRepository.List. A
+// nil Featured returns every row.
+type ContentFilter struct {
+	Featured *bool
+}
+
+// BlogRepository stores blog posts. Every post read carries the number of
+// its approved comments.
+type BlogRepository interface {
+	// List returns posts pinned first, then by ascending sort order, then
+	// newest first.
+	List(ctx context.Context, f ContentFilter) ([]models.Blog, error)
+	// Get looks a post up by path or by numeric ID.
+	Get(ctx context.Context, key string) (models.Blog, error)
+	GetByPath(ctx context.Context, path string) (models.Blog, error)
+	// Create inserts b and its category links and sets its ID.
+	Create(ctx context.Context, b *models.Blog) error
+	// Update overwrites the post with ID b.ID, including its categories.
+	Update(ctx context.Context, b *models.Blog) error
+	// Delete removes the post, its translations and its comments; deleting
+	// a missing post is not an error.
+	Delete(ctx context.Context, id int) error
+	// Reorder sets the sort order of every listed post in one transaction.
+	// If any ID does not exist nothing changes and ErrNotFound is returned.
+	Reorder(ctx context.Context, positions []models.SortPosition) error
+
+	// Translations returns the post's translations ordered by locale, or
+	// ErrNotFound when the post does not exist.
+	Translations(ctx context.Context, id int) ([]models.BlogTranslation, error)
+	// TranslationsByLocale returns every translation into locale keyed by
+	// post ID.
+	TranslationsByLocale(ctx context.Context, locale string) (map[int]models.BlogTranslation, error)
+	// SaveTranslation inserts or replaces the translation for t.BlogID and
+	// t.Locale; ErrNotFound when the post does not exist.
+	SaveTranslation(ctx context.Context, t *models.BlogTranslation) error
+	// DeleteTranslation removes one translation; ErrNotFound when absent.
+	DeleteTranslation(ctx context.Context, id int, locale string) error
+}
+
+// SolutionRepository stores solutions. Methods mirror BlogRepository.
+type SolutionRepository interface {
+	List(ctx context.Context, f ContentFilter) ([]models.Solution, error)
+	Get(ctx context.Context, key string) (models.Solution, error)
+	GetByPath(ctx context.Context, path string) (models.Solution, error)
+	Create(ctx context.Context, s *models.Solution) error
+	Update(ctx context.Context, s *models.Solution) error
+	Delete(ctx context.Context, id int) error
+	Reorder(ctx context.Context, positions []models.SortPosition) error
+
+	Translations(ctx context.Context, id int) ([]models.SolutionTranslation, error)
+	TranslationsByLocale(ctx context.Context, locale string) (map[int]models.SolutionTranslation, error)
+	SaveTranslation(ctx context.Context, t *models.SolutionTranslation) error
+	DeleteTranslation(ctx context.Context, id int, locale string) error
+}
+
+// CarouselRepository stores home page carousel slides.
+type CarouselRepository interface {
+	// List returns slides ordered by sort order; an empty position returns
+	// slides for every position.
+	List(ctx context.Context, position string) ([]models.Carousel, error)
+	Create(ctx context.Context, c *models.Carousel) error
+	Update(ctx context.Context, c *models.Carousel) error
+	Delete(ctx context.Context, id int) error
+}
+
+// CategoryRepository reads blog categories with their post counts.
+type CategoryRepository interface {
+	List(ctx context.Context) ([]models.Category, error)
+	Get(ctx context.Context, id int) (models.Category, error)
+}
+
+// SocialLinkRepository stores footer social links.
+type SocialLinkRepository interface {
+	List(ctx context.Context) ([]models.SocialLink, error)
+	Create(ctx context.Context, l *models.SocialLink) error
+	Update(ctx context.Context, l *models.SocialLink) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ContactFilter narrows ContactRepository.List. A nil Spam returns every
+// submission.
+type ContactFilter struct {
+	Spam *bool
+}
+
+// ContactRepository stores contact form submissions.
+type ContactRepository interface {
+	// List returns submissions, newest first.
+	List(ctx context.Context, f ContactFilter) ([]models.Contact, error)
+	Create(ctx context.Context, c *models.Contact) error
+	Delete(ctx context.Context, id int) error
+}
+
+// CommentFilter narrows CommentRepository.List. Zero values match every
+// comment.
+type CommentFilter struct {
+	BlogID int
+	Status string
+}
+
+// CommentRepository stores blog comments. Deleting a blog deletes its
+// comments.
+type CommentRepository interface {
+	// List returns comments, newest first.
+	List(ctx context.Context, f CommentFilter) ([]models.Comment, error)
+	Get(ctx context.Context, id int) (models.Comment, error)
+	Create(ctx context.Context, c *models.Comment) error
+	// SetStatus moves a comment to another moderation state; ErrNotFound
+	// when absent.
+	SetStatus(ctx context.Context, id int, status string) error
+	// MarkNotified records the comment's first approval and reports whether
+	// this call recorded it; later calls return false.
+	MarkNotified(ctx context.Context, id int) (bool, error)
+	// Delete removes the comment and every reply below it; deleting a
+	// missing comment is not an error.
+	Delete(ctx context.Context, id int) error
+}
+
+// NewsletterRepository stores newsletter subscribers and the suppression
+// list. Email addresses are passed in lower case.
+type NewsletterRepository interface {
+	// ListSubscribers returns subscribers, newest first; an empty status
+	// returns every subscriber.
+	ListSubscribers(ctx context.Context, status string) ([]models.Subscriber, error)
+	GetSubscriber(ctx context.Context, email string) (models.Subscriber, error)
+	// SaveSubscriber inserts s, or overwrites the subscriber with the same
+	// email, and fills in ID and timestamps.
+	SaveSubscriber(ctx context.Context, s *models.Subscriber) error
+
+	// ListSuppressions returns the suppression list, newest first.
+	ListSuppressions(ctx context.Context) ([]models.Suppression, error)
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	// Suppress adds or replaces an entry and marks a subscriber with that
+	// email unsubscribed, in one transaction.
+	Suppress(ctx context.Context, s *models.Suppression) error
+	// Unsuppress removes an entry; ErrNotFound when absent. The subscriber
+	// stays unsubscribed until they subscribe and confirm again.
+	Unsuppress(ctx context.Context, email string) error
+}
+
+// RelatedRepository stores the precomputed related-content index of each
+// kind ("blogs" or "solutions").
+type RelatedRepository interface {
+	// Replace swaps the whole index for kind in one transaction.
+	Replace(ctx context.Context, kind string, index map[int]models.RelatedIndex) error
+	// Get returns one item's entry; an item without one gets an empty index.
+	Get(ctx context.Context, kind string, id int) (models.RelatedIndex, error)
+}
+
+// AnalyticsFilter narrows the AnalyticsRepository reports. From and To are
+// inclusive UTC days (YYYY-MM-DD) and required; an empty Kind or zero
+// ItemID matches every item.
+type AnalyticsFilter struct {
+	Kind   string
+	ItemID int
+	From   string
+	To     string
+}
+
+// AnalyticsRepository stores daily page view counts.
+type AnalyticsRepository interface {
+	// AddViews adds counts to the stored totals in one transaction.
+	AddViews(ctx context.Context, counts []models.ViewCount) error
+	// Top returns the most viewed items, most views first; Title and Path
+	// are left for the caller.
+	Top(ctx context.Context, f AnalyticsFilter, limit int) ([]models.ContentViews, error)
+	// Daily returns totals per day, oldest first, for days with views.
+	Daily(ctx context.Context, f AnalyticsFilter) ([]models.DailyViews, error)
+	// Referrers returns totals per referrer host, most views first.
+	Referrers(ctx context.Context, f AnalyticsFilter, limit int) ([]models.ReferrerViews, error)
+}
+
+// AdminRepository stores administrator accounts.
+type AdminRepository interface {
+	GetByUsername(ctx context.Context, username string) (models.Admin, error)
+	// UpdateCredentials renames the account and replaces its password.
+	UpdateCredentials(ctx context.Context, username, newUsername, newPassword string) error
+}
+
+// DeliveryFilter narrows WebhookRepository.ListDeliveries. Zero values match
+// everything; Limit must be positive.
+type DeliveryFilter struct {
+	WebhookID int
+	Status    string
+	Limit     int
+}
+
+// WebhookRepository stores webhook subscriptions and their delivery log.
+type WebhookRepository interface {
+	// List returns subscriptions without their secrets.
+	List(ctx context.Context) ([]models.Webhook, error)
+	Create(ctx context.Context, w *models.Webhook) error
+	// Update overwrites the subscription; an empty Secret keeps the old one.
+	Update(ctx context.Context, w *models.Webhook) error
+	// Delete removes the subscription and its deliveries.
+	Delete(ctx context.Context, id int) error
+	// ListDeliveries returns deliveries, newest first.
+	ListDeliveries(ctx context.Context, f DeliveryFilter) ([]models.WebhookDelivery, error)
+	// RetryDelivery resets a delivery so the worker sends it again.
+	RetryDelivery(ctx context.Context, id int) error
+	// Enqueue adds a pending delivery of payload for every active
+	// subscription listening to event and returns how many were queued.
+	Enqueue(ctx context.Context, event, payload string) (int, error)
+}
+
+// Set bundles one repository per entity.
+type Set struct {
+	Blogs       BlogRepository
+	Solutions   SolutionRepository
+	Carousels   CarouselRepository
+	Categories  CategoryRepository
+	SocialLinks SocialLinkRepository
+	Contacts    ContactRepository
+	Comments    CommentRepository
+	Newsletter  NewsletterRepository
+	Admins      AdminRepository
+	Webhooks    WebhookRepository
+	Related     RelatedRepository
+	Analytics   AnalyticsRepository
+}
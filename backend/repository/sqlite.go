@@ -0,0 +1,1112 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewSQLite returns repositories backed by SQLite.
+//
+// db is called for every query rather than captured once, because restoring
+// a backup closes and reopens the connection (config.DB).
+func NewSQLite(db func() *sql.DB) Set {
+	return Set{
+		Blogs:       sqliteBlogs{db},
+		Solutions:   sqliteSolutions{db},
+		Carousels:   sqliteCarousels{db},
+		Categories:  sqliteCategories{db},
+		SocialLinks: sqliteSocialLinks{db},
+		Contacts:    sqliteContacts{db},
+		Comments:    sqliteComments{db},
+		Newsletter:  sqliteNewsletter{db},
+		Admins:      sqliteAdmins{db},
+		Webhooks:    sqliteWebhooks{db},
+		Related:     sqliteRelated{db},
+		Analytics:   sqliteAnalytics{db},
+	}
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// notFound maps sql.ErrNoRows to ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// uniqueViolation matches the driver message for a UNIQUE constraint, e.g.
+// "UNIQUE constraint failed: blogs.path".
+var uniqueViolation = regexp.MustCompile(`UNIQUE constraint failed: \w+\.(\w+)`)
+
+// constraint turns a UNIQUE violation into a *ConflictError naming the column.
+func constraint(err error) error {
+	if err == nil {
+		return nil
+	}
+	if m := uniqueViolation.FindStringSubmatch(err.Error()); m != nil {
+		return &ConflictError{Field: m[1]}
+	}
+	return err
+}
+
+// requireRow returns ErrNotFound when an UPDATE matched no rows.
+func requireRow(result sql.Result, err error) error {
+	if err != nil {
+		return constraint(err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// exists returns ErrNotFound when table has no row with id. table is always
+// a constant from this file.
+func exists(ctx context.Context, db *sql.DB, table string, id int) error {
+	var one int
+	return notFound(db.QueryRowContext(ctx, "SELECT 1 FROM "+table+" WHERE id = ?", id).Scan(&one))
+}
+
+// contentOrder is the list order shared by blogs and solutions.
+const contentOrder = " ORDER BY pinned DESC, sort_order ASC, created_at DESC, id DESC"
+
+// contentWhere renders f as a WHERE clause for the blogs or solutions table.
+func contentWhere(f ContentFilter) (string, []any) {
+	if f.Featured == nil {
+		return "", nil
+	}
+	return " WHERE featured = ?", []any{*f.Featured}
+}
+
+// reorder updates sort_order for each position inside one transaction.
+// table is always a constant from this file.
+func reorder(ctx context.Context, db *sql.DB, table string, positions []models.SortPosition) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE "+table+" SET sort_order = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, p := range positions {
+		if err := requireRow(stmt.ExecContext(ctx, p.SortOrder, p.ID)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// returning is appended to INSERT statements so Create fills in the
+// generated ID and timestamps.
+const returning = " RETURNING id, created_at, updated_at"
+
+// ---- blogs ----
+
+type sqliteBlogs struct{ db func() *sql.DB }
+
+const blogColumns = "id, title, summary, content, path, COALESCE(meta_title, ''), COALESCE(meta_description, ''), COALESCE(meta_keywords, ''), featured, pinned, sort_order, " +
+	"COALESCE((SELECT GROUP_CONCAT(category_id) FROM blog_category_relations WHERE blog_id = blogs.id), ''), " +
+	"(SELECT COUNT(*) FROM blog_comments WHERE blog_id = blogs.id AND status = 'approved'), created_at, updated_at"
+
+func scanBlog(s scanner) (models.Blog, error) {
+	var b models.Blog
+	var categories string
+	err := s.Scan(&b.ID, &b.Title, &b.Summary, &b.Content, &b.Path, &b.MetaTitle, &b.MetaDescription, &b.MetaKeywords, &b.Featured, &b.Pinned, &b.SortOrder, &categories, &b.CommentCount, &b.CreatedAt, &b.UpdatedAt)
+	b.CategoryIDs = splitIDs(categories)
+	return b, err
+}
+
+// splitIDs parses a GROUP_CONCAT list of IDs into ascending order.
+func splitIDs(s string) []int {
+	ids := []int{}
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.Atoi(part); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// setBlogCategories replaces the post's category links.
+func setBlogCategories(ctx context.Context, tx *sql.Tx, id int, categoryIDs []int) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM blog_category_relations WHERE blog_id = ?", id); err != nil {
+		return err
+	}
+	for _, categoryID := range categoryIDs {
+		if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO blog_category_relations (blog_id, category_id) VALUES (?, ?)", id, categoryID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r sqliteBlogs) List(ctx context.Context, f ContentFilter) ([]models.Blog, error) {
+	where, args := contentWhere(f)
+	rows, err := r.db().QueryContext(ctx, "SELECT "+blogColumns+" FROM blogs"+where+contentOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blogs := []models.Blog{}
+	for rows.Next() {
+		b, err := scanBlog(rows)
+		if err != nil {
+			return nil, err
+		}
+		blogs = append(blogs, b)
+	}
+	return blogs, rows.Err()
+}
+
+func (r sqliteBlogs) Get(ctx context.Context, key string) (models.Blog, error) {
+	b, err := scanBlog(r.db().QueryRowContext(ctx, "SELECT "+blogColumns+" FROM blogs WHERE path = ? OR id = ?", key, key))
+	return b, notFound(err)
+}
+
+func (r sqliteBlogs) GetByPath(ctx context.Context, path string) (models.Blog, error) {
+	b, err := scanBlog(r.db().QueryRowContext(ctx, "SELECT "+blogColumns+" FROM blogs WHERE path = ?", path))
+	return b, notFound(err)
+}
+
+func (r sqliteBlogs) Create(ctx context.Context, b *models.Blog) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	b.CommentCount = 0
+	err = tx.QueryRowContext(ctx, "INSERT INTO blogs (title, summary, content, path, meta_title, meta_description, meta_keywords, featured, pinned, sort_order) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"+returning,
+		b.Title, b.Summary, b.Content, b.Path, b.MetaTitle, b.MetaDescription, b.MetaKeywords, b.Featured, b.Pinned, b.SortOrder).
+		Scan(&b.ID, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return constraint(err)
+	}
+	if err := setBlogCategories(ctx, tx, b.ID, b.CategoryIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r sqliteBlogs) Update(ctx context.Context, b *models.Blog) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = requireRow(tx.ExecContext(ctx, "UPDATE blogs SET title=?, summary=?, content=?, path=?, meta_title=?, meta_description=?, meta_keywords=?, featured=?, pinned=?, sort_order=?, updated_at=CURRENT_TIMESTAMP WHERE id=?",
+		b.Title, b.Summary, b.Content, b.Path, b.MetaTitle, b.MetaDescription, b.MetaKeywords, b.Featured, b.Pinned, b.SortOrder, b.ID))
+	if err != nil {
+		return err
+	}
+	if err := setBlogCategories(ctx, tx, b.ID, b.CategoryIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r sqliteBlogs) Reorder(ctx context.Context, positions []models.SortPosition) error {
+	return reorder(ctx, r.db(), "blogs", positions)
+}
+
+func (r sqliteBlogs) Delete(ctx context.Context, id int) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM blog_translations WHERE blog_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM blog_category_relations WHERE blog_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM blog_comments WHERE blog_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM blogs WHERE id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ---- blog translations ----
+
+const blogTranslationColumns = "blog_id, locale, title, summary, content, meta_title, meta_description, meta_keywords, created_at, updated_at"
+
+func scanBlogTranslation(s scanner) (models.BlogTranslation, error) {
+	var t models.BlogTranslation
+	err := s.Scan(&t.BlogID, &t.Locale, &t.Title, &t.Summary, &t.Content, &t.MetaTitle, &t.MetaDescription, &t.MetaKeywords, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+func (r sqliteBlogs) Translations(ctx context.Context, id int) ([]models.BlogTranslation, error) {
+	if err := exists(ctx, r.db(), "blogs", id); err != nil {
+		return nil, err
+	}
+	rows, err := r.db().QueryContext(ctx, "SELECT "+blogTranslationColumns+" FROM blog_translations WHERE blog_id = ? ORDER BY locale", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []models.BlogTranslation{}
+	for rows.Next() {
+		t, err := scanBlogTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+func (r sqliteBlogs) TranslationsByLocale(ctx context.Context, locale string) (map[int]models.BlogTranslation, error) {
+	rows, err := r.db().QueryContext(ctx, "SELECT "+blogTranslationColumns+" FROM blog_translations WHERE locale = ?", locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := map[int]models.BlogTranslation{}
+	for rows.Next() {
+		t, err := scanBlogTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations[t.BlogID] = t
+	}
+	return translations, rows.Err()
+}
+
+func (r sqliteBlogs) SaveTranslation(ctx context.Context, t *models.BlogTranslation) error {
+	if err := exists(ctx, r.db(), "blogs", t.BlogID); err != nil {
+		return err
+	}
+	return r.db().QueryRowContext(ctx, `INSERT INTO blog_translations (blog_id, locale, title, summary, content, meta_title, meta_description, meta_keywords)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (blog_id, locale) DO UPDATE SET title = excluded.title, summary = excluded.summary, content = excluded.content,
+			meta_title = excluded.meta_title, meta_description = excluded.meta_description, meta_keywords = excluded.meta_keywords, updated_at = CURRENT_TIMESTAMP
+		RETURNING created_at, updated_at`,
+		t.BlogID, t.Locale, t.Title, t.Summary, t.Content, t.MetaTitle, t.MetaDescription, t.MetaKeywords).
+		Scan(&t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r sqliteBlogs) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	return requireRow(r.db().ExecContext(ctx, "DELETE FROM blog_translations WHERE blog_id = ? AND locale = ?", id, locale))
+}
+
+// ---- solutions ----
+
+type sqliteSolutions struct{ db func() *sql.DB }
+
+const solutionColumns = "id, title, description, image_url, path, COALESCE(meta_title, ''), COALESCE(meta_description, ''), COALESCE(meta_keywords, ''), featured, pinned, sort_order, created_at, updated_at"
+
+func scanSolution(s scanner) (models.Solution, error) {
+	var v models.Solution
+	err := s.Scan(&v.ID, &v.Title, &v.Description, &v.ImageURL, &v.Path, &v.MetaTitle, &v.MetaDescription, &v.MetaKeywords, &v.Featured, &v.Pinned, &v.SortOrder, &v.CreatedAt, &v.UpdatedAt)
+	return v, err
+}
+
+func (r sqliteSolutions) List(ctx context.Context, f ContentFilter) ([]models.Solution, error) {
+	where, args := contentWhere(f)
+	rows, err := r.db().QueryContext(ctx, "SELECT "+solutionColumns+" FROM solutions"+where+contentOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	solutions := []models.Solution{}
+	for rows.Next() {
+		v, err := scanSolution(rows)
+		if err != nil {
+			return nil, err
+		}
+		solutions = append(solutions, v)
+	}
+	return solutions, rows.Err()
+}
+
+func (r sqliteSolutions) Get(ctx context.Context, key string) (models.Solution, error) {
+	v, err := scanSolution(r.db().QueryRowContext(ctx, "SELECT "+solutionColumns+" FROM solutions WHERE path = ? OR id = ?", key, key))
+	return v, notFound(err)
+}
+
+func (r sqliteSolutions) GetByPath(ctx context.Context, path string) (models.Solution, error) {
+	v, err := scanSolution(r.db().QueryRowContext(ctx, "SELECT "+solutionColumns+" FROM solutions WHERE path = ?", path))
+	return v, notFound(err)
+}
+
+func (r sqliteSolutions) Create(ctx context.Context, s *models.Solution) error {
+	return constraint(r.db().QueryRowContext(ctx, "INSERT INTO solutions (title, description, image_url, path, meta_title, meta_description, meta_keywords, featured, pinned, sort_order) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"+returning,
+		s.Title, s.Description, s.ImageURL, s.Path, s.MetaTitle, s.MetaDescription, s.MetaKeywords, s.Featured, s.Pinned, s.SortOrder).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt))
+}
+
+func (r sqliteSolutions) Update(ctx context.Context, s *models.Solution) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE solutions SET title=?, description=?, image_url=?, path=?, meta_title=?, meta_description=?, meta_keywords=?, featured=?, pinned=?, sort_order=?, updated_at=CURRENT_TIMESTAMP WHERE id=?",
+		s.Title, s.Description, s.ImageURL, s.Path, s.MetaTitle, s.MetaDescription, s.MetaKeywords, s.Featured, s.Pinned, s.SortOrder, s.ID))
+}
+
+func (r sqliteSolutions) Reorder(ctx context.Context, positions []models.SortPosition) error {
+	return reorder(ctx, r.db(), "solutions", positions)
+}
+
+func (r sqliteSolutions) Delete(ctx context.Context, id int) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM solution_translations WHERE solution_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM solutions WHERE id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ---- solution translations ----
+
+const solutionTranslationColumns = "solution_id, locale, title, description, meta_title, meta_description, meta_keywords, created_at, updated_at"
+
+func scanSolutionTranslation(s scanner) (models.SolutionTranslation, error) {
+	var t models.SolutionTranslation
+	err := s.Scan(&t.SolutionID, &t.Locale, &t.Title, &t.Description, &t.MetaTitle, &t.MetaDescription, &t.MetaKeywords, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+func (r sqliteSolutions) Translations(ctx context.Context, id int) ([]models.SolutionTranslation, error) {
+	if err := exists(ctx, r.db(), "solutions", id); err != nil {
+		return nil, err
+	}
+	rows, err := r.db().QueryContext(ctx, "SELECT "+solutionTranslationColumns+" FROM solution_translations WHERE solution_id = ? ORDER BY locale", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []models.SolutionTranslation{}
+	for rows.Next() {
+		t, err := scanSolutionTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+func (r sqliteSolutions) TranslationsByLocale(ctx context.Context, locale string) (map[int]models.SolutionTranslation, error) {
+	rows, err := r.db().QueryContext(ctx, "SELECT "+solutionTranslationColumns+" FROM solution_translations WHERE locale = ?", locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := map[int]models.SolutionTranslation{}
+	for rows.Next() {
+		t, err := scanSolutionTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations[t.SolutionID] = t
+	}
+	return translations, rows.Err()
+}
+
+func (r sqliteSolutions) SaveTranslation(ctx context.Context, t *models.SolutionTranslation) error {
+	if err := exists(ctx, r.db(), "solutions", t.SolutionID); err != nil {
+		return err
+	}
+	return r.db().QueryRowContext(ctx, `INSERT INTO solution_translations (solution_id, locale, title, description, meta_title, meta_description, meta_keywords)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (solution_id, locale) DO UPDATE SET title = excluded.title, description = excluded.description,
+			meta_title = excluded.meta_title, meta_description = excluded.meta_description, meta_keywords = excluded.meta_keywords, updated_at = CURRENT_TIMESTAMP
+		RETURNING created_at, updated_at`,
+		t.SolutionID, t.Locale, t.Title, t.Description, t.MetaTitle, t.MetaDescription, t.MetaKeywords).
+		Scan(&t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r sqliteSolutions) DeleteTranslation(ctx context.Context, id int, locale string) error {
+	return requireRow(r.db().ExecContext(ctx, "DELETE FROM solution_translations WHERE solution_id = ? AND locale = ?", id, locale))
+}
+
+// ---- carousels ----
+
+type sqliteCarousels struct{ db func() *sql.DB }
+
+func (r sqliteCarousels) List(ctx context.Context, position string) ([]models.Carousel, error) {
+	query := "SELECT id, title, image_url, alt_text, COALESCE(description, ''), sort_order, position, rotation, image_width, image_height, active, starts_at, ends_at, locale, cta_label, cta_type, cta_url, cta_ref_id, created_at, updated_at FROM carousels"
+	var args []any
+	if position != "" {
+		query += " WHERE position = ?"
+		args = append(args, position)
+	}
+	query += " ORDER BY sort_order ASC, created_at DESC"
+
+	rows, err := r.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carousels := []models.Carousel{}
+	for rows.Next() {
+		var c models.Carousel
+		var active bool
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.Title, &c.ImageURL, &c.AltText, &c.Description, &c.SortOrder, &c.Position, &c.Rotation, &c.ImageWidth, &c.ImageHeight, &active, &startsAt, &endsAt, &c.Locale, &c.CTALabel, &c.CTAType, &c.CTAURL, &c.CTARefID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		c.Active, c.StartsAt, c.EndsAt = &active, nullTime(startsAt), nullTime(endsAt)
+		carousels = append(carousels, c)
+	}
+	return carousels, rows.Err()
+}
+
+func (r sqliteCarousels) Create(ctx context.Context, c *models.Carousel) error {
+	return r.db().QueryRowContext(ctx, "INSERT INTO carousels (title, image_url, alt_text, description, sort_order, position, rotation, image_width, image_height, active, starts_at, ends_at, locale, cta_label, cta_type, cta_url, cta_ref_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"+returning,
+		c.Title, c.ImageURL, c.AltText, c.Description, c.SortOrder, c.Position, c.Rotation, c.ImageWidth, c.ImageHeight, c.Active, c.StartsAt, c.EndsAt, c.Locale, c.CTALabel, c.CTAType, c.CTAURL, c.CTARefID).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r sqliteCarousels) Update(ctx context.Context, c *models.Carousel) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE carousels SET title = ?, image_url = ?, alt_text = ?, description = ?, sort_order = ?, position = ?, rotation = ?, image_width = ?, image_height = ?, active = ?, starts_at = ?, ends_at = ?, locale = ?, cta_label = ?, cta_type = ?, cta_url = ?, cta_ref_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		c.Title, c.ImageURL, c.AltText, c.Description, c.SortOrder, c.Position, c.Rotation, c.ImageWidth, c.ImageHeight, c.Active, c.StartsAt, c.EndsAt, c.Locale, c.CTALabel, c.CTAType, c.CTAURL, c.CTARefID, c.ID))
+}
+
+func (r sqliteCarousels) Delete(ctx context.Context, id int) error {
+	_, err := r.db().ExecContext(ctx, "DELETE FROM carousels WHERE id = ?", id)
+	return err
+}
+
+// ---- categories ----
+
+type sqliteCategories struct{ db func() *sql.DB }
+
+const categoryQuery = `
+	SELECT
+		c.id, c.name, c.slug, COALESCE(c.icon, ''), COALESCE(c.color, ''), c.created_at, c.updated_at,
+		(SELECT COUNT(*) FROM blog_category_relations bcr WHERE bcr.category_id = c.id) as count
+	FROM blog_categories c
+`
+
+func scanCategory(s scanner) (models.Category, error) {
+	var c models.Category
+	err := s.Scan(&c.ID, &c.Name, &c.Slug, &c.Icon, &c.Color, &c.CreatedAt, &c.UpdatedAt, &c.Count)
+	return c, err
+}
+
+func (r sqliteCategories) List(ctx context.Context) ([]models.Category, error) {
+	rows, err := r.db().QueryContext(ctx, categoryQuery+" ORDER BY c.id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func (r sqliteCategories) Get(ctx context.Context, id int) (models.Category, error) {
+	c, err := scanCategory(r.db().QueryRowContext(ctx, categoryQuery+" WHERE c.id = ?", id))
+	return c, notFound(err)
+}
+
+// ---- social links ----
+
+type sqliteSocialLinks struct{ db func() *sql.DB }
+
+func (r sqliteSocialLinks) List(ctx context.Context) ([]models.SocialLink, error) {
+	rows, err := r.db().QueryContext(ctx, "SELECT id, platform, url, sort_order, created_at, updated_at FROM social_links ORDER BY sort_order ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.SocialLink{}
+	for rows.Next() {
+		var l models.SocialLink
+		if err := rows.Scan(&l.ID, &l.Platform, &l.URL, &l.SortOrder, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+func (r sqliteSocialLinks) Create(ctx context.Context, l *models.SocialLink) error {
+	return r.db().QueryRowContext(ctx, "INSERT INTO social_links (platform, url, sort_order) VALUES (?, ?, ?)"+returning, l.Platform, l.URL, l.SortOrder).
+		Scan(&l.ID, &l.CreatedAt, &l.UpdatedAt)
+}
+
+func (r sqliteSocialLinks) Update(ctx context.Context, l *models.SocialLink) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE social_links SET platform = ?, url = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", l.Platform, l.URL, l.SortOrder, l.ID))
+}
+
+func (r sqliteSocialLinks) Delete(ctx context.Context, id int) error {
+	_, err := r.db().ExecContext(ctx, "DELETE FROM social_links WHERE id = ?", id)
+	return err
+}
+
+// ---- contacts ----
+
+type sqliteContacts struct{ db func() *sql.DB }
+
+func (r sqliteContacts) List(ctx context.Context, f ContactFilter) ([]models.Contact, error) {
+	query := "SELECT id, name, email, subject, message, is_spam, spam_reason, created_at, updated_at FROM contacts"
+	var args []any
+	if f.Spam != nil {
+		query += " WHERE is_spam = ?"
+		args = append(args, *f.Spam)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []models.Contact{}
+	for rows.Next() {
+		var c models.Contact
+		if err := rows.Scan(&c.ID, &c.Name, &c.Email, &c.Subject, &c.Message, &c.IsSpam, &c.SpamReason, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+func (r sqliteContacts) Create(ctx context.Context, c *models.Contact) error {
+	return r.db().QueryRowContext(ctx, "INSERT INTO contacts (name, email, subject, message, is_spam, spam_reason) VALUES (?, ?, ?, ?, ?, ?)"+returning,
+		c.Name, c.Email, c.Subject, c.Message, c.IsSpam, c.SpamReason).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r sqliteContacts) Delete(ctx context.Context, id int) error {
+	_, err := r.db().ExecContext(ctx, "DELETE FROM contacts WHERE id = ?", id)
+	return err
+}
+
+// ---- comments ----
+
+type sqliteComments struct{ db func() *sql.DB }
+
+const commentColumns = "id, blog_id, parent_id, author, email, content, status, spam_reason, notify_replies, created_at, updated_at"
+
+func scanComment(s scanner) (models.Comment, error) {
+	var c models.Comment
+	err := s.Scan(&c.ID, &c.BlogID, &c.ParentID, &c.Author, &c.Email, &c.Content, &c.Status, &c.SpamReason, &c.NotifyReplies, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+func (r sqliteComments) List(ctx context.Context, f CommentFilter) ([]models.Comment, error) {
+	var conds []string
+	var args []any
+	if f.BlogID != 0 {
+		conds = append(conds, "blog_id = ?")
+		args = append(args, f.BlogID)
+	}
+	if f.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, f.Status)
+	}
+	query := "SELECT " + commentColumns + " FROM blog_comments"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+
+	rows, err := r.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+func (r sqliteComments) Get(ctx context.Context, id int) (models.Comment, error) {
+	c, err := scanComment(r.db().QueryRowContext(ctx, "SELECT "+commentColumns+" FROM blog_comments WHERE id = ?", id))
+	return c, notFound(err)
+}
+
+func (r sqliteComments) Create(ctx context.Context, c *models.Comment) error {
+	return r.db().QueryRowContext(ctx, "INSERT INTO blog_comments (blog_id, parent_id, author, email, content, status, spam_reason, notify_replies) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"+returning,
+		c.BlogID, c.ParentID, c.Author, c.Email, c.Content, c.Status, c.SpamReason, c.NotifyReplies).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r sqliteComments) SetStatus(ctx context.Context, id int, status string) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE blog_comments SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", status, id))
+}
+
+func (r sqliteComments) MarkNotified(ctx context.Context, id int) (bool, error) {
+	res, err := r.db().ExecContext(ctx, "UPDATE blog_comments SET notified_at = CURRENT_TIMESTAMP WHERE id = ? AND notified_at IS NULL", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r sqliteComments) Delete(ctx context.Context, id int) error {
+	_, err := r.db().ExecContext(ctx, `WITH RECURSIVE thread(id) AS (
+		SELECT id FROM blog_comments WHERE id = ?
+		UNION ALL
+		SELECT c.id FROM blog_comments c JOIN thread t ON c.parent_id = t.id
+	) DELETE FROM blog_comments WHERE id IN (SELECT id FROM thread)`, id)
+	return err
+}
+
+// ---- newsletter ----
+
+type sqliteNewsletter struct{ db func() *sql.DB }
+
+const subscriberColumns = "id, email, status, locale, confirmed_at, unsubscribed_at, created_at, updated_at"
+
+func scanSubscriber(s scanner) (models.Subscriber, error) {
+	var sub models.Subscriber
+	var confirmed, unsubscribed sql.NullTime
+	err := s.Scan(&sub.ID, &sub.Email, &sub.Status, &sub.Locale, &confirmed, &unsubscribed, &sub.CreatedAt, &sub.UpdatedAt)
+	sub.ConfirmedAt, sub.UnsubscribedAt = nullTime(confirmed), nullTime(unsubscribed)
+	return sub, err
+}
+
+// nullTime converts a nullable column to the *time.Time used by models.
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func (r sqliteNewsletter) ListSubscribers(ctx context.Context, status string) ([]models.Subscriber, error) {
+	query := "SELECT " + subscriberColumns + " FROM newsletter_subscribers"
+	var args []any
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+
+	rows, err := r.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscribers := []models.Subscriber{}
+	for rows.Next() {
+		sub, err := scanSubscriber(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscribers = append(subscribers, sub)
+	}
+	return subscribers, rows.Err()
+}
+
+func (r sqliteNewsletter) GetSubscriber(ctx context.Context, email string) (models.Subscriber, error) {
+	sub, err := scanSubscriber(r.db().QueryRowContext(ctx, "SELECT "+subscriberColumns+" FROM newsletter_subscribers WHERE email = ?", email))
+	return sub, notFound(err)
+}
+
+func (r sqliteNewsletter) SaveSubscriber(ctx context.Context, s *models.Subscriber) error {
+	return r.db().QueryRowContext(ctx, `INSERT INTO newsletter_subscribers (email, status, locale, confirmed_at, unsubscribed_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(email) DO UPDATE SET status = excluded.status, locale = excluded.locale, confirmed_at = excluded.confirmed_at,
+			unsubscribed_at = excluded.unsubscribed_at, updated_at = CURRENT_TIMESTAMP`+returning,
+		s.Email, s.Status, s.Locale, s.ConfirmedAt, s.UnsubscribedAt).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r sqliteNewsletter) ListSuppressions(ctx context.Context) ([]models.Suppression, error) {
+	rows, err := r.db().QueryContext(ctx, "SELECT email, reason, created_at FROM newsletter_suppressions ORDER BY created_at DESC, email ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppressions := []models.Suppression{}
+	for rows.Next() {
+		var s models.Suppression
+		if err := rows.Scan(&s.Email, &s.Reason, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		suppressions = append(suppressions, s)
+	}
+	return suppressions, rows.Err()
+}
+
+func (r sqliteNewsletter) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var one int
+	err := r.db().QueryRowContext(ctx, "SELECT 1 FROM newsletter_suppressions WHERE email = ?", email).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r sqliteNewsletter) Suppress(ctx context.Context, s *models.Suppression) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, "INSERT OR REPLACE INTO newsletter_suppressions (email, reason) VALUES (?, ?) RETURNING created_at", s.Email, s.Reason).
+		Scan(&s.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE newsletter_subscribers SET status = ?, unsubscribed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE email = ? AND status != ?",
+		models.SubscriberUnsubscribed, s.Email, models.SubscriberUnsubscribed); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r sqliteNewsletter) Unsuppress(ctx context.Context, email string) error {
+	return requireRow(r.db().ExecContext(ctx, "DELETE FROM newsletter_suppressions WHERE email = ?", email))
+}
+
+// ---- admins ----
+
+type sqliteAdmins struct{ db func() *sql.DB }
+
+func (r sqliteAdmins) GetByUsername(ctx context.Context, username string) (models.Admin, error) {
+	var a models.Admin
+	err := r.db().QueryRowContext(ctx, "SELECT id, username, password, created_at FROM admins WHERE username = ?", username).
+		Scan(&a.ID, &a.Username, &a.Password, &a.CreatedAt)
+	return a, notFound(err)
+}
+
+func (r sqliteAdmins) UpdateCredentials(ctx context.Context, username, newUsername, newPassword string) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE admins SET username = ?, password = ? WHERE username = ?", newUsername, newPassword, username))
+}
+
+// ---- webhooks ----
+
+type sqliteWebhooks struct{ db func() *sql.DB }
+
+func (r sqliteWebhooks) List(ctx context.Context) ([]models.Webhook, error) {
+	rows, err := r.db().QueryContext(ctx, "SELECT id, url, events, active, created_at, updated_at FROM webhooks ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []models.Webhook{}
+	for rows.Next() {
+		var h models.Webhook
+		var events string
+		if err := rows.Scan(&h.ID, &h.URL, &events, &h.Active, &h.CreatedAt, &h.UpdatedAt); err != nil {
+			return nil, err
+		}
+		h.Events = strings.Split(events, ",")
+		hooks = append(hooks, h)
+	}
+	return hooks, rows.Err()
+}
+
+func (r sqliteWebhooks) Create(ctx context.Context, w *models.Webhook) error {
+	return r.db().QueryRowContext(ctx, "INSERT INTO webhooks (url, secret, events, active) VALUES (?, ?, ?, ?)"+returning,
+		w.URL, w.Secret, strings.Join(w.Events, ","), w.Active).
+		Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+func (r sqliteWebhooks) Update(ctx context.Context, w *models.Webhook) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE webhooks SET url = ?, secret = CASE WHEN ? = '' THEN secret ELSE ? END, events = ?, active = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		w.URL, w.Secret, w.Secret, strings.Join(w.Events, ","), w.Active, w.ID))
+}
+
+func (r sqliteWebhooks) Delete(ctx context.Context, id int) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r sqliteWebhooks) ListDeliveries(ctx context.Context, f DeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := "SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, response_status, last_error, created_at, updated_at FROM webhook_deliveries WHERE 1 = 1"
+	var args []any
+	if f.WebhookID != 0 {
+		query += " AND webhook_id = ?"
+		args = append(args, f.WebhookID)
+	}
+	if f.Status != "" {
+		query += " AND status = ?"
+		args = append(args, f.Status)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, f.Limit)
+
+	rows, err := r.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r sqliteWebhooks) RetryDelivery(ctx context.Context, id int) error {
+	return requireRow(r.db().ExecContext(ctx, "UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ?", id))
+}
+
+func (r sqliteWebhooks) Enqueue(ctx context.Context, event, payload string) (int, error) {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, events FROM webhooks WHERE active = 1")
+	if err != nil {
+		return 0, err
+	}
+	var ids []int
+	for rows.Next() {
+		var h models.Webhook
+		var events string
+		if err := rows.Scan(&h.ID, &events); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		h.Events = strings.Split(events, ",")
+		if h.Listens(event) {
+			ids = append(ids, h.ID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO webhook_deliveries (webhook_id, event, payload) VALUES (?, ?, ?)", id, event, payload); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), tx.Commit()
+}
+
+// ---- related content ----
+
+type sqliteRelated struct{ db func() *sql.DB }
+
+func (r sqliteRelated) Replace(ctx context.Context, kind string, index map[int]models.RelatedIndex) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM content_relations WHERE kind = ?", kind); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO content_relations (kind, item_id, relation, rank, target_id, score) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for id, entry := range index {
+		for rank, m := range entry.Related {
+			if _, err := stmt.ExecContext(ctx, kind, id, "related", rank, m.ID, m.Score); err != nil {
+				return err
+			}
+		}
+		for relation, target := range map[string]int{"previous": entry.Previous, "next": entry.Next} {
+			if target == 0 {
+				continue
+			}
+			if _, err := stmt.ExecContext(ctx, kind, id, relation, 0, target, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (r sqliteRelated) Get(ctx context.Context, kind string, id int) (models.RelatedIndex, error) {
+	rows, err := r.db().QueryContext(ctx, "SELECT relation, target_id, score FROM content_relations WHERE kind = ? AND item_id = ? ORDER BY relation, rank", kind, id)
+	if err != nil {
+		return models.RelatedIndex{}, err
+	}
+	defer rows.Close()
+
+	var entry models.RelatedIndex
+	for rows.Next() {
+		var relation string
+		var m models.RelatedMatch
+		if err := rows.Scan(&relation, &m.ID, &m.Score); err != nil {
+			return models.RelatedIndex{}, err
+		}
+		switch relation {
+		case "related":
+			entry.Related = append(entry.Related, m)
+		case "previous":
+			entry.Previous = m.ID
+		case "next":
+			entry.Next = m.ID
+		}
+	}
+	return entry, rows.Err()
+}
+
+// ---- analytics ----
+
+type sqliteAnalytics struct{ db func() *sql.DB }
+
+func (r sqliteAnalytics) AddViews(ctx context.Context, counts []models.ViewCount) error {
+	tx, err := r.db().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO page_views (kind, item_id, day, referrer, views, visitors) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (kind, item_id, day, referrer) DO UPDATE SET views = views + excluded.views, visitors = visitors + excluded.visitors`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range counts {
+		if _, err := stmt.ExecContext(ctx, c.Kind, c.ItemID, c.Day, c.Referrer, c.Views, c.Visitors); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// analyticsWhere builds the WHERE clause shared by the reports.
+func analyticsWhere(f AnalyticsFilter) (string, []any) {
+	where, args := "WHERE day BETWEEN ? AND ?", []any{f.From, f.To}
+	if f.Kind != "" {
+		where += " AND kind = ?"
+		args = append(args, f.Kind)
+	}
+	if f.ItemID != 0 {
+		where += " AND item_id = ?"
+		args = append(args, f.ItemID)
+	}
+	return where, args
+}
+
+func (r sqliteAnalytics) Top(ctx context.Context, f AnalyticsFilter, limit int) ([]models.ContentViews, error) {
+	where, args := analyticsWhere(f)
+	rows, err := r.db().QueryContext(ctx, "SELECT kind, item_id, SUM(views), SUM(visitors) FROM page_views "+where+
+		" GROUP BY kind, item_id ORDER BY SUM(views) DESC, SUM(visitors) DESC, kind, item_id LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ContentViews{}
+	for rows.Next() {
+		var v models.ContentViews
+		if err := rows.Scan(&v.Kind, &v.ID, &v.Views, &v.Visitors); err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, rows.Err()
+}
+
+func (r sqliteAnalytics) Daily(ctx context.Context, f AnalyticsFilter) ([]models.DailyViews, error) {
+	where, args := analyticsWhere(f)
+	rows, err := r.db().QueryContext(ctx, "SELECT day, SUM(views), SUM(visitors) FROM page_views "+where+" GROUP BY day ORDER BY day", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []models.DailyViews{}
+	for rows.Next() {
+		var d models.DailyViews
+		if err := rows.Scan(&d.Day, &d.Views, &d.Visitors); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+func (r sqliteAnalytics) Referrers(ctx context.Context, f AnalyticsFilter, limit int) ([]models.ReferrerViews, error) {
+	where, args := analyticsWhere(f)
+	rows, err := r.db().QueryContext(ctx, "SELECT referrer, SUM(views), SUM(visitors) FROM page_views "+where+
+		" GROUP BY referrer ORDER BY SUM(views) DESC, referrer LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []models.ReferrerViews{}
+	for rows.Next() {
+		var v models.ReferrerViews
+		if err := rows.Scan(&v.Referrer, &v.Views, &v.Visitors); err != nil {
+			return nil, err
+		}
+		refs = append(refs, v)
+	}
+	return refs, rows.Err()
+}
@@ -0,0 +1,309 @@
+package routes
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"backend/antispam"
+	"backend/cache"
+	"backend/config"
+	"backend/controllers"
+	"backend/mailer"
+	"backend/middleware"
+	"backend/repository"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testServer is the full API router backed by a temporary SQLite file and an
+// in-process Redis. The package-level state it touches (config.DB,
+// config.Redis, config.Current) means tests using it must not run in
+// parallel.
+type testServer struct {
+	t      *testing.T
+	router *gin.Engine
+	redis  *miniredis.Miniredis
+	token  string
+}
+
+type serverOption func(*config.Settings)
+
+// newTestServer boots a fresh database and Redis for t and tears both down
+// when the test ends.
+func newTestServer(t *testing.T, opts ...serverOption) *testServer {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+
+	cfg := config.Defaults()
+	cfg.Server.Mode = gin.TestMode
+	cfg.Database.Path = filepath.Join(t.TempDir(), "data.db")
+	cfg.Redis.Addr = mr.Addr()
+	cfg.Contact.MinFillSeconds = 0
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	config.Current = cfg
+
+	antispam.Configure(antispam.Config{
+		MinFillTime:  config.Seconds(cfg.Contact.MinFillSeconds),
+		TokenMaxAge:  config.Seconds(cfg.Contact.TokenMaxAgeSeconds),
+		MaxLinks:     cfg.Contact.MaxLinks,
+		SpamKeywords: cfg.Contact.SpamKeywords,
+	})
+
+	config.InitDB()
+	config.InitRedis()
+	if config.Redis == nil && config.RedisConfigured() {
+		t.Fatal("redis client not initialised")
+	}
+	cache.Init()
+
+	t.Cleanup(func() {
+		config.CloseRedis()
+		config.Redis = nil
+		cache.Use(nil)
+		config.CloseDB()
+		config.DB = nil
+		config.Current = config.Defaults()
+		mailer.Use(nil)
+		controllers.UseRepositories(repository.NewSQLite(func() *sql.DB { return config.DB }))
+	})
+
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Recovery())
+	r.Use(middleware.DBReadLock())
+	SetupRoutes(r)
+
+	return &testServer{t: t, router: r, redis: mr}
+}
+
+// useMemoryRepositories swaps the handlers onto in-memory repositories; the
+// database stays available for health checks.
+func (s *testServer) useMemoryRepositories() {
+	controllers.UseRepositories(repository.NewMemory())
+}
+
+// do sends a request with an optional JSON body (a string or any value
+// marshalled with encoding/json).
+func (s *testServer) do(method, path string, body any, header ...string) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = bytes.NewBufferString(b)
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("marshal body: %v", err)
+		}
+		r = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// admin sends an authenticated request, logging in as the default admin on
+// first use.
+func (s *testServer) admin(method, path string, body any) *httptest.ResponseRecorder {
+	s.t.Helper()
+	if s.token == "" {
+		s.token = s.login("admin", "admin123")
+	}
+	return s.do(method, path, body, "Authorization", "Bearer "+s.token)
+}
+
+func (s *testServer) login(username, password string) string {
+	s.t.Helper()
+	w := s.do(http.MethodPost, "/api/admin/login", map[string]string{"username": username, "password": password})
+	expectStatus(s.t, w, http.StatusOK)
+	return decode[struct {
+		Token string `json:"token"`
+	}](s.t, w).Token
+}
+
+// upload posts content as the multipart "file" field of the restore endpoint.
+func (s *testServer) upload(filename string, content []byte) *httptest.ResponseRecorder {
+	s.t.Helper()
+	if s.token == "" {
+		s.token = s.login("admin", "admin123")
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if filename != "" {
+		fw, err := mw.CreateFormFile("file", filename)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		fw.Write(content)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/db/restore", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// contactToken fetches a form token for the public contact endpoint.
+func (s *testServer) contactToken() string {
+	s.t.Helper()
+	w := s.do(http.MethodGet, "/api/contact/token", nil)
+	expectStatus(s.t, w, http.StatusOK)
+	return decode[struct {
+		Token string `json:"token"`
+	}](s.t, w).Token
+}
+
+// recordingMailer keeps sent messages instead of delivering them; a non-nil
+// err makes every send fail, and onSend, when set, runs during each send
+// and fails it by returning an error.
+type recordingMailer struct {
+	mu     sync.Mutex
+	sent   []mailer.Message
+	err    error
+	onSend func() error
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.onSend != nil {
+		if err := m.onSend(); err != nil {
+			return err
+		}
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *recordingMailer) messages() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.sent)
+}
+
+// recordMail routes outgoing mail of this server into a recordingMailer.
+func (s *testServer) recordMail() *recordingMailer {
+	m := &recordingMailer{}
+	mailer.Use(m)
+	return m
+}
+
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if w.Code != want {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, want, w.Body.String())
+	}
+}
+
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode %T: %v; body: %s", v, err, w.Body.String())
+	}
+	return v
+}
+
+// ---- archive builders for restore tests ----
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg, ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("backup is not gzip: %v", err)
+	}
+	defer gz.Close()
+	out, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
@@ -1,47 +1,100 @@
 package routes
 
 import (
+	"backend/antispam"
+	"backend/cache"
+	"backend/config"
 	"backend/controllers"
 	"backend/middleware"
-	"os"
-	"strconv"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(r *gin.Engine) {
+	// 只采信 TRUSTED_PROXIES 中代理转发的 X-Forwarded-For；默认不信任任何代理，
+	// 否则客户端伪造这个头就能绕过按 IP 限流和访客去重
+	if err := r.SetTrustedProxies(config.Current.Server.TrustedProxies); err != nil {
+		slog.Error("invalid trusted proxies; trusting none", "error", err)
+		_ = r.SetTrustedProxies(nil)
+	}
+
+	// 存活/就绪/版本（不在 /api 下：不走缓存，恢复数据库期间也能响应）
+	r.GET("/healthz", controllers.Healthz)
+	r.GET("/readyz", controllers.Readyz)
+	r.GET("/version", controllers.Version)
+
+	// 未知路径也返回统一的错误格式
+	r.NoRoute(middleware.NoRoute)
+
 	api := r.Group("/api")
 	{
-		// 公开 GET 接口缓存（Redis 可选）
-		ttlSeconds := 300
-		if v := os.Getenv("CACHE_TTL_SECONDS"); v != "" {
-			if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
-				ttlSeconds = parsed
-			}
-		}
-		api.Use(middleware.CachePublicGetResponses(time.Duration(ttlSeconds)*time.Second, 1024*1024))
+		// 公开 GET 接口缓存（Redis 可选），并处理 ETag / Last-Modified 条件请求；
+		// Cache-Control 默认取 CACHE_CONTROL，个别路由用 middleware.CacheControl 覆盖。
+		// 过期条目在 CACHE_STALE_SECONDS 内先返回旧内容，由路由器在后台刷新；清理缓存后预热 CACHE_WARM_PATHS
+		cacheSettings := config.Current.Cache
+		api.Use(middleware.CachePublicGetResponses(middleware.ResponseCacheOptions{
+			TTL:            config.Seconds(cacheSettings.TTLSeconds),
+			MaxBodyBytes:   1024 * 1024,
+			DefaultControl: cacheSettings.Control,
+			Stale:          config.Seconds(cacheSettings.StaleSeconds),
+			WarmPaths:      cacheSettings.WarmPaths,
+			Handler:        r,
+		}))
+		// 轮播图、分类和社交链接变化少，允许浏览器短时间直接复用
+		shortLived := middleware.CacheControl("public, max-age=60, must-revalidate")
 
 		// 公开接口
-		api.GET("/blogs", controllers.GetBlogs)
+		api.GET("/blogs", middleware.CacheTags(cache.TagBlogs), controllers.GetBlogs)
 		api.GET("/blogs/:id", controllers.GetBlog)
 		api.GET("/blogs/by-path/:path", controllers.GetBlogByPath)
-		api.GET("/carousels", controllers.GetCarousels)
+
+		// 博客评论（只返回已审核的评论；发表评论按 IP 限流）
+		comments := config.Current.Comments
+		api.GET("/blogs/:id/comments", controllers.GetBlogComments)
+		api.POST("/blogs/:id/comments",
+			middleware.RateLimitByIP(comments.RateLimit, config.Seconds(comments.RateWindowSeconds), nil),
+			controllers.CreateBlogComment,
+		)
+		api.GET("/carousels", shortLived, middleware.CacheTags(cache.TagCarousels), controllers.GetCarousels)
 
 		// 解决方案接口
-		api.GET("/solutions", controllers.GetSolutions)
+		api.GET("/solutions", middleware.CacheTags(cache.TagSolutions), controllers.GetSolutions)
 		api.GET("/solutions/:id", controllers.GetSolution)
 		api.GET("/solutions/by-path/:path", controllers.GetSolutionByPath)
 
-		// 联系表单接口
-		api.POST("/contact", controllers.CreateContact)
+		// 联系表单接口（按 IP 限流，防止刷表单）
+		contact := config.Current.Contact
+		api.GET("/contact/token", controllers.GetContactToken)
+		api.POST("/contact",
+			middleware.RateLimitByIP(contact.RateLimit, config.Seconds(contact.RateWindowSeconds), func(c *gin.Context) {
+				antispam.RecordRejected(antispam.ReasonRateLimited)
+			}),
+			controllers.CreateContact,
+		)
+
+		// 邮件订阅（双重确认；订阅按 IP 限流，确认和退订凭邮件中的签名令牌）
+		subscribe := config.Current.Newsletter
+		api.POST("/newsletter/subscribe",
+			middleware.RateLimitByIP(subscribe.RateLimit, config.Seconds(subscribe.RateWindowSeconds), nil),
+			controllers.Subscribe,
+		)
+		api.POST("/newsletter/confirm", controllers.ConfirmSubscription)
+		api.POST("/newsletter/unsubscribe", controllers.Unsubscribe)
+
+		// 浏览统计上报（按 IP 限流；只记录每天的计数，不保存访客信息）
+		api.POST("/track",
+			middleware.RateLimitByIP(config.Current.Analytics.RateLimit, time.Minute, nil),
+			controllers.TrackView,
+		)
 
 		// 博客分类接口
-		api.GET("/categories", controllers.GetCategories)
-		api.GET("/categories/:id", controllers.GetCategory)
+		api.GET("/categories", shortLived, middleware.CacheTags(cache.TagCategories), controllers.GetCategories)
+		api.GET("/categories/:id", shortLived, middleware.CacheTags(cache.TagCategories), controllers.GetCategory)
 
 		// 社交媒体链接接口
-		api.GET("/social-links", controllers.GetSocialLinks)
+		api.GET("/social-links", shortLived, middleware.CacheTags(cache.TagSocialLinks), controllers.GetSocialLinks)
 
 		// 管理员登录
 		api.POST("/admin/login", controllers.AdminLogin)
@@ -54,6 +107,9 @@ func SetupRoutes(r *gin.Engine) {
 			admin.GET("/db/backup", controllers.BackupDatabase)
 			admin.POST("/db/restore", controllers.RestoreDatabase)
 
+			// 缓存与 Redis 连接状态
+			admin.GET("/cache/status", controllers.GetCacheStatus)
+
 			// 管理员账号管理
 			admin.PUT("/credentials", controllers.UpdateAdminCredentials)
 
@@ -61,17 +117,42 @@ func SetupRoutes(r *gin.Engine) {
 			admin.POST("/blogs", controllers.CreateBlog)
 			admin.PUT("/blogs/:id", controllers.UpdateBlog)
 			admin.DELETE("/blogs/:id", controllers.DeleteBlog)
+			admin.PUT("/blogs/order", controllers.ReorderBlogs)
 
 			// 解决方案管理
 			admin.POST("/solutions", controllers.CreateSolution)
 			admin.PUT("/solutions/:id", controllers.UpdateSolution)
 			admin.DELETE("/solutions/:id", controllers.DeleteSolution)
+			admin.PUT("/solutions/order", controllers.ReorderSolutions)
+
+			// 多语言译文（原文为默认语言，这里只管理其他语言）
+			admin.GET("/blogs/:id/translations", controllers.GetBlogTranslations)
+			admin.PUT("/blogs/:id/translations/:locale", controllers.SaveBlogTranslation)
+			admin.DELETE("/blogs/:id/translations/:locale", controllers.DeleteBlogTranslation)
+			admin.GET("/solutions/:id/translations", controllers.GetSolutionTranslations)
+			admin.PUT("/solutions/:id/translations/:locale", controllers.SaveSolutionTranslation)
+			admin.DELETE("/solutions/:id/translations/:locale", controllers.DeleteSolutionTranslation)
+			admin.GET("/translations/missing", controllers.GetMissingTranslations)
 
 			// 联系请求管理
 			admin.GET("/contacts", controllers.GetContacts)
+			admin.GET("/contacts/stats", controllers.GetContactStats)
 			admin.DELETE("/contacts/:id", controllers.DeleteContact)
 
-			// 首页轮播图管理
+			// 评论审核
+			admin.GET("/comments", controllers.GetComments)
+			admin.PUT("/comments/:id/status", controllers.UpdateCommentStatus)
+			admin.DELETE("/comments/:id", controllers.DeleteComment)
+
+			// 邮件订阅者与禁止发送列表
+			admin.GET("/newsletter/subscribers", controllers.GetSubscribers)
+			admin.GET("/newsletter/subscribers/export", controllers.ExportSubscribers)
+			admin.GET("/newsletter/suppressions", controllers.GetSuppressions)
+			admin.POST("/newsletter/suppressions", controllers.CreateSuppression)
+			admin.DELETE("/newsletter/suppressions/:email", controllers.DeleteSuppression)
+
+			// 首页轮播图管理（列表包括未启用和不在展示时间内的轮播图）
+			admin.GET("/carousels", controllers.GetAllCarousels)
 			admin.POST("/carousels", controllers.CreateCarousel)
 			admin.PUT("/carousels/:id", controllers.UpdateCarousel)
 			admin.DELETE("/carousels/:id", controllers.DeleteCarousel)
@@ -80,6 +161,19 @@ func SetupRoutes(r *gin.Engine) {
 			admin.POST("/social-links", controllers.CreateSocialLink)
 			admin.PUT("/social-links/:id", controllers.UpdateSocialLink)
 			admin.DELETE("/social-links/:id", controllers.DeleteSocialLink)
+
+			// Webhook 订阅与投递日志
+			admin.GET("/webhooks", controllers.GetWebhooks)
+			admin.POST("/webhooks", controllers.CreateWebhook)
+			admin.PUT("/webhooks/:id", controllers.UpdateWebhook)
+			admin.DELETE("/webhooks/:id", controllers.DeleteWebhook)
+			admin.GET("/webhooks/deliveries", controllers.GetWebhookDeliveries)
+			admin.POST("/webhooks/deliveries/:id/retry", controllers.RetryWebhookDelivery)
+
+			// 浏览统计报表
+			admin.GET("/analytics/top", controllers.GetTopContent)
+			admin.GET("/analytics/daily", controllers.GetDailyViews)
+			admin.GET("/analytics/referrers", controllers.GetReferrers)
 		}
 	}
 }
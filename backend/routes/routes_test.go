@@ -0,0 +1,2371 @@
+package routes
+
+import (
+	"backend/cache"
+	"backend/config"
+	"backend/i18n"
+	"backend/middleware"
+	"backend/models"
+	"backend/newsletter"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andybalholm/brotli"
+)
+
+// coveredRoutes lists every route registered by SetupRoutes together with
+// the test that exercises it. TestRoutesCovered fails when a route is added
+// without a test.
+var coveredRoutes = map[string]string{
+	"GET /healthz":                                         "TestHealthEndpoints",
+	"GET /readyz":                                          "TestHealthEndpoints",
+	"GET /version":                                         "TestHealthEndpoints",
+	"GET /api/blogs":                                       "TestBlogCRUD",
+	"GET /api/blogs/:id":                                   "TestBlogCRUD",
+	"GET /api/blogs/by-path/:path":                         "TestBlogCRUD",
+	"GET /api/carousels":                                   "TestCarouselCRUD",
+	"GET /api/solutions":                                   "TestSolutionCRUD",
+	"GET /api/solutions/:id":                               "TestSolutionCRUD",
+	"GET /api/solutions/by-path/:path":                     "TestSolutionCRUD",
+	"GET /api/contact/token":                               "TestContactSubmission",
+	"POST /api/contact":                                    "TestContactSubmission",
+	"GET /api/categories":                                  "TestCategories",
+	"GET /api/categories/:id":                              "TestCategories",
+	"GET /api/social-links":                                "TestSocialLinkCRUD",
+	"POST /api/admin/login":                                "TestAdminLogin",
+	"GET /api/admin/db/backup":                             "TestBackupDownload",
+	"GET /api/admin/cache/status":                          "TestRedisRecovery",
+	"POST /api/admin/db/restore":                           "TestRestore",
+	"PUT /api/admin/credentials":                           "TestUpdateCredentials",
+	"POST /api/admin/blogs":                                "TestBlogCRUD",
+	"PUT /api/admin/blogs/:id":                             "TestBlogCRUD",
+	"DELETE /api/admin/blogs/:id":                          "TestBlogCRUD",
+	"PUT /api/admin/blogs/order":                           "TestContentOrdering",
+	"POST /api/admin/solutions":                            "TestSolutionCRUD",
+	"PUT /api/admin/solutions/:id":                         "TestSolutionCRUD",
+	"DELETE /api/admin/solutions/:id":                      "TestSolutionCRUD",
+	"PUT /api/admin/solutions/order":                       "TestContentOrdering",
+	"GET /api/admin/blogs/:id/translations":                "TestTranslations",
+	"PUT /api/admin/blogs/:id/translations/:locale":        "TestTranslations",
+	"DELETE /api/admin/blogs/:id/translations/:locale":     "TestTranslations",
+	"GET /api/admin/solutions/:id/translations":            "TestTranslations",
+	"PUT /api/admin/solutions/:id/translations/:locale":    "TestTranslations",
+	"DELETE /api/admin/solutions/:id/translations/:locale": "TestTranslations",
+	"GET /api/admin/translations/missing":                  "TestTranslations",
+	"GET /api/admin/contacts":                              "TestContactSubmission",
+	"GET /api/admin/contacts/stats":                        "TestContactSubmission",
+	"DELETE /api/admin/contacts/:id":                       "TestContactSubmission",
+	"POST /api/admin/carousels":                            "TestCarouselCRUD",
+	"PUT /api/admin/carousels/:id":                         "TestCarouselCRUD",
+	"DELETE /api/admin/carousels/:id":                      "TestCarouselCRUD",
+	"GET /api/admin/carousels":                             "TestCarouselSchedule",
+	"POST /api/admin/social-links":                         "TestSocialLinkCRUD",
+	"PUT /api/admin/social-links/:id":                      "TestSocialLinkCRUD",
+	"DELETE /api/admin/social-links/:id":                   "TestSocialLinkCRUD",
+	"GET /api/admin/webhooks":                              "TestWebhookCRUD",
+	"POST /api/admin/webhooks":                             "TestWebhookCRUD",
+	"PUT /api/admin/webhooks/:id":                          "TestWebhookCRUD",
+	"DELETE /api/admin/webhooks/:id":                       "TestWebhookCRUD",
+	"GET /api/admin/webhooks/deliveries":                   "TestWebhookCRUD",
+	"POST /api/admin/webhooks/deliveries/:id/retry":        "TestWebhookCRUD",
+	"POST /api/track":                                      "TestViewAnalytics",
+	"GET /api/admin/analytics/top":                         "TestViewAnalytics",
+	"GET /api/admin/analytics/daily":                       "TestViewAnalytics",
+	"GET /api/admin/analytics/referrers":                   "TestViewAnalytics",
+	"GET /api/blogs/:id/comments":                          "TestComments",
+	"POST /api/blogs/:id/comments":                         "TestComments",
+	"GET /api/admin/comments":                              "TestComments",
+	"PUT /api/admin/comments/:id/status":                   "TestComments",
+	"DELETE /api/admin/comments/:id":                       "TestComments",
+	"POST /api/newsletter/subscribe":                       "TestNewsletter",
+	"POST /api/newsletter/confirm":                         "TestNewsletter",
+	"POST /api/newsletter/unsubscribe":                     "TestNewsletter",
+	"GET /api/admin/newsletter/subscribers":                "TestNewsletter",
+	"GET /api/admin/newsletter/subscribers/export":         "TestNewsletter",
+	"GET /api/admin/newsletter/suppressions":               "TestNewsletter",
+	"POST /api/admin/newsletter/suppressions":              "TestNewsletter",
+	"DELETE /api/admin/newsletter/suppressions/:email":     "TestNewsletter",
+}
+
+func TestRoutesCovered(t *testing.T) {
+	s := newTestServer(t)
+
+	registered := map[string]bool{}
+	for _, r := range s.router.Routes() {
+		key := r.Method + " " + r.Path
+		registered[key] = true
+		if _, ok := coveredRoutes[key]; !ok {
+			t.Errorf("route %s has no integration test; add one and list it in coveredRoutes", key)
+		}
+	}
+	for key := range coveredRoutes {
+		if !registered[key] {
+			t.Errorf("coveredRoutes lists %s, which is no longer registered", key)
+		}
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, path := range []string{"/healthz", "/readyz", "/version"} {
+		t.Run(path, func(t *testing.T) {
+			w := s.do(http.MethodGet, path, nil)
+			expectStatus(t, w, http.StatusOK)
+		})
+	}
+
+	ready := decode[map[string]any](t, s.do(http.MethodGet, "/readyz", nil))
+	if ready["status"] != "ready" {
+		t.Errorf("readyz status = %v, want ready", ready["status"])
+	}
+}
+
+func TestReadyzRedis(t *testing.T) {
+	// With the default "auto" backend the cache falls back to memory, so
+	// losing Redis only degrades the instance.
+	t.Run("optional", func(t *testing.T) {
+		s := newTestServer(t)
+		s.redis.Close()
+		w := s.do(http.MethodGet, "/readyz", nil)
+		expectStatus(t, w, http.StatusOK)
+		if got, _ := decode[map[string]any](t, w)["checks"].(map[string]any)["redis"].(string); !strings.HasPrefix(got, "degraded") {
+			t.Errorf("readyz redis = %q, want degraded", got)
+		}
+	})
+
+	// When Redis is required, losing it makes the instance unready.
+	t.Run("required", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Cache.Backend = "redis" })
+		expectStatus(t, s.do(http.MethodGet, "/readyz", nil), http.StatusOK)
+		s.redis.Close()
+		expectStatus(t, s.do(http.MethodGet, "/readyz", nil), http.StatusServiceUnavailable)
+	})
+}
+
+func TestAdminLogin(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name string
+		body any
+		want int
+	}{
+		{"success", map[string]string{"username": "admin", "password": "admin123"}, http.StatusOK},
+		{"wrong password", map[string]string{"username": "admin", "password": "nope"}, http.StatusUnauthorized},
+		{"unknown user", map[string]string{"username": "ghost", "password": "admin123"}, http.StatusUnauthorized},
+		{"missing password", map[string]string{"username": "admin"}, http.StatusUnprocessableEntity},
+		{"malformed json", "{", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, "/api/admin/login", tt.body)
+			expectStatus(t, w, tt.want)
+			body := decode[map[string]any](t, w)
+			if tt.want == http.StatusOK && body["token"] == "" {
+				t.Error("login response has no token")
+			}
+			if tt.want != http.StatusOK && body["request_id"] == nil {
+				t.Error("error response has no request_id")
+			}
+		})
+	}
+}
+
+func TestAdminRoutesRequireAuth(t *testing.T) {
+	s := newTestServer(t)
+
+	headers := []struct {
+		name  string
+		value string
+	}{
+		{"no header", ""},
+		{"not bearer", "Token abc"},
+		{"invalid token", "Bearer not-a-jwt"},
+	}
+
+	for _, r := range s.router.Routes() {
+		if !strings.HasPrefix(r.Path, "/api/admin/") || r.Path == "/api/admin/login" {
+			continue
+		}
+		path := strings.NewReplacer(":id", "1").Replace(r.Path)
+		for _, h := range headers {
+			t.Run(r.Method+" "+r.Path+"/"+h.name, func(t *testing.T) {
+				var w = s.do(r.Method, path, nil)
+				if h.value != "" {
+					w = s.do(r.Method, path, nil, "Authorization", h.value)
+				}
+				expectStatus(t, w, http.StatusUnauthorized)
+			})
+		}
+	}
+}
+
+func TestUpdateCredentials(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.admin(http.MethodPut, "/api/admin/credentials", map[string]string{
+		"current_password": "wrong", "new_username": "root", "new_password": "s3cret",
+	})
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+
+	w = s.admin(http.MethodPut, "/api/admin/credentials", map[string]string{"new_username": "root"})
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+
+	w = s.admin(http.MethodPut, "/api/admin/credentials", map[string]string{
+		"current_password": "admin123", "new_username": "root", "new_password": "s3cret",
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	expectStatus(t, s.do(http.MethodPost, "/api/admin/login", map[string]string{"username": "admin", "password": "admin123"}), http.StatusUnauthorized)
+	s.login("root", "s3cret")
+}
+
+// repositoryBackends runs CRUD tests against both the SQLite repositories and
+// the in-memory fakes, so the fakes stay faithful to production behaviour.
+var repositoryBackends = []struct {
+	name  string
+	setup func(*testServer)
+}{
+	{"sqlite", func(*testServer) {}},
+	{"memory", (*testServer).useMemoryRepositories},
+}
+
+func TestBlogCRUD(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", "{"), http.StatusBadRequest)
+
+			w := s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Hello World", Summary: "s", Content: "body", MetaTitle: "SEO"})
+			expectStatus(t, w, http.StatusCreated)
+			created := decode[models.Blog](t, w)
+			if created.ID == 0 || created.Path != "hello-world" || created.CreatedAt.IsZero() {
+				t.Fatalf("created blog = %+v, want ID, generated path and timestamps", created)
+			}
+
+			for _, path := range []string{
+				fmt.Sprintf("/api/blogs/%d", created.ID),
+				"/api/blogs/hello-world",
+				"/api/blogs/by-path/hello-world",
+			} {
+				w := s.do(http.MethodGet, path, nil)
+				expectStatus(t, w, http.StatusOK)
+				if got := decode[models.Blog](t, w); got.Title != "Hello World" || got.MetaTitle != "SEO" {
+					t.Errorf("GET %s = %+v", path, got)
+				}
+			}
+
+			w = s.admin(http.MethodPut, fmt.Sprintf("/api/admin/blogs/%d", created.ID), models.Blog{Title: "Renamed Post", Content: "body"})
+			expectStatus(t, w, http.StatusOK)
+			expectStatus(t, s.do(http.MethodGet, "/api/blogs/by-path/renamed-post", nil), http.StatusOK)
+
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/blogs/999", models.Blog{Title: "x", Content: "c"}), http.StatusNotFound)
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/blogs/abc", models.Blog{Title: "x", Content: "c"}), http.StatusBadRequest)
+
+			list := decode[[]models.Blog](t, s.do(http.MethodGet, "/api/blogs", nil))
+			if len(list) != 1 {
+				t.Fatalf("list has %d blogs, want 1", len(list))
+			}
+
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/blogs/%d", created.ID), nil), http.StatusOK)
+			expectStatus(t, s.do(http.MethodGet, fmt.Sprintf("/api/blogs/%d", created.ID), nil), http.StatusNotFound)
+			expectStatus(t, s.do(http.MethodGet, "/api/blogs/by-path/renamed-post", nil), http.StatusNotFound)
+		})
+	}
+}
+
+func TestSolutionCRUD(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/solutions", "{"), http.StatusBadRequest)
+
+			w := s.admin(http.MethodPost, "/api/admin/solutions", models.Solution{Title: "Smart Grid", Description: "d", ImageURL: "/g.png"})
+			expectStatus(t, w, http.StatusCreated)
+			created := decode[models.Solution](t, w)
+			if created.ID == 0 || created.Path != "smart-grid" {
+				t.Fatalf("created solution = %+v", created)
+			}
+
+			for _, path := range []string{
+				fmt.Sprintf("/api/solutions/%d", created.ID),
+				"/api/solutions/smart-grid",
+				"/api/solutions/by-path/smart-grid",
+			} {
+				expectStatus(t, s.do(http.MethodGet, path, nil), http.StatusOK)
+			}
+
+			// Unlike blogs, the path is not regenerated on update.
+			w = s.admin(http.MethodPut, fmt.Sprintf("/api/admin/solutions/%d", created.ID), models.Solution{Title: "Grid 2", Description: "d", Path: "grid-2"})
+			expectStatus(t, w, http.StatusOK)
+			got := decode[models.Solution](t, s.do(http.MethodGet, "/api/solutions/by-path/grid-2", nil))
+			if got.Title != "Grid 2" {
+				t.Errorf("updated solution = %+v", got)
+			}
+
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/solutions/999", models.Solution{Title: "x"}), http.StatusNotFound)
+
+			if list := decode[[]models.Solution](t, s.do(http.MethodGet, "/api/solutions", nil)); len(list) != 1 {
+				t.Fatalf("list has %d solutions, want 1", len(list))
+			}
+
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/solutions/%d", created.ID), nil), http.StatusOK)
+			expectStatus(t, s.do(http.MethodGet, "/api/solutions/grid-2", nil), http.StatusNotFound)
+			if list := decode[[]models.Solution](t, s.do(http.MethodGet, "/api/solutions", nil)); len(list) != 0 {
+				t.Fatalf("list has %d solutions after delete, want 0", len(list))
+			}
+		})
+	}
+}
+
+func TestContentOrdering(t *testing.T) {
+	type item struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	titles := func(t *testing.T, s *testServer, path string) string {
+		t.Helper()
+		w := s.do(http.MethodGet, path, nil)
+		expectStatus(t, w, http.StatusOK)
+		var out []string
+		for _, v := range decode[[]item](t, w) {
+			out = append(out, v.Title)
+		}
+		return strings.Join(out, ",")
+	}
+
+	for _, backend := range repositoryBackends {
+		for _, kind := range []string{"blogs", "solutions"} {
+			t.Run(backend.name+"/"+kind, func(t *testing.T) {
+				s := newTestServer(t)
+				backend.setup(s)
+				list, admin := "/api/"+kind, "/api/admin/"+kind
+
+				ids := map[string]int{}
+				for _, title := range []string{"A", "B", "C"} {
+					body := map[string]any{"title": title, "content": "c", "description": "d", "path": strings.ToLower(title), "featured": title == "B"}
+					w := s.admin(http.MethodPost, admin, body)
+					expectStatus(t, w, http.StatusCreated)
+					ids[title] = decode[item](t, w).ID
+				}
+				if got := titles(t, s, list); got != "C,B,A" {
+					t.Fatalf("default order = %s, want newest first", got)
+				}
+				if got := titles(t, s, list+"?featured=true"); got != "B" {
+					t.Errorf("featured = %s, want B", got)
+				}
+				if got := titles(t, s, list+"?featured=false"); got != "C,A" {
+					t.Errorf("not featured = %s, want C,A", got)
+				}
+
+				pin := map[string]any{"title": "A", "content": "c", "description": "d", "path": "a", "pinned": true}
+				expectStatus(t, s.admin(http.MethodPut, fmt.Sprintf("%s/%d", admin, ids["A"]), pin), http.StatusOK)
+				if got := titles(t, s, list); got != "A,C,B" {
+					t.Fatalf("after pinning A = %s, want A,C,B", got)
+				}
+
+				order := map[string]any{"items": []map[string]int{{"id": ids["B"], "sort_order": 1}, {"id": ids["C"], "sort_order": 2}}}
+				expectStatus(t, s.admin(http.MethodPut, admin+"/order", order), http.StatusOK)
+				if got := titles(t, s, list); got != "A,B,C" {
+					t.Fatalf("after reorder = %s, want A,B,C", got)
+				}
+
+				// One unknown ID rolls the whole batch back.
+				order = map[string]any{"items": []map[string]int{{"id": ids["C"], "sort_order": 0}, {"id": 999, "sort_order": 3}}}
+				expectStatus(t, s.admin(http.MethodPut, admin+"/order", order), http.StatusNotFound)
+				if got := titles(t, s, list); got != "A,B,C" {
+					t.Errorf("after failed reorder = %s, want unchanged A,B,C", got)
+				}
+
+				dup := map[string]any{"items": []map[string]int{{"id": ids["B"]}, {"id": ids["B"], "sort_order": 1}}}
+				expectStatus(t, s.admin(http.MethodPut, admin+"/order", dup), http.StatusUnprocessableEntity)
+				expectStatus(t, s.admin(http.MethodPut, admin+"/order", map[string]any{"items": []any{}}), http.StatusUnprocessableEntity)
+				expectStatus(t, s.do(http.MethodPut, admin+"/order", order), http.StatusUnauthorized)
+			})
+		}
+	}
+}
+
+func TestRelatedContent(t *testing.T) {
+	detail := func(t *testing.T, s *testServer, path string) (models.Blog, string) {
+		t.Helper()
+		w := s.do(http.MethodGet, path, nil)
+		expectStatus(t, w, http.StatusOK)
+		return decode[models.Blog](t, w), w.Header().Get("X-Cache")
+	}
+	relatedIDs := func(r *models.RelatedContent) []int {
+		var ids []int
+		for _, l := range r.Items {
+			ids = append(ids, l.ID)
+		}
+		return ids
+	}
+
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			ids := map[string]int{}
+			for _, b := range []struct {
+				key, title, content string
+				categories          []int
+			}{
+				{"inverter", "Solar inverter maintenance", "Cleaning inverter fans keeps solar output high.", []int{1}},
+				{"wind", "Wind turbine blades", "Blade inspection with drones.", []int{2}},
+				{"solar", "Solar panel and inverter efficiency", "Pairing panels with the right inverter.", []int{1}},
+				{"grid", "智能电网调度", "智能电网的负荷调度与预测", nil},
+				{"storage", "智能电网储能", "储能系统接入智能电网", nil},
+			} {
+				body := map[string]any{"title": b.title, "content": b.content, "path": b.key, "category_ids": b.categories}
+				w := s.admin(http.MethodPost, "/api/admin/blogs", body)
+				expectStatus(t, w, http.StatusCreated)
+				ids[b.key] = decode[models.Blog](t, w).ID
+			}
+
+			blog, _ := detail(t, s, fmt.Sprintf("/api/blogs/%d?include=related", ids["solar"]))
+			if !slices.Equal(blog.CategoryIDs, []int{1}) {
+				t.Errorf("category_ids = %v, want [1]", blog.CategoryIDs)
+			}
+			if blog.Related == nil || len(blog.Related.Items) == 0 || blog.Related.Items[0].ID != ids["inverter"] {
+				t.Fatalf("related = %+v, want the inverter post first", blog.Related)
+			}
+			if p, n := blog.Related.Previous, blog.Related.Next; p == nil || p.ID != ids["wind"] || n == nil || n.ID != ids["grid"] {
+				t.Errorf("previous/next = %+v/%+v, want wind/grid", p, n)
+			}
+
+			// Ranking itself is covered in the related package.
+			blog, _ = detail(t, s, "/api/blogs/by-path/grid?include=related")
+			if got := relatedIDs(blog.Related); !slices.Equal(got, []int{ids["storage"]}) {
+				t.Errorf("related for grid by path = %v, want [%d]", got, ids["storage"])
+			}
+
+			if w := s.do(http.MethodGet, fmt.Sprintf("/api/blogs/%d", ids["solar"]), nil); strings.Contains(w.Body.String(), `"related"`) {
+				t.Errorf("related returned without include: %s", w.Body.String())
+			}
+
+			// Renaming a related post purges cached detail pages that link to it.
+			path := fmt.Sprintf("/api/blogs/%d?include=related", ids["solar"])
+			detail(t, s, path)
+			if _, hit := detail(t, s, path); hit != "HIT" {
+				t.Fatalf("second GET X-Cache = %q, want HIT", hit)
+			}
+			rename := map[string]any{"title": "Inverter care", "content": "Cleaning inverter fans keeps solar output high.", "path": "inverter", "category_ids": []int{1}}
+			expectStatus(t, s.admin(http.MethodPut, fmt.Sprintf("/api/admin/blogs/%d", ids["inverter"]), rename), http.StatusOK)
+			blog, hit := detail(t, s, path)
+			if hit != "MISS" || blog.Related.Items[0].Title != "Inverter care" {
+				t.Errorf("after rename X-Cache = %q, first related = %+v", hit, blog.Related.Items[0])
+			}
+
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/blogs/%d", ids["inverter"]), nil), http.StatusOK)
+			blog, _ = detail(t, s, path)
+			if slices.Contains(relatedIDs(blog.Related), ids["inverter"]) {
+				t.Errorf("related after delete = %v, still lists the deleted post", relatedIDs(blog.Related))
+			}
+
+			bad := map[string]any{"title": "Bad", "content": "c", "category_ids": []int{999}}
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", bad), http.StatusUnprocessableEntity)
+
+			for _, title := range []string{"Battery storage retrofit", "Battery storage sizing"} {
+				body := map[string]any{"title": title, "description": "Lithium battery storage", "path": strings.ToLower(strings.ReplaceAll(title, " ", "-"))}
+				expectStatus(t, s.admin(http.MethodPost, "/api/admin/solutions", body), http.StatusCreated)
+			}
+			w := s.do(http.MethodGet, "/api/solutions/by-path/battery-storage-sizing?include=related", nil)
+			expectStatus(t, w, http.StatusOK)
+			solution := decode[models.Solution](t, w)
+			if solution.Related == nil || len(solution.Related.Items) != 1 || solution.Related.Previous == nil {
+				t.Errorf("solution related = %+v, want the other solution as related and previous", solution.Related)
+			}
+		})
+	}
+}
+
+func TestViewAnalytics(t *testing.T) {
+	const browser = "Mozilla/5.0 (X11; Linux x86_64) Gecko/20100101 Firefox/130.0"
+	track := func(s *testServer, kind string, id int, referrer string, header ...string) *httptest.ResponseRecorder {
+		body := map[string]any{"kind": kind, "id": id, "referrer": referrer}
+		return s.do(http.MethodPost, "/api/track", body, append([]string{"User-Agent", browser, "Origin", "https://example.com"}, header...)...)
+	}
+
+	run := func(t *testing.T, s *testServer, buffered func() bool) {
+		w := s.admin(http.MethodPost, "/api/admin/blogs", map[string]any{"title": "Popular", "content": "c", "path": "popular"})
+		expectStatus(t, w, http.StatusCreated)
+		popular := decode[models.Blog](t, w).ID
+		w = s.admin(http.MethodPost, "/api/admin/blogs", map[string]any{"title": "Quiet", "content": "c", "path": "quiet"})
+		expectStatus(t, w, http.StatusCreated)
+		quiet := decode[models.Blog](t, w).ID
+		w = s.admin(http.MethodPost, "/api/admin/solutions", map[string]any{"title": "Storage", "description": "d", "path": "storage"})
+		expectStatus(t, w, http.StatusCreated)
+		storage := decode[models.Solution](t, w).ID
+
+		for range 3 {
+			expectStatus(t, track(s, "blog", popular, "https://www.Google.com/search?q=solar"), http.StatusNoContent)
+		}
+		// A second visitor arriving from the site itself counts as direct.
+		expectStatus(t, track(s, "blog", popular, "https://example.com/news", "User-Agent", browser+" Edg/130.0"), http.StatusNoContent)
+		expectStatus(t, track(s, "blog", quiet, "https://google.com/"), http.StatusNoContent)
+		expectStatus(t, track(s, "solution", storage, ""), http.StatusNoContent)
+
+		// Accepted but not counted.
+		expectStatus(t, track(s, "blog", quiet, "", "User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1)"), http.StatusNoContent)
+		expectStatus(t, track(s, "blog", quiet, "", "User-Agent", "curl/8.0"), http.StatusNoContent)
+		expectStatus(t, track(s, "blog", quiet, "", "DNT", "1"), http.StatusNoContent)
+
+		expectStatus(t, track(s, "blog", 999, ""), http.StatusNotFound)
+		expectStatus(t, track(s, "page", popular, ""), http.StatusUnprocessableEntity)
+
+		if !buffered() {
+			t.Error("views were not buffered before the first report")
+		}
+
+		for range 2 { // reading twice must not count anything twice
+			top := decode[[]models.ContentViews](t, s.admin(http.MethodGet, "/api/admin/analytics/top", nil))
+			want := []models.ContentViews{
+				{Kind: "blog", ID: popular, Title: "Popular", Path: "popular", Views: 4, Visitors: 2},
+				{Kind: "blog", ID: quiet, Title: "Quiet", Path: "quiet", Views: 1, Visitors: 1},
+				{Kind: "solution", ID: storage, Title: "Storage", Path: "storage", Views: 1, Visitors: 1},
+			}
+			if !slices.Equal(top, want) {
+				t.Fatalf("top = %+v, want %+v", top, want)
+			}
+		}
+
+		top := decode[[]models.ContentViews](t, s.admin(http.MethodGet, "/api/admin/analytics/top?kind=solution&limit=1", nil))
+		if len(top) != 1 || top[0].ID != storage {
+			t.Errorf("top solutions = %+v, want only storage", top)
+		}
+
+		w = s.admin(http.MethodGet, fmt.Sprintf("/api/admin/analytics/daily?kind=blog&id=%d", popular), nil)
+		expectStatus(t, w, http.StatusOK)
+		daily := decode[[]models.DailyViews](t, w)
+		today := time.Now().UTC().Format(time.DateOnly)
+		if len(daily) != 30 || daily[29] != (models.DailyViews{Day: today, Views: 4, Visitors: 2}) || daily[0].Views != 0 {
+			t.Errorf("daily = %d days ending %+v, want 30 days ending today with 4 views", len(daily), daily[len(daily)-1])
+		}
+		daily = decode[[]models.DailyViews](t, s.admin(http.MethodGet, "/api/admin/analytics/daily?from="+today+"&to="+today, nil))
+		if len(daily) != 1 || daily[0].Views != 6 {
+			t.Errorf("daily for today = %+v, want 6 views", daily)
+		}
+
+		refs := decode[[]models.ReferrerViews](t, s.admin(http.MethodGet, "/api/admin/analytics/referrers?kind=blog", nil))
+		want := []models.ReferrerViews{{Referrer: "google.com", Views: 4, Visitors: 2}, {Referrer: "", Views: 1, Visitors: 1}}
+		if !slices.Equal(refs, want) {
+			t.Errorf("referrers = %+v, want %+v", refs, want)
+		}
+
+		for _, query := range []string{"?kind=page", "?id=1", "?kind=blog&id=x", "?from=2024-13-01", "?from=2024-02-01&to=2024-01-01", "?from=2020-01-01&to=2024-01-01"} {
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/analytics/daily"+query, nil), http.StatusUnprocessableEntity)
+		}
+		expectStatus(t, s.do(http.MethodGet, "/api/admin/analytics/top", nil), http.StatusUnauthorized)
+	}
+
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+			run(t, s, func() bool { return s.redis.Exists("analytics:{pending}") })
+		})
+	}
+
+	t.Run("without redis", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Redis.Addr = "" })
+		run(t, s, func() bool { return true })
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Analytics.Enabled = false })
+		w := s.admin(http.MethodPost, "/api/admin/blogs", map[string]any{"title": "A", "content": "c"})
+		expectStatus(t, w, http.StatusCreated)
+		expectStatus(t, track(s, "blog", decode[models.Blog](t, w).ID, ""), http.StatusNoContent)
+		if top := decode[[]models.ContentViews](t, s.admin(http.MethodGet, "/api/admin/analytics/top", nil)); len(top) != 0 {
+			t.Errorf("top = %+v, want nothing counted", top)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Analytics.RateLimit = 1 })
+		expectStatus(t, track(s, "blog", 999, ""), http.StatusNotFound)
+		expectStatus(t, track(s, "blog", 999, ""), http.StatusTooManyRequests)
+	})
+}
+
+func TestTranslations(t *testing.T) {
+	type report struct {
+		Blogs []struct {
+			ID      int      `json:"id"`
+			Missing []string `json:"missing"`
+		} `json:"blogs"`
+		Solutions []struct {
+			ID      int      `json:"id"`
+			Missing []string `json:"missing"`
+		} `json:"solutions"`
+	}
+
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			blog := decode[models.Blog](t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Hello", Summary: "Intro", Content: "Body", Path: "hello"}))
+			sol := decode[models.Solution](t, s.admin(http.MethodPost, "/api/admin/solutions", models.Solution{Title: "Sensors", Description: "Desc", Path: "sensors"}))
+
+			missing := decode[report](t, s.admin(http.MethodGet, "/api/admin/translations/missing", nil))
+			if len(missing.Blogs) != 1 || len(missing.Solutions) != 1 || missing.Blogs[0].Missing[0] != "zh" {
+				t.Fatalf("missing report = %+v, want both items missing zh", missing)
+			}
+
+			blogTr := fmt.Sprintf("/api/admin/blogs/%d/translations", blog.ID)
+			expectStatus(t, s.admin(http.MethodPut, blogTr+"/en", models.BlogTranslation{Title: "x"}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPut, blogTr+"/fr", models.BlogTranslation{Title: "x"}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPut, blogTr+"/zh", map[string]string{"content": "no title"}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/blogs/999/translations/zh", models.BlogTranslation{Title: "x"}), http.StatusNotFound)
+
+			// Warm the cache in English first so the Chinese request below
+			// proves the locale is part of the cache key.
+			expectStatus(t, s.do(http.MethodGet, "/api/blogs/hello", nil), http.StatusOK)
+
+			w := s.admin(http.MethodPut, blogTr+"/zh-CN", models.BlogTranslation{Title: "你好", Content: "正文"})
+			expectStatus(t, w, http.StatusOK)
+			if tr := decode[models.BlogTranslation](t, w); tr.Locale != "zh" || tr.BlogID != blog.ID {
+				t.Fatalf("saved translation = %+v", tr)
+			}
+			expectStatus(t, s.admin(http.MethodPut, blogTr+"/zh", models.BlogTranslation{Title: "你好！", Content: "正文"}), http.StatusOK)
+			if list := decode[[]models.BlogTranslation](t, s.admin(http.MethodGet, blogTr, nil)); len(list) != 1 || list[0].Title != "你好！" {
+				t.Fatalf("translations = %+v, want one overwritten zh entry", list)
+			}
+
+			for _, req := range []struct {
+				path   string
+				header []string
+			}{
+				{"/api/blogs/hello?lang=zh", nil},
+				{"/api/blogs/by-path/hello", []string{"Accept-Language", "zh-CN,zh;q=0.9"}},
+				{fmt.Sprintf("/api/blogs/%d?lang=zh", blog.ID), []string{"Accept-Language", "en"}},
+			} {
+				w := s.do(http.MethodGet, req.path, nil, req.header...)
+				expectStatus(t, w, http.StatusOK)
+				got := decode[models.Blog](t, w)
+				if got.Locale != "zh" || got.Title != "你好！" || got.Content != "正文" || got.Summary != "Intro" {
+					t.Errorf("GET %s = %+v, want zh title/content with summary fallback", req.path, got)
+				}
+				if len(got.Alternates) != 3 || got.Alternates[1] != (models.Alternate{Hreflang: "zh", Href: "/blog/hello?lang=zh"}) || got.Alternates[2].Hreflang != "x-default" {
+					t.Errorf("alternates = %+v", got.Alternates)
+				}
+				if v := w.Header().Get("Vary"); !strings.Contains(v, "Accept-Language") {
+					t.Errorf("Vary = %q, want Accept-Language", v)
+				}
+			}
+			if got := decode[models.Blog](t, s.do(http.MethodGet, "/api/blogs/hello", nil)); got.Locale != "en" || got.Title != "Hello" {
+				t.Errorf("default locale blog = %+v, want original", got)
+			}
+			if list := decode[[]models.Blog](t, s.do(http.MethodGet, "/api/blogs?lang=zh", nil)); len(list) != 1 || list[0].Title != "你好！" {
+				t.Errorf("localized list = %+v", list)
+			}
+
+			solTr := fmt.Sprintf("/api/admin/solutions/%d/translations", sol.ID)
+			expectStatus(t, s.admin(http.MethodPut, solTr+"/zh", models.SolutionTranslation{Title: "传感器"}), http.StatusOK)
+			if list := decode[[]models.Solution](t, s.do(http.MethodGet, "/api/solutions", nil, "Accept-Language", "zh")); len(list) != 1 || list[0].Title != "传感器" || list[0].Description != "Desc" {
+				t.Errorf("localized solutions = %+v", list)
+			}
+			if got := decode[models.Solution](t, s.do(http.MethodGet, "/api/solutions/by-path/sensors?lang=zh", nil)); got.Title != "传感器" || len(got.Alternates) != 3 {
+				t.Errorf("localized solution = %+v", got)
+			}
+			if list := decode[[]models.SolutionTranslation](t, s.admin(http.MethodGet, solTr, nil)); len(list) != 1 {
+				t.Errorf("solution translations = %+v", list)
+			}
+
+			missing = decode[report](t, s.admin(http.MethodGet, "/api/admin/translations/missing?locale=zh", nil))
+			if len(missing.Blogs) != 0 || len(missing.Solutions) != 0 {
+				t.Errorf("missing report = %+v, want nothing missing", missing)
+			}
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/translations/missing?locale=xx", nil), http.StatusUnprocessableEntity)
+
+			expectStatus(t, s.admin(http.MethodDelete, solTr+"/zh", nil), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodDelete, solTr+"/zh", nil), http.StatusNotFound)
+			if got := decode[models.Solution](t, s.do(http.MethodGet, "/api/solutions/sensors?lang=zh", nil)); got.Locale != "en" || got.Title != "Sensors" {
+				t.Errorf("solution after translation delete = %+v, want original", got)
+			}
+			expectStatus(t, s.admin(http.MethodDelete, blogTr+"/zh", nil), http.StatusOK)
+
+			// Deleting the post takes its translations with it.
+			expectStatus(t, s.admin(http.MethodPut, blogTr+"/zh", models.BlogTranslation{Title: "你好"}), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/blogs/%d", blog.ID), nil), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodGet, blogTr, nil), http.StatusNotFound)
+		})
+	}
+}
+
+func TestCarouselCRUD(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/carousels", models.Carousel{Title: "no image"}), http.StatusUnprocessableEntity)
+
+			w := s.admin(http.MethodPost, "/api/admin/carousels", models.Carousel{Title: "Top", ImageURL: "/a.png", Rotation: 45, ImageWidth: 5000, SortOrder: 2})
+			expectStatus(t, w, http.StatusCreated)
+			top := decode[models.Carousel](t, w)
+			if top.Position != "top" || top.Rotation != 0 || top.ImageWidth != 2000 {
+				t.Errorf("created carousel = %+v, want defaults applied", top)
+			}
+
+			s.admin(http.MethodPost, "/api/admin/carousels", models.Carousel{Title: "First", ImageURL: "/b.png", SortOrder: 1})
+			s.admin(http.MethodPost, "/api/admin/carousels", models.Carousel{Title: "Bottom", ImageURL: "/c.png", Position: "bottom"})
+
+			all := decode[[]models.Carousel](t, s.do(http.MethodGet, "/api/carousels", nil))
+			if len(all) != 3 {
+				t.Fatalf("got %d carousels, want 3", len(all))
+			}
+			tops := decode[[]models.Carousel](t, s.do(http.MethodGet, "/api/carousels?position=top", nil))
+			if len(tops) != 2 || tops[0].Title != "First" {
+				t.Errorf("top carousels = %+v, want 2 ordered by sort_order", tops)
+			}
+
+			w = s.admin(http.MethodPut, fmt.Sprintf("/api/admin/carousels/%d", top.ID), models.Carousel{Title: "Top", ImageURL: "/a.png", Rotation: 90})
+			expectStatus(t, w, http.StatusOK)
+			if got := decode[models.Carousel](t, w); got.Rotation != 90 || got.Position != "top" {
+				t.Errorf("updated carousel = %+v", got)
+			}
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/carousels/999", models.Carousel{Title: "x", ImageURL: "/x"}), http.StatusNotFound)
+
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/carousels/%d", top.ID), nil), http.StatusOK)
+			if got := decode[[]models.Carousel](t, s.do(http.MethodGet, "/api/carousels?position=top", nil)); len(got) != 1 {
+				t.Errorf("got %d top carousels after delete, want 1", len(got))
+			}
+		})
+	}
+}
+
+func TestCarouselSchedule(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			now := time.Now().UTC().Truncate(time.Second)
+			create := func(title string, fields map[string]any) models.Carousel {
+				t.Helper()
+				fields["title"], fields["image_url"] = title, "/"+title+".png"
+				w := s.admin(http.MethodPost, "/api/admin/carousels", fields)
+				expectStatus(t, w, http.StatusCreated)
+				return decode[models.Carousel](t, w)
+			}
+			titles := func(path string) []string {
+				t.Helper()
+				var got []string
+				for _, c := range decode[[]models.Carousel](t, s.do(http.MethodGet, path, nil)) {
+					got = append(got, c.Title)
+				}
+				slices.Sort(got)
+				return got
+			}
+
+			always := create("always", map[string]any{})
+			hidden := create("hidden", map[string]any{"active": false})
+			create("past", map[string]any{"ends_at": now.Add(-time.Hour)})
+			create("future", map[string]any{"starts_at": now.Add(time.Hour)})
+			current := create("current", map[string]any{"starts_at": now.Add(-time.Hour), "ends_at": now.Add(time.Hour)})
+			chinese := create("chinese", map[string]any{"locale": "zh-CN"})
+
+			if always.Active == nil || !*always.Active || hidden.Active == nil || *hidden.Active {
+				t.Errorf("active = %v / %v, want true by default and false when turned off", always.Active, hidden.Active)
+			}
+			if chinese.Locale != i18n.Chinese {
+				t.Errorf("locale = %q, want %q", chinese.Locale, i18n.Chinese)
+			}
+
+			// Visitors only see enabled slides inside their window and language.
+			if got := titles("/api/carousels"); !slices.Equal(got, []string{"always", "current"}) {
+				t.Errorf("english slides = %v", got)
+			}
+			if got := titles("/api/carousels?lang=zh"); !slices.Equal(got, []string{"always", "chinese", "current"}) {
+				t.Errorf("chinese slides = %v", got)
+			}
+
+			// Admins see every slide with its schedule intact.
+			all := decode[[]models.Carousel](t, s.admin(http.MethodGet, "/api/admin/carousels", nil))
+			if len(all) != 6 {
+				t.Fatalf("admin list has %d slides, want 6", len(all))
+			}
+			for _, c := range all {
+				if c.ID == current.ID && (c.StartsAt == nil || !c.StartsAt.Equal(now.Add(-time.Hour)) || c.EndsAt == nil || !c.EndsAt.Equal(now.Add(time.Hour))) {
+					t.Errorf("current window = %v - %v", c.StartsAt, c.EndsAt)
+				}
+			}
+
+			// Turning a slide back on publishes it.
+			w := s.admin(http.MethodPut, fmt.Sprintf("/api/admin/carousels/%d", hidden.ID), map[string]any{"title": "hidden", "image_url": "/hidden.png", "active": true})
+			expectStatus(t, w, http.StatusOK)
+			if got := titles("/api/carousels"); !slices.Equal(got, []string{"always", "current", "hidden"}) {
+				t.Errorf("slides after enabling = %v", got)
+			}
+
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/carousels", map[string]any{
+				"title": "x", "image_url": "/x.png", "starts_at": now, "ends_at": now,
+			}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/carousels", map[string]any{
+				"title": "x", "image_url": "/x.png", "locale": "fr",
+			}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.do(http.MethodGet, "/api/admin/carousels", nil), http.StatusUnauthorized)
+		})
+	}
+
+	t.Run("cache expires at the next boundary", func(t *testing.T) {
+		s := newTestServer(t)
+		start := time.Now().Add(1200 * time.Millisecond)
+		w := s.admin(http.MethodPost, "/api/admin/carousels", map[string]any{"title": "sale", "image_url": "/sale.png", "starts_at": start})
+		expectStatus(t, w, http.StatusCreated)
+
+		get := func() *httptest.ResponseRecorder {
+			t.Helper()
+			w := s.do(http.MethodGet, "/api/carousels", nil)
+			expectStatus(t, w, http.StatusOK)
+			return w
+		}
+		// Browsers may not keep the list past the boundary either.
+		maxAgeBeforeStart := func(t *testing.T, w *httptest.ResponseRecorder) {
+			t.Helper()
+			control := w.Header().Get("Cache-Control")
+			if want := "public, max-age=1, must-revalidate"; control != want && control != "public, max-age=0, must-revalidate" {
+				t.Errorf("Cache-Control = %q, want %q or max-age=0", control, want)
+			}
+		}
+		maxAgeBeforeStart(t, get())
+		w = get()
+		if w.Header().Get("X-Cache") != "HIT" || len(decode[[]models.Carousel](t, w)) != 0 {
+			t.Fatalf("before start: X-Cache = %q, body = %s", w.Header().Get("X-Cache"), w.Body)
+		}
+		maxAgeBeforeStart(t, w)
+
+		time.Sleep(time.Until(start) + 100*time.Millisecond)
+		w = get()
+		if got := w.Header().Get("X-Cache"); got != "MISS" {
+			t.Errorf("after start X-Cache = %q, want MISS", got)
+		}
+		if got := decode[[]models.Carousel](t, w); len(got) != 1 || got[0].Title != "sale" {
+			t.Errorf("after start slides = %+v, want the sale", got)
+		}
+		// The slide appeared without being edited, so Last-Modified is its start.
+		if got, want := w.Header().Get("Last-Modified"), start.UTC().Format(http.TimeFormat); got != want {
+			t.Errorf("Last-Modified = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestCarouselCTA(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			w := s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Solar", Content: "c", Path: "solar"})
+			expectStatus(t, w, http.StatusCreated)
+			blog := decode[models.Blog](t, w)
+			w = s.admin(http.MethodPost, "/api/admin/solutions", models.Solution{Title: "Arm", Path: "robot-arm"})
+			expectStatus(t, w, http.StatusCreated)
+			solution := decode[models.Solution](t, w)
+
+			create := func(title string, cta map[string]any) *httptest.ResponseRecorder {
+				t.Helper()
+				cta["title"], cta["image_url"] = title, "/"+title+".png"
+				return s.admin(http.MethodPost, "/api/admin/carousels", cta)
+			}
+			hrefs := func() map[string]string {
+				t.Helper()
+				got := map[string]string{}
+				for _, c := range decode[[]models.Carousel](t, s.do(http.MethodGet, "/api/carousels", nil)) {
+					got[c.Title] = c.CTAHref
+				}
+				return got
+			}
+
+			w = create("sale", map[string]any{"cta_label": "Shop now", "cta_type": "url", "cta_url": " https://shop.example.com/sale "})
+			expectStatus(t, w, http.StatusCreated)
+			if got := decode[models.Carousel](t, w); got.CTAHref != "https://shop.example.com/sale" || got.CTALabel != "Shop now" {
+				t.Errorf("url cta = %+v", got)
+			}
+			expectStatus(t, create("contact", map[string]any{"cta_type": "url", "cta_url": "/contact"}), http.StatusCreated)
+			w = create("post", map[string]any{"cta_label": "Read", "cta_type": "blog", "cta_ref_id": blog.ID, "cta_url": "ignored"})
+			expectStatus(t, w, http.StatusCreated)
+			post := decode[models.Carousel](t, w)
+			if post.CTAHref != "/blog/solar" || post.CTAURL != "" {
+				t.Errorf("blog cta = %+v, want /blog/solar without a url", post)
+			}
+			expectStatus(t, create("arm", map[string]any{"cta_type": "solution", "cta_ref_id": solution.ID}), http.StatusCreated)
+			expectStatus(t, create("plain", map[string]any{}), http.StatusCreated)
+
+			want := map[string]string{"sale": "https://shop.example.com/sale", "contact": "/contact", "post": "/blog/solar", "arm": "/solution/robot-arm", "plain": ""}
+			if got := hrefs(); !maps.Equal(got, want) {
+				t.Errorf("hrefs = %v, want %v", got, want)
+			}
+
+			// References follow renamed paths and disappear with deleted content.
+			expectStatus(t, s.admin(http.MethodPut, fmt.Sprintf("/api/admin/blogs/%d", blog.ID), models.Blog{Title: "Solar", Content: "c", Path: "solar-power"}), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/solutions/%d", solution.ID), nil), http.StatusOK)
+			want["post"], want["arm"] = "/blog/solar-power", ""
+			if got := hrefs(); !maps.Equal(got, want) {
+				t.Errorf("hrefs after edits = %v, want %v", got, want)
+			}
+			for _, c := range decode[[]models.Carousel](t, s.admin(http.MethodGet, "/api/admin/carousels", nil)) {
+				if c.Title == "arm" && (c.CTAType != "solution" || c.CTARefID != solution.ID || c.CTAHref != "") {
+					t.Errorf("dangling cta = %+v, want the reference kept without an href", c)
+				}
+			}
+
+			// Switching to a url drops the reference.
+			w = s.admin(http.MethodPut, fmt.Sprintf("/api/admin/carousels/%d", post.ID), map[string]any{
+				"title": "post", "image_url": "/post.png", "cta_type": "url", "cta_url": "https://example.com", "cta_ref_id": blog.ID,
+			})
+			expectStatus(t, w, http.StatusOK)
+			if got := decode[models.Carousel](t, w); got.CTARefID != 0 || got.CTAHref != "https://example.com" {
+				t.Errorf("switched cta = %+v", got)
+			}
+
+			for name, cta := range map[string]map[string]any{
+				"missing blog":       {"cta_type": "blog", "cta_ref_id": 999},
+				"missing solution":   {"cta_type": "solution", "cta_ref_id": solution.ID},
+				"no reference":       {"cta_type": "blog"},
+				"script url":         {"cta_type": "url", "cta_url": "javascript:alert(1)"},
+				"protocol relative":  {"cta_type": "url", "cta_url": "//evil.example.com"},
+				"empty url":          {"cta_type": "url"},
+				"label without type": {"cta_label": "Go"},
+				"unknown type":       {"cta_type": "page", "cta_ref_id": blog.ID},
+			} {
+				if w := create("bad", cta); w.Code != http.StatusUnprocessableEntity {
+					t.Errorf("%s: status = %d, want 422", name, w.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			list := decode[[]models.Category](t, s.do(http.MethodGet, "/api/categories", nil))
+			if len(list) != 4 || list[0].Slug != "industry-trends" {
+				t.Fatalf("categories = %+v, want the 4 defaults", list)
+			}
+
+			w := s.do(http.MethodGet, fmt.Sprintf("/api/categories/%d", list[1].ID), nil)
+			expectStatus(t, w, http.StatusOK)
+			if got := decode[models.Category](t, w); got.Slug != "product-updates" {
+				t.Errorf("category = %+v", got)
+			}
+
+			expectStatus(t, s.do(http.MethodGet, "/api/categories/999", nil), http.StatusNotFound)
+			expectStatus(t, s.do(http.MethodGet, "/api/categories/abc", nil), http.StatusNotFound)
+		})
+	}
+}
+
+func TestSocialLinkCRUD(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			w := s.admin(http.MethodPost, "/api/admin/social-links", models.SocialLink{Platform: "github", URL: "https://github.com/x", SortOrder: 2})
+			expectStatus(t, w, http.StatusOK)
+			id := int(decode[map[string]any](t, w)["id"].(float64))
+			s.admin(http.MethodPost, "/api/admin/social-links", models.SocialLink{Platform: "weibo", URL: "https://weibo.com/x", SortOrder: 1})
+
+			list := decode[[]models.SocialLink](t, s.do(http.MethodGet, "/api/social-links", nil))
+			if len(list) != 2 || list[0].Platform != "weibo" {
+				t.Fatalf("social links = %+v, want 2 ordered by sort_order", list)
+			}
+
+			expectStatus(t, s.admin(http.MethodPut, fmt.Sprintf("/api/admin/social-links/%d", id), models.SocialLink{Platform: "github", URL: "https://github.com/y"}), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/social-links/abc", models.SocialLink{}), http.StatusBadRequest)
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/social-links/999", models.SocialLink{Platform: "x", URL: "https://x.com/y"}), http.StatusNotFound)
+
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/social-links/%d", id), nil), http.StatusOK)
+			if got := decode[[]models.SocialLink](t, s.do(http.MethodGet, "/api/social-links", nil)); len(got) != 1 {
+				t.Errorf("got %d links after delete, want 1", len(got))
+			}
+		})
+	}
+}
+
+func TestContactSubmission(t *testing.T) {
+	s := newTestServer(t, func(c *config.Settings) {
+		c.Contact.RateLimit = 100
+		c.Contact.SpamKeywords = []string{"casino"}
+	})
+
+	valid := func(token string) map[string]string {
+		return map[string]string{
+			"name": "Ada", "email": "ada@example.com", "subject": "Hi", "message": "Hello there",
+			"form_token": token,
+		}
+	}
+
+	tokenResp := s.do(http.MethodGet, "/api/contact/token", nil)
+	if cc := tokenResp.Header().Get("Cache-Control"); !strings.Contains(cc, "no-store") {
+		t.Errorf("token Cache-Control = %q, want no-store", cc)
+	}
+
+	tests := []struct {
+		name string
+		body func() map[string]string
+		want int
+	}{
+		{"valid", func() map[string]string { return valid(s.contactToken()) }, http.StatusCreated},
+		{"missing token", func() map[string]string { return valid("") }, http.StatusBadRequest},
+		{"forged token", func() map[string]string { return valid("1.deadbeef") }, http.StatusBadRequest},
+		{"bad email", func() map[string]string {
+			b := valid(s.contactToken())
+			b["email"] = "not-an-email"
+			return b
+		}, http.StatusUnprocessableEntity},
+		{"honeypot", func() map[string]string {
+			b := valid(s.contactToken())
+			b["website"] = "http://spam.example"
+			return b
+		}, http.StatusCreated},
+		{"spam keyword", func() map[string]string {
+			b := valid(s.contactToken())
+			b["message"] = "Visit our casino"
+			return b
+		}, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, s.do(http.MethodPost, "/api/contact", tt.body()), tt.want)
+		})
+	}
+
+	// The honeypot submission is acknowledged but never stored.
+	all := decode[[]models.Contact](t, s.admin(http.MethodGet, "/api/admin/contacts", nil))
+	if len(all) != 2 {
+		t.Fatalf("stored %d contacts, want 2 (valid + flagged spam)", len(all))
+	}
+	spam := decode[[]models.Contact](t, s.admin(http.MethodGet, "/api/admin/contacts?spam=1", nil))
+	if len(spam) != 1 || spam[0].SpamReason != "keyword:casino" {
+		t.Errorf("spam contacts = %+v", spam)
+	}
+	ham := decode[[]models.Contact](t, s.admin(http.MethodGet, "/api/admin/contacts?spam=0", nil))
+	if len(ham) != 1 || ham[0].IsSpam {
+		t.Errorf("non-spam contacts = %+v", ham)
+	}
+
+	stats := decode[map[string]any](t, s.admin(http.MethodGet, "/api/admin/contacts/stats", nil))
+	if stats["rejected"] == nil {
+		t.Errorf("stats = %v, want rejected counters", stats)
+	}
+
+	expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/contacts/%d", ham[0].ID), nil), http.StatusOK)
+	if got := decode[[]models.Contact](t, s.admin(http.MethodGet, "/api/admin/contacts", nil)); len(got) != 1 {
+		t.Errorf("got %d contacts after delete, want 1", len(got))
+	}
+}
+
+func TestContactRateLimit(t *testing.T) {
+	s := newTestServer(t, func(c *config.Settings) { c.Contact.RateLimit = 2 })
+
+	for i := 0; i < 2; i++ {
+		expectStatus(t, s.do(http.MethodPost, "/api/contact", "{"), http.StatusBadRequest)
+	}
+	w := s.do(http.MethodPost, "/api/contact", "{")
+	expectStatus(t, w, http.StatusTooManyRequests)
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("429 response has no Retry-After header")
+	}
+
+	// httptest requests come from 192.0.2.1.
+	t.Run("spoofed forwarded-for", func(t *testing.T) {
+		s := newTestServer(t, func(c *config.Settings) { c.Contact.RateLimit = 2 })
+		for i := 1; i <= 2; i++ {
+			expectStatus(t, s.do(http.MethodPost, "/api/contact", "{", "X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i)), http.StatusBadRequest)
+		}
+		expectStatus(t, s.do(http.MethodPost, "/api/contact", "{", "X-Forwarded-For", "203.0.113.3"), http.StatusTooManyRequests)
+	})
+
+	t.Run("trusted proxy", func(t *testing.T) {
+		s := newTestServer(t, func(c *config.Settings) {
+			c.Contact.RateLimit = 1
+			c.Server.TrustedProxies = []string{"192.0.2.0/24"}
+		})
+		expectStatus(t, s.do(http.MethodPost, "/api/contact", "{", "X-Forwarded-For", "203.0.113.1"), http.StatusBadRequest)
+		expectStatus(t, s.do(http.MethodPost, "/api/contact", "{", "X-Forwarded-For", "203.0.113.2"), http.StatusBadRequest)
+		expectStatus(t, s.do(http.MethodPost, "/api/contact", "{", "X-Forwarded-For", "203.0.113.1"), http.StatusTooManyRequests)
+	})
+}
+
+func TestComments(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t, func(cfg *config.Settings) {
+				cfg.Comments.RateLimit = 100
+				cfg.Contact.SpamKeywords = []string{"casino"}
+			})
+			backend.setup(s)
+
+			w := s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Solar", Content: "c", Path: "solar"})
+			expectStatus(t, w, http.StatusCreated)
+			blog := decode[models.Blog](t, w).ID
+			w = s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Wind", Content: "c", Path: "wind"})
+			expectStatus(t, w, http.StatusCreated)
+			other := decode[models.Blog](t, w).ID
+
+			comments := fmt.Sprintf("/api/blogs/%d/comments", blog)
+			post := func(path string, body map[string]any) *httptest.ResponseRecorder {
+				t.Helper()
+				body["form_token"] = s.contactToken()
+				return s.do(http.MethodPost, path, body)
+			}
+			comment := func(parent int, author string) map[string]any {
+				return map[string]any{"author": author, "email": strings.ToLower(author) + "@example.com", "content": "Nice post", "parent_id": parent}
+			}
+			pending := func() []models.Comment {
+				t.Helper()
+				return decode[[]models.Comment](t, s.admin(http.MethodGet, "/api/admin/comments?status=pending", nil))
+			}
+			approve := func(id int) {
+				t.Helper()
+				expectStatus(t, s.admin(http.MethodPut, fmt.Sprintf("/api/admin/comments/%d/status", id), map[string]string{"status": "approved"}), http.StatusOK)
+			}
+			commentCount := func() int {
+				t.Helper()
+				for _, b := range decode[[]models.Blog](t, s.do(http.MethodGet, "/api/blogs", nil)) {
+					if b.ID == blog {
+						return b.CommentCount
+					}
+				}
+				t.Fatal("blog missing from list")
+				return 0
+			}
+
+			// New comments wait for moderation.
+			root := comment(0, "Ada")
+			root["notify_replies"] = true
+			w = post(comments, root)
+			expectStatus(t, w, http.StatusCreated)
+			if got := decode[map[string]any](t, w)["status"]; got != "pending" {
+				t.Errorf("status = %v, want pending", got)
+			}
+			if got := decode[[]models.Comment](t, s.do(http.MethodGet, comments, nil)); len(got) != 0 {
+				t.Errorf("public thread = %+v, want nothing before approval", got)
+			}
+			if n := commentCount(); n != 0 {
+				t.Errorf("comment_count = %d, want 0", n)
+			}
+
+			queue := pending()
+			if len(queue) != 1 || queue[0].Email != "ada@example.com" || queue[0].BlogTitle != "Solar" || !queue[0].NotifyReplies {
+				t.Fatalf("pending = %+v", queue)
+			}
+			rootID := queue[0].ID
+
+			// Replies must target an approved comment on the same post.
+			expectStatus(t, post(comments, comment(rootID, "Bob")), http.StatusUnprocessableEntity)
+			approve(rootID)
+			expectStatus(t, post(fmt.Sprintf("/api/blogs/%d/comments", other), comment(rootID, "Bob")), http.StatusUnprocessableEntity)
+			expectStatus(t, post(comments, comment(999, "Bob")), http.StatusUnprocessableEntity)
+
+			// Approval purges the cached list and thread.
+			if n := commentCount(); n != 1 {
+				t.Errorf("comment_count = %d, want 1", n)
+			}
+			s.do(http.MethodGet, "/api/blogs/solar/comments", nil)
+			if got := s.do(http.MethodGet, "/api/blogs/solar/comments", nil).Header().Get("X-Cache"); got != "HIT" {
+				t.Errorf("thread X-Cache = %q, want HIT", got)
+			}
+
+			// Three levels deep is the default limit.
+			expectStatus(t, post(comments, comment(rootID, "Bob")), http.StatusCreated)
+			reply := pending()[0].ID
+			approve(reply)
+			expectStatus(t, post(comments, comment(reply, "Cy")), http.StatusCreated)
+			deep := pending()[0].ID
+			approve(deep)
+			expectStatus(t, post(comments, comment(deep, "Dee")), http.StatusUnprocessableEntity)
+
+			if got := s.do(http.MethodGet, "/api/blogs/solar/comments", nil).Header().Get("X-Cache"); got != "MISS" {
+				t.Errorf("thread X-Cache after approval = %q, want MISS", got)
+			}
+			thread := decode[[]models.Comment](t, s.do(http.MethodGet, comments, nil))
+			if len(thread) != 1 || thread[0].ID != rootID || thread[0].Email != "" || thread[0].NotifyReplies ||
+				len(thread[0].Replies) != 1 || thread[0].Replies[0].ID != reply ||
+				len(thread[0].Replies[0].Replies) != 1 || thread[0].Replies[0].Replies[0].ID != deep {
+				t.Fatalf("thread = %+v, want root > reply > deep without emails", thread)
+			}
+			if n := commentCount(); n != 3 {
+				t.Errorf("comment_count = %d, want 3", n)
+			}
+
+			// Spam is stored but held; the honeypot is acknowledged and dropped.
+			spam := comment(0, "Eve")
+			spam["content"] = "Best casino in town"
+			expectStatus(t, post(comments, spam), http.StatusCreated)
+			held := decode[[]models.Comment](t, s.admin(http.MethodGet, "/api/admin/comments?status=spam", nil))
+			if len(held) != 1 || held[0].SpamReason != "keyword:casino" {
+				t.Errorf("spam = %+v", held)
+			}
+			bot := comment(0, "Bot")
+			bot["website"] = "http://spam.example"
+			expectStatus(t, post(comments, bot), http.StatusCreated)
+			all := decode[[]models.Comment](t, s.admin(http.MethodGet, fmt.Sprintf("/api/admin/comments?blog_id=%d", blog), nil))
+			if len(all) != 4 {
+				t.Errorf("stored %d comments, want 4", len(all))
+			}
+
+			// Invalid input.
+			invalid := comment(0, "Fay")
+			invalid["email"] = "not-an-email"
+			expectStatus(t, post(comments, invalid), http.StatusUnprocessableEntity)
+			expectStatus(t, s.do(http.MethodPost, comments, comment(0, "Fay")), http.StatusBadRequest) // no form token
+			expectStatus(t, post("/api/blogs/999/comments", comment(0, "Fay")), http.StatusNotFound)
+			expectStatus(t, s.do(http.MethodGet, "/api/blogs/999/comments", nil), http.StatusNotFound)
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/comments?status=hidden", nil), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/comments?blog_id=x", nil), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPut, fmt.Sprintf("/api/admin/comments/%d/status", rootID), map[string]string{"status": "hidden"}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/comments/999/status", map[string]string{"status": "spam"}), http.StatusNotFound)
+			expectStatus(t, s.admin(http.MethodDelete, "/api/admin/comments/999", nil), http.StatusNotFound)
+			expectStatus(t, s.do(http.MethodGet, "/api/admin/comments", nil), http.StatusUnauthorized)
+
+			// Deleting a comment removes its replies.
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/comments/%d", reply), nil), http.StatusOK)
+			thread = decode[[]models.Comment](t, s.do(http.MethodGet, comments, nil))
+			if len(thread) != 1 || len(thread[0].Replies) != 0 {
+				t.Errorf("thread after delete = %+v, want the root alone", thread)
+			}
+			if n := commentCount(); n != 1 {
+				t.Errorf("comment_count = %d, want 1", n)
+			}
+
+			// Deleting the post removes its comments.
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/blogs/%d", blog), nil), http.StatusOK)
+			if got := decode[[]models.Comment](t, s.admin(http.MethodGet, "/api/admin/comments", nil)); len(got) != 0 {
+				t.Errorf("comments after blog delete = %+v, want none", got)
+			}
+		})
+	}
+
+	t.Run("reply notification", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Comments.RequireApproval = false })
+		w := s.admin(http.MethodPost, "/api/admin/webhooks", map[string]any{"url": "https://example.com/hook", "events": []string{"comment.replied"}})
+		expectStatus(t, w, http.StatusCreated)
+		w = s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Solar", Content: "c"})
+		expectStatus(t, w, http.StatusCreated)
+		comments := fmt.Sprintf("/api/blogs/%d/comments", decode[models.Blog](t, w).ID)
+
+		post := func(body map[string]any) int {
+			t.Helper()
+			body["content"], body["form_token"] = "Hello", s.contactToken()
+			w := s.do(http.MethodPost, comments, body)
+			expectStatus(t, w, http.StatusCreated)
+			if got := decode[map[string]any](t, w)["status"]; got != "approved" {
+				t.Fatalf("status = %v, want approved without moderation", got)
+			}
+			all := decode[[]models.Comment](t, s.admin(http.MethodGet, "/api/admin/comments", nil))
+			return all[0].ID
+		}
+		subscribed := post(map[string]any{"author": "Ada", "email": "ada@example.com", "notify_replies": true})
+		quiet := post(map[string]any{"author": "Bob", "email": "bob@example.com"})
+		reply := post(map[string]any{"author": "Cy", "email": "cy@example.com", "parent_id": subscribed})
+		post(map[string]any{"author": "Ada", "email": "ada@example.com", "parent_id": subscribed}) // own thread
+		post(map[string]any{"author": "Cy", "email": "cy@example.com", "parent_id": quiet})
+
+		// Withdrawing and re-approving the reply does not notify again.
+		status := fmt.Sprintf("/api/admin/comments/%d/status", reply)
+		expectStatus(t, s.admin(http.MethodPut, status, map[string]string{"status": "pending"}), http.StatusOK)
+		expectStatus(t, s.admin(http.MethodPut, status, map[string]string{"status": "approved"}), http.StatusOK)
+
+		deliveries := decode[[]models.WebhookDelivery](t, s.admin(http.MethodGet, "/api/admin/webhooks/deliveries", nil))
+		if len(deliveries) != 1 || deliveries[0].Event != "comment.replied" || !strings.Contains(deliveries[0].Payload, "ada@example.com") {
+			t.Fatalf("deliveries = %+v, want one comment.replied for ada", deliveries)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Comments.RateLimit = 1 })
+		expectStatus(t, s.do(http.MethodPost, "/api/blogs/1/comments", "{"), http.StatusBadRequest)
+		expectStatus(t, s.do(http.MethodPost, "/api/blogs/1/comments", "{"), http.StatusTooManyRequests)
+	})
+}
+
+// newsletterLink matches the confirmation and unsubscribe links in a mail.
+var newsletterLink = regexp.MustCompile(`/newsletter/(confirm|unsubscribe)\?token=(\S+)`)
+
+// newsletterTokens returns the tokens of the links in msg by purpose.
+func newsletterTokens(t *testing.T, text string) map[string]string {
+	t.Helper()
+	tokens := map[string]string{}
+	for _, m := range newsletterLink.FindAllStringSubmatch(text, -1) {
+		token, err := url.QueryUnescape(m[2])
+		if err != nil {
+			t.Fatalf("unescape %q: %v", m[2], err)
+		}
+		tokens[m[1]] = token
+	}
+	if tokens["confirm"] == "" || tokens["unsubscribe"] == "" {
+		t.Fatalf("mail text %q lacks confirm and unsubscribe links", text)
+	}
+	return tokens
+}
+
+func TestNewsletter(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t, func(cfg *config.Settings) { cfg.Newsletter.RateLimit = 100 })
+			backend.setup(s)
+			mail := s.recordMail()
+
+			subscribe := func(email string, header ...string) {
+				t.Helper()
+				expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", map[string]string{"email": email}, header...), http.StatusAccepted)
+			}
+			subscribers := func(status string) []models.Subscriber {
+				t.Helper()
+				return decode[[]models.Subscriber](t, s.admin(http.MethodGet, "/api/admin/newsletter/subscribers?status="+status, nil))
+			}
+			token := func(path, token string) *httptest.ResponseRecorder {
+				return s.do(http.MethodPost, "/api/newsletter/"+path, map[string]string{"token": token})
+			}
+
+			// Subscribing stores a pending address and mails a confirmation.
+			subscribe("Ada@Example.com", "Accept-Language", "zh-CN")
+			sent := mail.messages()
+			if len(sent) != 1 || sent[0].To != "ada@example.com" || sent[0].Headers["List-Unsubscribe"] == "" {
+				t.Fatalf("sent = %+v, want one confirmation to ada@example.com", sent)
+			}
+			tokens := newsletterTokens(t, sent[0].Text)
+			if got := subscribers("pending"); len(got) != 1 || got[0].Locale != i18n.Chinese || got[0].ConfirmedAt != nil {
+				t.Fatalf("pending = %+v", got)
+			}
+
+			// Only a valid confirm token confirms.
+			expectStatus(t, token("confirm", "garbage"), http.StatusBadRequest)
+			expectStatus(t, token("confirm", tokens["unsubscribe"]), http.StatusBadRequest)
+			expectStatus(t, token("confirm", tokens["confirm"]), http.StatusOK)
+			expectStatus(t, token("confirm", tokens["confirm"]), http.StatusOK)
+			confirmed := subscribers("confirmed")
+			if len(confirmed) != 1 || confirmed[0].Email != "ada@example.com" || confirmed[0].ConfirmedAt == nil {
+				t.Fatalf("confirmed = %+v", confirmed)
+			}
+
+			// A confirmed address is not mailed again, and the response is the same.
+			subscribe("ada@example.com")
+			if n := len(mail.messages()); n != 1 {
+				t.Errorf("sent %d mails, want no new one for a confirmed address", n)
+			}
+
+			w := s.admin(http.MethodGet, "/api/admin/newsletter/subscribers/export?status=confirmed", nil)
+			expectStatus(t, w, http.StatusOK)
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+				t.Errorf("export Content-Type = %q", ct)
+			}
+			if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") {
+				t.Errorf("export Content-Disposition = %q", cd)
+			}
+			lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+			if len(lines) != 2 || lines[0] != "email,status,locale,created_at,confirmed_at,unsubscribed_at" ||
+				!strings.HasPrefix(lines[1], "ada@example.com,confirmed,zh,") {
+				t.Errorf("export = %q", w.Body.String())
+			}
+
+			// Unsubscribing suppresses the address for good.
+			expectStatus(t, token("unsubscribe", tokens["confirm"]), http.StatusBadRequest)
+			expectStatus(t, token("unsubscribe", tokens["unsubscribe"]), http.StatusOK)
+			expectStatus(t, token("unsubscribe", tokens["unsubscribe"]), http.StatusOK)
+			if got := subscribers("unsubscribed"); len(got) != 1 || got[0].UnsubscribedAt == nil {
+				t.Errorf("unsubscribed = %+v", got)
+			}
+			suppressions := decode[[]models.Suppression](t, s.admin(http.MethodGet, "/api/admin/newsletter/suppressions", nil))
+			if len(suppressions) != 1 || suppressions[0].Email != "ada@example.com" || suppressions[0].Reason != "unsubscribed" {
+				t.Fatalf("suppressions = %+v", suppressions)
+			}
+			subscribe("ada@example.com")
+			if n := len(mail.messages()); n != 1 {
+				t.Errorf("sent %d mails, want none to a suppressed address", n)
+			}
+			expectStatus(t, token("confirm", tokens["confirm"]), http.StatusConflict)
+
+			// Lifting the suppression allows a fresh double opt-in.
+			expectStatus(t, s.admin(http.MethodDelete, "/api/admin/newsletter/suppressions/ADA@example.com", nil), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodDelete, "/api/admin/newsletter/suppressions/ada@example.com", nil), http.StatusNotFound)
+			subscribe("ada@example.com")
+			if n := len(mail.messages()); n != 2 {
+				t.Errorf("sent %d mails, want a new confirmation", n)
+			}
+			if got := subscribers("pending"); len(got) != 1 {
+				t.Errorf("pending after resubscribe = %+v", got)
+			}
+
+			// Bounces and complaints are suppressed by hand.
+			w = s.admin(http.MethodPost, "/api/admin/newsletter/suppressions", map[string]string{"email": "Bob@example.com", "reason": "bounce"})
+			expectStatus(t, w, http.StatusCreated)
+			if got := decode[models.Suppression](t, w); got.Email != "bob@example.com" || got.Reason != "bounce" {
+				t.Errorf("suppression = %+v", got)
+			}
+			subscribe("bob@example.com")
+			if n := len(mail.messages()); n != 2 {
+				t.Errorf("sent %d mails, want none to a bounced address", n)
+			}
+
+			// The honeypot is acknowledged and dropped.
+			expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", map[string]string{"email": "bot@example.com", "website": "x"}), http.StatusAccepted)
+			if got := subscribers(""); len(got) != 1 {
+				t.Errorf("subscribers = %+v, want ada alone", got)
+			}
+
+			// Invalid input.
+			expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", map[string]string{"email": "nope"}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.do(http.MethodPost, "/api/newsletter/confirm", map[string]string{}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/newsletter/subscribers?status=bogus", nil), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/newsletter/subscribers/export?status=bogus", nil), http.StatusUnprocessableEntity)
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/newsletter/suppressions", map[string]string{"email": "nope"}), http.StatusUnprocessableEntity)
+			expectStatus(t, s.do(http.MethodGet, "/api/admin/newsletter/subscribers", nil), http.StatusUnauthorized)
+		})
+	}
+
+	t.Run("expired confirmation", func(t *testing.T) {
+		s := newTestServer(t)
+		stale := newsletter.Token(newsletter.PurposeConfirm, "ada@example.com", time.Now().Add(-49*time.Hour))
+		w := s.do(http.MethodPost, "/api/newsletter/confirm", map[string]string{"token": stale})
+		expectStatus(t, w, http.StatusBadRequest)
+		if got := decode[map[string]any](t, w)["code"]; got != "newsletter_token_expired" {
+			t.Errorf("code = %v, want newsletter_token_expired", got)
+		}
+
+		// Unsubscribe links keep working.
+		old := newsletter.Token(newsletter.PurposeUnsubscribe, "ada@example.com", time.Now().Add(-365*24*time.Hour))
+		expectStatus(t, s.do(http.MethodPost, "/api/newsletter/unsubscribe", map[string]string{"token": old}), http.StatusOK)
+	})
+
+	t.Run("mail failure", func(t *testing.T) {
+		s := newTestServer(t)
+		s.recordMail().err = errors.New("connection refused")
+		expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", map[string]string{"email": "ada@example.com"}), http.StatusServiceUnavailable)
+	})
+
+	t.Run("mail sent without the db lock", func(t *testing.T) {
+		s := newTestServer(t)
+		m := s.recordMail()
+		// A restore must be able to take the write lock while the mail is out.
+		m.onSend = func() error {
+			locked := make(chan struct{})
+			go func() {
+				middleware.LockDBWrite()
+				middleware.UnlockDBWrite()
+				close(locked)
+			}()
+			select {
+			case <-locked:
+				return nil
+			case <-time.After(2 * time.Second):
+				return errors.New("db read lock held while sending")
+			}
+		}
+		expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", map[string]string{"email": "ada@example.com"}), http.StatusAccepted)
+		if len(m.messages()) != 1 {
+			t.Errorf("sent %d messages, want 1", len(m.messages()))
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Newsletter.RateLimit = 1 })
+		expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", "{"), http.StatusBadRequest)
+		expectStatus(t, s.do(http.MethodPost, "/api/newsletter/subscribe", "{"), http.StatusTooManyRequests)
+	})
+}
+
+func TestWebhookCRUD(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			bad := []struct {
+				name string
+				body map[string]any
+			}{
+				{"relative url", map[string]any{"url": "/hook"}},
+				{"ftp url", map[string]any{"url": "ftp://example.com/hook"}},
+				{"unknown event", map[string]any{"url": "https://example.com/hook", "events": []string{"blog.exploded"}}},
+			}
+			for _, tt := range bad {
+				t.Run(tt.name, func(t *testing.T) {
+					expectStatus(t, s.admin(http.MethodPost, "/api/admin/webhooks", tt.body), http.StatusUnprocessableEntity)
+				})
+			}
+
+			w := s.admin(http.MethodPost, "/api/admin/webhooks", map[string]any{
+				"url": "https://example.com/hook", "secret": "shh", "events": []string{"blog.published"},
+			})
+			expectStatus(t, w, http.StatusCreated)
+			hook := decode[models.Webhook](t, w)
+			if !hook.Active || hook.Secret != "" {
+				t.Errorf("created webhook = %+v, want active and no secret echoed", hook)
+			}
+
+			// Without a secret one is generated and shown once, so every delivery is signed.
+			w = s.admin(http.MethodPost, "/api/admin/webhooks", map[string]any{"url": "https://example.com/unsigned", "events": []string{"db.restored"}})
+			expectStatus(t, w, http.StatusCreated)
+			generated := decode[models.Webhook](t, w)
+			if len(generated.Secret) != 64 {
+				t.Errorf("generated secret = %q, want 64 hex characters", generated.Secret)
+			}
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/webhooks/%d", generated.ID), nil), http.StatusOK)
+
+			list := decode[struct {
+				Webhooks []models.Webhook `json:"webhooks"`
+				Events   []string         `json:"available_events"`
+			}](t, s.admin(http.MethodGet, "/api/admin/webhooks", nil))
+			if len(list.Webhooks) != 1 || list.Webhooks[0].Secret != "" || len(list.Events) == 0 {
+				t.Errorf("webhook list = %+v", list)
+			}
+
+			// Publishing a blog enqueues one delivery for the subscription.
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Hook Test", Content: "c"}), http.StatusCreated)
+			deliveries := decode[[]models.WebhookDelivery](t, s.admin(http.MethodGet, fmt.Sprintf("/api/admin/webhooks/deliveries?webhook_id=%d", hook.ID), nil))
+			if len(deliveries) != 1 || deliveries[0].Event != "blog.published" || deliveries[0].Status != "pending" {
+				t.Fatalf("deliveries = %+v, want one pending blog.published", deliveries)
+			}
+			expectStatus(t, s.admin(http.MethodGet, "/api/admin/webhooks/deliveries?webhook_id=abc", nil), http.StatusUnprocessableEntity)
+
+			expectStatus(t, s.admin(http.MethodPost, fmt.Sprintf("/api/admin/webhooks/deliveries/%d/retry", deliveries[0].ID), nil), http.StatusOK)
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/webhooks/deliveries/999/retry", nil), http.StatusNotFound)
+
+			w = s.admin(http.MethodPut, fmt.Sprintf("/api/admin/webhooks/%d", hook.ID), map[string]any{"url": "https://example.com/v2", "active": false})
+			expectStatus(t, w, http.StatusOK)
+			if got := decode[models.Webhook](t, w); got.Active || got.URL != "https://example.com/v2" {
+				t.Errorf("updated webhook = %+v", got)
+			}
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/webhooks/999", map[string]any{"url": "https://example.com"}), http.StatusNotFound)
+
+			expectStatus(t, s.admin(http.MethodDelete, fmt.Sprintf("/api/admin/webhooks/%d", hook.ID), nil), http.StatusOK)
+			if got := decode[[]models.WebhookDelivery](t, s.admin(http.MethodGet, "/api/admin/webhooks/deliveries", nil)); len(got) != 0 {
+				t.Errorf("deliveries after delete = %+v, want none", got)
+			}
+		})
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	s := newTestServer(t)
+
+	get := func(path string) string {
+		t.Helper()
+		w := s.do(http.MethodGet, path, nil)
+		expectStatus(t, w, http.StatusOK)
+		return w.Header().Get("X-Cache")
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		purge func()
+	}{
+		{"blogs", "/api/blogs", func() {
+			s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "New", Content: "c"})
+		}},
+		{"solutions", "/api/solutions", func() {
+			s.admin(http.MethodPost, "/api/admin/solutions", models.Solution{Title: "New", Description: "d"})
+		}},
+		{"carousels", "/api/carousels?position=top", func() {
+			s.admin(http.MethodPost, "/api/admin/carousels", models.Carousel{Title: "New", ImageURL: "/n.png"})
+		}},
+		{"social links", "/api/social-links", func() {
+			s.admin(http.MethodPost, "/api/admin/social-links", models.SocialLink{Platform: "x", URL: "https://x.com"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := get(tt.path); got != "MISS" {
+				t.Fatalf("first GET X-Cache = %q, want MISS", got)
+			}
+			if got := get(tt.path); got != "HIT" {
+				t.Fatalf("second GET X-Cache = %q, want HIT", got)
+			}
+			tt.purge()
+			if got := get(tt.path); got != "MISS" {
+				t.Fatalf("GET after write X-Cache = %q, want MISS (cache purged)", got)
+			}
+		})
+	}
+
+	t.Run("query string is part of the key", func(t *testing.T) {
+		get("/api/carousels?position=bottom")
+		if got := get("/api/carousels?position=side"); got != "MISS" {
+			t.Errorf("X-Cache = %q, want MISS for a different query", got)
+		}
+	})
+
+	t.Run("admin and no-store responses are not cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if got := s.admin(http.MethodGet, "/api/admin/contacts", nil).Header().Get("X-Cache"); got != "" {
+				t.Errorf("admin X-Cache = %q, want none", got)
+			}
+		}
+		get("/api/contact/token")
+		if got := get("/api/contact/token"); got != "MISS" {
+			t.Errorf("contact token X-Cache = %q, want MISS", got)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			w := s.do(http.MethodGet, "/api/blogs/missing", nil)
+			expectStatus(t, w, http.StatusNotFound)
+			if got := w.Header().Get("X-Cache"); got != "MISS" {
+				t.Errorf("X-Cache = %q, want MISS", got)
+			}
+		}
+	})
+}
+
+func TestMemoryCacheFallback(t *testing.T) {
+	withoutRedis := func(backend string, maxEntries int) serverOption {
+		return func(cfg *config.Settings) {
+			cfg.Redis.Addr = ""
+			cfg.Cache.Backend = backend
+			cfg.Cache.MemoryMaxEntries = maxEntries
+		}
+	}
+	xcache := func(t *testing.T, s *testServer, path string) string {
+		t.Helper()
+		w := s.do(http.MethodGet, path, nil)
+		expectStatus(t, w, http.StatusOK)
+		return w.Header().Get("X-Cache")
+	}
+
+	t.Run("auto falls back to memory", func(t *testing.T) {
+		s := newTestServer(t, withoutRedis("auto", 100))
+		ready := decode[map[string]any](t, s.do(http.MethodGet, "/readyz", nil))
+		if got := ready["checks"].(map[string]any)["cache"]; got != "memory" {
+			t.Errorf("readyz cache = %v, want memory", got)
+		}
+
+		for i, want := range []string{"MISS", "HIT"} {
+			if got := xcache(t, s, "/api/blogs"); got != want {
+				t.Fatalf("GET #%d X-Cache = %q, want %s", i+1, got, want)
+			}
+		}
+		xcache(t, s, "/api/solutions")
+		expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "New", Content: "c"}), http.StatusCreated)
+		if got := xcache(t, s, "/api/blogs"); got != "MISS" {
+			t.Errorf("blogs after write X-Cache = %q, want MISS (purged)", got)
+		}
+		if got := xcache(t, s, "/api/solutions"); got != "HIT" {
+			t.Errorf("solutions after blog write X-Cache = %q, want HIT (other pattern)", got)
+		}
+	})
+
+	t.Run("none disables the cache", func(t *testing.T) {
+		s := newTestServer(t, withoutRedis("none", 100))
+		for i := 0; i < 2; i++ {
+			if got := xcache(t, s, "/api/blogs"); got != "" {
+				t.Errorf("X-Cache = %q, want none", got)
+			}
+		}
+	})
+}
+
+func TestCacheTagInvalidation(t *testing.T) {
+	stores := []struct {
+		name string
+		opt  serverOption
+	}{
+		{"redis", func(*config.Settings) {}},
+		{"memory", func(cfg *config.Settings) { cfg.Cache.Backend = "memory" }},
+	}
+	for _, store := range stores {
+		t.Run(store.name, func(t *testing.T) {
+			s := newTestServer(t, store.opt)
+			for _, title := range []string{"First", "Second"} {
+				expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: title, Content: "c"}), http.StatusCreated)
+			}
+
+			paths := []string{"/api/blogs", "/api/blogs/1", "/api/blogs/by-path/first", "/api/blogs/2", "/api/categories", "/api/solutions"}
+			xcache := func(path string) string {
+				t.Helper()
+				w := s.do(http.MethodGet, path, nil)
+				expectStatus(t, w, http.StatusOK)
+				return w.Header().Get("X-Cache")
+			}
+			for _, path := range paths {
+				xcache(path)
+				if got := xcache(path); got != "HIT" {
+					t.Fatalf("%s X-Cache = %q, want HIT before the write", path, got)
+				}
+			}
+			if store.name == "redis" && !s.redis.Exists("cache:v2:tag:blogs:1") {
+				t.Error("tag set for blogs:1 missing")
+			}
+
+			expectStatus(t, s.admin(http.MethodPut, "/api/admin/blogs/1", models.Blog{Title: "First", Path: "first", Content: "changed"}), http.StatusOK)
+			if store.name == "redis" && s.redis.Exists("cache:v2:tag:blogs:1") {
+				t.Error("tag set for blogs:1 not deleted by the purge")
+			}
+
+			want := map[string]string{
+				"/api/blogs":               "MISS", // list
+				"/api/blogs/1":             "MISS", // the updated blog, by id
+				"/api/blogs/by-path/first": "MISS", // and by path
+				"/api/blogs/2":             "HIT",  // other blogs are untouched
+				"/api/categories":          "MISS", // counts depend on blogs
+				"/api/solutions":           "HIT",
+			}
+			for _, path := range paths {
+				if got := xcache(path); got != want[path] {
+					t.Errorf("%s X-Cache after blog update = %q, want %s", path, got, want[path])
+				}
+			}
+		})
+	}
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	blogCount := func(t *testing.T, w *httptest.ResponseRecorder) int {
+		return len(decode[[]models.Blog](t, w))
+	}
+	// insertBlog writes straight to the database, bypassing cache invalidation.
+	insertBlog := func(t *testing.T, title string) {
+		t.Helper()
+		if _, err := config.DB.Exec("INSERT INTO blogs (title, summary, content, path) VALUES (?, '', 'c', ?)", title, strings.ToLower(title)); err != nil {
+			t.Fatalf("insert blog: %v", err)
+		}
+	}
+	eventually := func(t *testing.T, what string, cond func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if cond() {
+				return
+			}
+		}
+		t.Fatalf("timed out waiting for %s", what)
+	}
+
+	t.Run("expired entries are served stale and refreshed once", func(t *testing.T) {
+		s := newTestServer(t)
+		expectStatus(t, s.do(http.MethodGet, "/api/blogs", nil), http.StatusOK)
+
+		// Move the entry past its fresh period without waiting for the TTL.
+		key := cache.CacheKey(http.MethodGet, "/api/blogs", "#"+i18n.Default)
+		raw := s.redis.HGet(key, "meta")
+		var entry cache.CachedResponse
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil || entry.FreshUntil == 0 {
+			t.Fatalf("entry meta = %q, want fresh_until set", raw)
+		}
+		entry.FreshUntil = 1
+		b, _ := json.Marshal(entry)
+		s.redis.HSet(key, "meta", string(b))
+		insertBlog(t, "Added")
+
+		w := s.do(http.MethodGet, "/api/blogs", nil)
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get("X-Cache"); got != "STALE" {
+			t.Fatalf("X-Cache = %q, want STALE", got)
+		}
+		if n := blogCount(t, w); n != 0 {
+			t.Errorf("stale response has %d blogs, want the old (empty) list", n)
+		}
+
+		eventually(t, "background refresh", func() bool {
+			w := s.do(http.MethodGet, "/api/blogs", nil)
+			return w.Header().Get("X-Cache") == "HIT" && blogCount(t, w) == 1
+		})
+	})
+
+	t.Run("concurrent misses are coalesced", func(t *testing.T) {
+		s := newTestServer(t)
+		const n = 20
+		results := make(chan string, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w := s.do(http.MethodGet, "/api/solutions", nil)
+				if w.Code != http.StatusOK {
+					results <- fmt.Sprintf("status %d", w.Code)
+					return
+				}
+				results <- w.Header().Get("X-Cache")
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		misses := 0
+		for r := range results {
+			switch r {
+			case "MISS":
+				misses++
+			case "HIT", "COALESCED":
+			default:
+				t.Errorf("unexpected result %q", r)
+			}
+		}
+		if misses != 1 {
+			t.Errorf("%d requests ran the handler, want 1", misses)
+		}
+	})
+
+	t.Run("configured paths are warmed after a purge", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) { cfg.Cache.WarmPaths = []string{"/api/blogs"} })
+		expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Warm", Content: "c"}), http.StatusCreated)
+
+		key := cache.CacheKey(http.MethodGet, "/api/blogs", "#"+i18n.Default)
+		eventually(t, "warm-up", func() bool { return s.redis.Exists(key) })
+
+		w := s.do(http.MethodGet, "/api/blogs", nil)
+		if got := w.Header().Get("X-Cache"); got != "HIT" {
+			t.Errorf("X-Cache after warm-up = %q, want HIT", got)
+		}
+		if n := blogCount(t, w); n != 1 {
+			t.Errorf("warmed list has %d blogs, want 1", n)
+		}
+	})
+}
+
+func TestCompressedResponses(t *testing.T) {
+	s := newTestServer(t)
+	long := strings.Repeat("compressible content ", 200)
+	expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Long", Content: long}), http.StatusCreated)
+
+	identity := s.do(http.MethodGet, "/api/blogs", nil)
+	expectStatus(t, identity, http.StatusOK)
+	if got := identity.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding without Accept-Encoding = %q, want none", got)
+	}
+	plain := identity.Body.String()
+	etag := identity.Header().Get("ETag")
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"gzip", "gzip"},
+		{"br", "br"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"*", "br"},
+		{"gzip;q=0, br;q=0", ""},
+		{"deflate", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			w := s.do(http.MethodGet, "/api/blogs", nil, "Accept-Encoding", tt.accept)
+			expectStatus(t, w, http.StatusOK)
+			if got := w.Header().Get("X-Cache"); got != "HIT" {
+				t.Errorf("X-Cache = %q, want HIT", got)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+			if v := w.Header().Get("Vary"); !strings.Contains(v, "Accept-Encoding") {
+				t.Errorf("Vary = %q, want Accept-Encoding", v)
+			}
+
+			body := io.Reader(w.Body)
+			if tt.want != "" {
+				if w.Body.Len() >= len(plain) {
+					t.Errorf("%s body is %d bytes, identity %d", tt.want, w.Body.Len(), len(plain))
+				}
+				if got := w.Header().Get("ETag"); got == etag || !strings.Contains(got, tt.want) {
+					t.Errorf("ETag = %q, want a %s variant of %s", got, tt.want, etag)
+				}
+				var err error
+				if body, err = decoders[tt.want](body); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+			}
+			raw, err := io.ReadAll(body)
+			if err != nil || string(raw) != plain {
+				t.Errorf("decoded body differs from identity (err %v)", err)
+			}
+		})
+	}
+
+	t.Run("validators match every coding", func(t *testing.T) {
+		gz := s.do(http.MethodGet, "/api/blogs", nil, "Accept-Encoding", "gzip")
+		w := s.do(http.MethodGet, "/api/blogs", nil, "Accept-Encoding", "br", "If-None-Match", gz.Header().Get("ETag"))
+		expectStatus(t, w, http.StatusNotModified)
+	})
+
+	t.Run("stored as raw compressed bytes", func(t *testing.T) {
+		key := cache.CacheKey(http.MethodGet, "/api/blogs", "#"+i18n.Default)
+		fields, err := s.redis.HKeys(key)
+		if err != nil {
+			t.Fatalf("entry: %v", err)
+		}
+		if got := strings.Join(fields, ","); got != "br,gzip,meta" {
+			t.Errorf("hash fields = %s, want br,gzip,meta", got)
+		}
+		if br := s.redis.HGet(key, "br"); len(br) >= len(plain) {
+			t.Errorf("stored br body is %d bytes, identity %d", len(br), len(plain))
+		}
+	})
+
+	t.Run("small bodies are not compressed", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/social-links", nil, "Accept-Encoding", "br, gzip")
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Content-Encoding = %q, want none", got)
+		}
+	})
+
+	t.Run("previous key version is purged on start", func(t *testing.T) {
+		s.redis.Set("cache:v1:GET:/api/blogs:old", `{"status":200}`)
+		cache.Init()
+		deadline := time.Now().Add(2 * time.Second)
+		for s.redis.Exists("cache:v1:GET:/api/blogs:old") && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if s.redis.Exists("cache:v1:GET:/api/blogs:old") {
+			t.Error("cache:v1 key still present")
+		}
+	})
+}
+
+func TestRedisRecovery(t *testing.T) {
+	type status struct {
+		Redis config.RedisStatus `json:"redis"`
+		Cache struct {
+			Store string `json:"store"`
+		} `json:"cache"`
+	}
+	cacheStatus := func(t *testing.T, s *testServer) status {
+		t.Helper()
+		w := s.admin(http.MethodGet, "/api/admin/cache/status", nil)
+		expectStatus(t, w, http.StatusOK)
+		return decode[status](t, w)
+	}
+	eventually := func(t *testing.T, what string, cond func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+			if cond() {
+				return
+			}
+		}
+		t.Fatalf("timed out waiting for %s", what)
+	}
+
+	t.Run("reconnects after failing at startup", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		addr := mr.Addr()
+		mr.Close()
+		s := newTestServer(t, func(cfg *config.Settings) {
+			cfg.Redis.Addr = addr
+			cfg.Redis.HealthIntervalSeconds = 1
+		})
+
+		st := cacheStatus(t, s)
+		if st.Redis.State != config.RedisDisconnected || st.Cache.Store != "memory" {
+			t.Fatalf("status = %+v, want disconnected with memory fallback", st)
+		}
+		s.do(http.MethodGet, "/api/blogs", nil)
+		if got := s.do(http.MethodGet, "/api/blogs", nil).Header().Get("X-Cache"); got != "HIT" {
+			t.Errorf("fallback X-Cache = %q, want HIT", got)
+		}
+
+		if err := mr.Restart(); err != nil {
+			t.Fatal(err)
+		}
+		eventually(t, "redis to reconnect", func() bool {
+			return cacheStatus(t, s).Redis.State == config.RedisConnected
+		})
+		if st := cacheStatus(t, s); st.Cache.Store != "redis" {
+			t.Errorf("status = %+v, want redis store after reconnecting", st)
+		}
+		// The first request starts the background flush; writes resume once
+		// it is done.
+		eventually(t, "a response to be written to Redis", func() bool {
+			s.do(http.MethodGet, "/api/blogs", nil)
+			return len(mr.Keys()) > 0
+		})
+	})
+
+	t.Run("circuit breaker skips redis and flushes on recovery", func(t *testing.T) {
+		s := newTestServer(t, func(cfg *config.Settings) {
+			cfg.Redis.HealthIntervalSeconds = 60
+			cfg.Redis.BreakerThreshold = 2
+			cfg.Redis.BreakerCooldownSeconds = 1
+		})
+		s.do(http.MethodGet, "/api/blogs", nil)
+		if got := s.do(http.MethodGet, "/api/blogs", nil).Header().Get("X-Cache"); got != "HIT" {
+			t.Fatalf("X-Cache = %q, want HIT from redis", got)
+		}
+
+		// A failed read and the failed write after it open the circuit.
+		s.redis.SetError("LOADING slow redis")
+		s.do(http.MethodGet, "/api/blogs", nil)
+		if st := cacheStatus(t, s); st.Redis.State != config.RedisCircuitOpen || st.Cache.Store != "memory" {
+			t.Fatalf("status = %+v, want circuit_open with memory fallback", st)
+		}
+		commands := s.redis.CommandCount()
+		s.do(http.MethodGet, "/api/blogs", nil)
+		if got := s.redis.CommandCount(); got != commands {
+			t.Errorf("redis received %d commands while the circuit was open", got-commands)
+		}
+
+		// This purge cannot reach Redis, whose entry still lists no blogs.
+		expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "During outage", Summary: "s", Content: "c"}), http.StatusCreated)
+		s.redis.SetError("")
+
+		eventually(t, "circuit to close", func() bool {
+			return cacheStatus(t, s).Redis.State == config.RedisConnected
+		})
+		w := s.do(http.MethodGet, "/api/blogs", nil)
+		if got := w.Header().Get("X-Cache"); got != "MISS" {
+			t.Errorf("X-Cache after recovery = %q, want MISS", got)
+		}
+		if !strings.Contains(w.Body.String(), "During outage") {
+			t.Errorf("stale list served after recovery: %s", w.Body.String())
+		}
+	})
+}
+
+func TestConditionalGet(t *testing.T) {
+	s := newTestServer(t)
+	expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Hello", Content: "c"}), http.StatusCreated)
+
+	first := s.do(http.MethodGet, "/api/blogs", nil)
+	expectStatus(t, first, http.StatusOK)
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+	if !strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, "W/") {
+		t.Fatalf("ETag = %q, want a strong entity tag", etag)
+	}
+	if lastModified == "" {
+		t.Fatal("Last-Modified missing")
+	}
+	if got := first.Header().Get("Cache-Control"); got != config.Current.Cache.Control {
+		t.Errorf("Cache-Control = %q, want default %q", got, config.Current.Cache.Control)
+	}
+
+	notModified := func(t *testing.T, w *httptest.ResponseRecorder, xcache string) {
+		t.Helper()
+		expectStatus(t, w, http.StatusNotModified)
+		if w.Body.Len() != 0 {
+			t.Errorf("304 body = %q, want empty", w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Errorf("304 ETag = %q, want %q", got, etag)
+		}
+		if got := w.Header().Get("X-Cache"); got != xcache {
+			t.Errorf("X-Cache = %q, want %s", got, xcache)
+		}
+	}
+
+	t.Run("If-None-Match on a cache hit", func(t *testing.T) {
+		notModified(t, s.do(http.MethodGet, "/api/blogs", nil, "If-None-Match", etag), "HIT")
+		notModified(t, s.do(http.MethodGet, "/api/blogs", nil, "If-None-Match", `"other", W/`+etag), "HIT")
+	})
+
+	t.Run("If-None-Match on a cache miss", func(t *testing.T) {
+		s.redis.FlushAll()
+		notModified(t, s.do(http.MethodGet, "/api/blogs", nil, "If-None-Match", etag), "MISS")
+	})
+
+	t.Run("If-Modified-Since", func(t *testing.T) {
+		notModified(t, s.do(http.MethodGet, "/api/blogs", nil, "If-Modified-Since", lastModified), "HIT")
+
+		since, _ := http.ParseTime(lastModified)
+		earlier := since.Add(-time.Hour).Format(http.TimeFormat)
+		expectStatus(t, s.do(http.MethodGet, "/api/blogs", nil, "If-Modified-Since", earlier), http.StatusOK)
+	})
+
+	t.Run("If-None-Match takes precedence", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/blogs", nil, "If-None-Match", `"stale"`, "If-Modified-Since", lastModified)
+		expectStatus(t, w, http.StatusOK)
+	})
+
+	t.Run("writes change the ETag", func(t *testing.T) {
+		expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Second", Content: "c"}), http.StatusCreated)
+		w := s.do(http.MethodGet, "/api/blogs", nil, "If-None-Match", etag)
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get("ETag"); got == etag || got == "" {
+			t.Errorf("ETag after write = %q, want a new tag", got)
+		}
+	})
+
+	t.Run("per-route Cache-Control", func(t *testing.T) {
+		for i := 0; i < 2; i++ { // MISS, then HIT from the stored entry
+			w := s.do(http.MethodGet, "/api/carousels", nil)
+			expectStatus(t, w, http.StatusOK)
+			if got := w.Header().Get("Cache-Control"); !strings.Contains(got, "max-age=60") {
+				t.Errorf("carousels Cache-Control = %q, want max-age=60", got)
+			}
+		}
+	})
+
+	t.Run("no-store responses have no validators", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/contact/token", nil)
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get("ETag"); got != "" {
+			t.Errorf("contact token ETag = %q, want none", got)
+		}
+		if got := w.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("contact token Cache-Control = %q, want no-store", got)
+		}
+	})
+}
+
+func TestBackupDownload(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.admin(http.MethodGet, "/api/admin/db/backup", nil)
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); ct != "application/gzip" {
+		t.Errorf("Content-Type = %q, want application/gzip", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "attachment") {
+		t.Errorf("Content-Disposition = %q, want attachment", cd)
+	}
+	if db := gunzip(t, w.Body.Bytes()); !bytes.HasPrefix(db, []byte("SQLite format 3\x00")) {
+		t.Error("backup does not contain a SQLite database")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	archives := []struct {
+		name  string
+		file  string
+		build func(t *testing.T, db []byte) []byte
+	}{
+		{"gzip", "backup.db.gz", func(t *testing.T, db []byte) []byte { return gzipBytes(t, db) }},
+		{"zip", "backup.zip", func(t *testing.T, db []byte) []byte {
+			return zipBytes(t, map[string][]byte{"data.db": db})
+		}},
+		{"tar", "backup.tar", func(t *testing.T, db []byte) []byte {
+			return tarBytes(t, map[string][]byte{"data.db": db})
+		}},
+		{"tgz", "backup.tgz", func(t *testing.T, db []byte) []byte {
+			return gzipBytes(t, tarBytes(t, map[string][]byte{"data.db": db}))
+		}},
+		{"tar.gz", "backup.tar.gz", func(t *testing.T, db []byte) []byte {
+			return gzipBytes(t, tarBytes(t, map[string][]byte{"data.db": db}))
+		}},
+		{"raw", "data.db", func(t *testing.T, db []byte) []byte { return db }},
+	}
+
+	for _, tt := range archives {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Before", Content: "c"}), http.StatusCreated)
+			backup := s.admin(http.MethodGet, "/api/admin/db/backup", nil)
+			expectStatus(t, backup, http.StatusOK)
+			db := gunzip(t, backup.Body.Bytes())
+
+			expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "After", Content: "c"}), http.StatusCreated)
+			s.do(http.MethodGet, "/api/blogs", nil) // warm the cache
+
+			w := s.upload(tt.file, tt.build(t, db))
+			expectStatus(t, w, http.StatusOK)
+
+			list := s.do(http.MethodGet, "/api/blogs", nil)
+			if got := list.Header().Get("X-Cache"); got != "MISS" {
+				t.Errorf("X-Cache after restore = %q, want MISS", got)
+			}
+			blogs := decode[[]models.Blog](t, list)
+			if len(blogs) != 1 || blogs[0].Title != "Before" {
+				t.Errorf("blogs after restore = %+v, want only the backed-up post", blogs)
+			}
+			expectStatus(t, s.do(http.MethodGet, "/readyz", nil), http.StatusOK)
+		})
+	}
+}
+
+func TestRestoreRejectsMalformedUploads(t *testing.T) {
+	s := newTestServer(t)
+	expectStatus(t, s.admin(http.MethodPost, "/api/admin/blogs", models.Blog{Title: "Keep", Content: "c"}), http.StatusCreated)
+
+	notSQLite := bytes.Repeat([]byte("not a database "), 10)
+	tests := []struct {
+		name    string
+		file    string
+		content []byte
+	}{
+		{"missing file field", "", nil},
+		{"raw non-sqlite", "data.db", notSQLite},
+		{"too small", "data.db", []byte("SQLite")},
+		{"corrupt gzip", "backup.gz", []byte("definitely not gzip")},
+		{"gzip of non-sqlite", "backup.gz", gzipBytes(t, notSQLite)},
+		{"corrupt zip", "backup.zip", []byte("PK garbage")},
+		{"empty zip", "backup.zip", zipBytes(t, nil)},
+		{"zip of non-sqlite", "backup.zip", zipBytes(t, map[string][]byte{"data.db": notSQLite})},
+		{"corrupt tar", "backup.tar", bytes.Repeat([]byte{0xff}, 1024)},
+		{"empty tar", "backup.tar", tarBytes(t, nil)},
+		{"corrupt tgz", "backup.tgz", []byte("nope")},
+		{"tgz of non-sqlite", "backup.tar.gz", gzipBytes(t, tarBytes(t, map[string][]byte{"data.db": notSQLite}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := http.StatusBadRequest
+			if tt.file == "" {
+				want = http.StatusUnprocessableEntity
+			}
+			expectStatus(t, s.upload(tt.file, tt.content), want)
+		})
+	}
+
+	// The live database is untouched.
+	blogs := decode[[]models.Blog](t, s.do(http.MethodGet, "/api/blogs", nil))
+	if len(blogs) != 1 || blogs[0].Title != "Keep" {
+		t.Errorf("blogs after rejected restores = %+v", blogs)
+	}
+}
+
+// apiError is the error envelope written by middleware.AbortWithError.
+type apiError struct {
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+	Details []struct {
+		Field   string `json:"field"`
+		Rule    string `json:"rule"`
+		Param   string `json:"param"`
+		Message string `json:"message"`
+	} `json:"details"`
+	RequestID string `json:"request_id"`
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		w      func() *httptest.ResponseRecorder
+		status int
+		code   string
+		field  string
+		rule   string
+	}{
+		{"malformed json", func() *httptest.ResponseRecorder {
+			return s.admin(http.MethodPost, "/api/admin/blogs", "{")
+		}, http.StatusBadRequest, "invalid_json", "", ""},
+		{"missing required field", func() *httptest.ResponseRecorder {
+			return s.admin(http.MethodPost, "/api/admin/blogs", map[string]string{"content": "no title"})
+		}, http.StatusUnprocessableEntity, "validation_failed", "title", "required"},
+		{"wrong field type", func() *httptest.ResponseRecorder {
+			return s.admin(http.MethodPost, "/api/admin/carousels", map[string]any{"title": "t", "image_url": "/a.png", "sort_order": "first"})
+		}, http.StatusUnprocessableEntity, "validation_failed", "sort_order", "type"},
+		{"invalid id", func() *httptest.ResponseRecorder {
+			return s.admin(http.MethodDelete, "/api/admin/blogs/abc", nil)
+		}, http.StatusBadRequest, "invalid_id", "", ""},
+		{"not found", func() *httptest.ResponseRecorder {
+			return s.do(http.MethodGet, "/api/solutions/missing", nil)
+		}, http.StatusNotFound, "not_found", "", ""},
+		{"unknown route", func() *httptest.ResponseRecorder {
+			return s.do(http.MethodGet, "/api/nope", nil)
+		}, http.StatusNotFound, "route_not_found", "", ""},
+		{"bad credentials", func() *httptest.ResponseRecorder {
+			return s.do(http.MethodPost, "/api/admin/login", map[string]string{"username": "admin", "password": "nope"})
+		}, http.StatusUnauthorized, "invalid_credentials", "", ""},
+		{"missing token", func() *httptest.ResponseRecorder {
+			return s.do(http.MethodGet, "/api/admin/contacts", nil)
+		}, http.StatusUnauthorized, "unauthorized", "", ""},
+		{"form token", func() *httptest.ResponseRecorder {
+			return s.do(http.MethodPost, "/api/contact", map[string]string{"name": "n", "email": "a@b.co", "subject": "s", "message": "m", "form_token": "1.deadbeef"})
+		}, http.StatusBadRequest, "invalid_form_token", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.w()
+			expectStatus(t, w, tt.status)
+			e := decode[apiError](t, w)
+			if e.Code != tt.code || e.Error == "" || e.RequestID == "" {
+				t.Fatalf("envelope = %+v, want code %q with message and request_id", e, tt.code)
+			}
+			if tt.field != "" && (len(e.Details) != 1 || e.Details[0].Field != tt.field || e.Details[0].Rule != tt.rule || e.Details[0].Message == "") {
+				t.Errorf("details = %+v, want %s/%s", e.Details, tt.field, tt.rule)
+			}
+		})
+	}
+}
+
+func TestUniqueConflicts(t *testing.T) {
+	for _, backend := range repositoryBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			s := newTestServer(t)
+			backend.setup(s)
+
+			for _, kind := range []string{"blogs", "solutions"} {
+				first := s.admin(http.MethodPost, "/api/admin/"+kind, map[string]string{"title": "Dup", "path": "dup"})
+				expectStatus(t, first, http.StatusCreated)
+				other := s.admin(http.MethodPost, "/api/admin/"+kind, map[string]string{"title": "Other", "path": "other"})
+				expectStatus(t, other, http.StatusCreated)
+
+				w := s.admin(http.MethodPost, "/api/admin/"+kind, map[string]string{"title": "Dup again", "path": "dup"})
+				expectStatus(t, w, http.StatusConflict)
+				if strings.Contains(w.Body.String(), "UNIQUE") {
+					t.Errorf("conflict leaks SQL: %s", w.Body.String())
+				}
+				e := decode[apiError](t, w)
+				if e.Code != "conflict" || len(e.Details) != 1 || e.Details[0].Field != "path" || e.Details[0].Rule != "unique" {
+					t.Errorf("%s conflict = %+v", kind, e)
+				}
+
+				id := int(decode[map[string]any](t, other)["id"].(float64))
+				w = s.admin(http.MethodPut, fmt.Sprintf("/api/admin/%s/%d", kind, id), map[string]string{"title": "Other", "path": "dup"})
+				expectStatus(t, w, http.StatusConflict)
+			}
+		})
+	}
+}
+
+func TestErrorLocalization(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		acceptLanguage string
+		lang           string
+		message        string
+	}{
+		{"", "en", "Blog not found"},
+		{"zh-CN,zh;q=0.9,en;q=0.8", "zh", "博客不存在"},
+		{"fr-FR, en;q=0.5, zh;q=0.7", "zh", "博客不存在"},
+		{"de", "en", "Blog not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			w := s.do(http.MethodGet, "/api/blogs/missing", nil, "Accept-Language", tt.acceptLanguage)
+			expectStatus(t, w, http.StatusNotFound)
+			if got := w.Header().Get("Content-Language"); got != tt.lang {
+				t.Errorf("Content-Language = %q, want %q", got, tt.lang)
+			}
+			if e := decode[apiError](t, w); e.Error != tt.message {
+				t.Errorf("error = %q, want %q", e.Error, tt.message)
+			}
+		})
+	}
+
+	w := s.admin(http.MethodPost, "/api/admin/social-links", map[string]string{"platform": "x"})
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+	w = s.do(http.MethodPost, "/api/admin/social-links", map[string]string{"platform": "x"}, "Authorization", "Bearer "+s.token, "Accept-Language", "zh")
+	if e := decode[apiError](t, w); e.Error != "参数校验失败：url 不能为空" || e.Details[0].Message != "url 不能为空" {
+		t.Errorf("zh validation = %+v", e)
+	}
+}
@@ -0,0 +1,309 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/config"
+	"backend/logger"
+	"backend/middleware"
+	"backend/repository"
+)
+
+// Event types emitted by the backend.
+const (
+	EventBlogPublished          = "blog.published"
+	EventBlogUpdated            = "blog.updated"
+	EventBlogDeleted            = "blog.deleted"
+	EventSolutionPublished      = "solution.published"
+	EventSolutionUpdated        = "solution.updated"
+	EventSolutionDeleted        = "solution.deleted"
+	EventContactCreated         = "contact.created"
+	EventCommentCreated         = "comment.created"
+	EventCommentReplied         = "comment.replied"
+	EventNewsletterConfirmed    = "newsletter.confirmed"
+	EventNewsletterUnsubscribed = "newsletter.unsubscribed"
+	EventDBRestored             = "db.restored"
+)
+
+// Events lists every event type a subscription may name.
+var Events = []string{
+	EventBlogPublished,
+	EventBlogUpdated,
+	EventBlogDeleted,
+	EventSolutionPublished,
+	EventSolutionUpdated,
+	EventSolutionDeleted,
+	EventContactCreated,
+	EventCommentCreated,
+	EventCommentReplied,
+	EventNewsletterConfirmed,
+	EventNewsletterUnsubscribed,
+	EventDBRestored,
+}
+
+// ValidEvent reports whether name is a known event type or the "*" wildcard.
+func ValidEvent(name string) bool {
+	if name == "*" {
+		return true
+	}
+	for _, e := range Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	pollInterval = 5 * time.Second
+	batchSize    = 20
+	baseBackoff  = 10 * time.Second
+	maxBackoff   = time.Hour
+)
+
+var (
+	client = &http.Client{Timeout: 10 * time.Second}
+	wake   = make(chan struct{}, 1)
+
+	workerMu     sync.Mutex
+	workerCancel context.CancelFunc
+	workerDone   chan struct{}
+)
+
+// Payload is the JSON body posted to subscribers.
+type Payload struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Dispatch queues event for every active subscription in store that listens
+// to it.
+//
+// Delivery happens asynchronously in the worker; Dispatch is best-effort and
+// only logs failures so callers never fail a request because of webhooks.
+func Dispatch(ctx context.Context, store repository.WebhookRepository, event string, data interface{}) {
+	// Keep the request ID for logging but don't drop the event if the client
+	// disconnects after the change was committed.
+	ctx = context.WithoutCancel(ctx)
+	log := logger.FromContext(ctx)
+
+	body, err := json.Marshal(Payload{Event: event, Timestamp: time.Now().UTC(), Data: data})
+	if err != nil {
+		log.Error("webhook payload marshal failed", "event", event, "error", err)
+		return
+	}
+
+	queued, err := store.Enqueue(ctx, event, string(body))
+	if err != nil {
+		log.Error("webhook enqueue failed", "event", event, "error", err)
+		return
+	}
+	if queued > 0 {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" using secret.
+//
+// Subscribers verify the X-Webhook-Signature header ("sha256=<hex>") against
+// the X-Webhook-Timestamp header and reject old timestamps, so a captured
+// delivery cannot be replayed later.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret returns a random signing secret for a subscription created
+// without one.
+func NewSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func maxAttempts() int {
+	if n := config.Current.Webhook.MaxAttempts; n > 0 {
+		return n
+	}
+	return 1
+}
+
+// StartWorker launches the background goroutine that drains the delivery queue.
+func StartWorker() {
+	workerMu.Lock()
+	defer workerMu.Unlock()
+	if workerCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workerCancel = cancel
+	workerDone = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			processPending(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	}(workerDone)
+}
+
+// StopWorker stops the delivery worker and waits for the current batch.
+// Undelivered entries stay in the queue and are retried on next start.
+func StopWorker() {
+	workerMu.Lock()
+	cancel, done := workerCancel, workerDone
+	workerCancel, workerDone = nil, nil
+	workerMu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+type pendingDelivery struct {
+	id       int
+	event    string
+	payload  string
+	attempts int
+	url      string
+	secret   string
+}
+
+func processPending(ctx context.Context) {
+	batch := loadPending(ctx)
+
+	limit := maxAttempts()
+	for _, d := range batch {
+		if ctx.Err() != nil {
+			return
+		}
+		code, err := deliver(ctx, d)
+		if ctx.Err() != nil {
+			// Shutting down: leave the entry pending without counting the attempt.
+			return
+		}
+		recordAttempt(ctx, d, code, err, limit)
+	}
+}
+
+// loadPending reads due deliveries. DB access is wrapped in the DB read lock
+// so a restore never swaps the database underneath the worker; the lock is
+// not held during HTTP calls, and waiting for it ends when the worker stops.
+func loadPending(ctx context.Context) []pendingDelivery {
+	if !middleware.LockDBReadContext(ctx) {
+		return nil
+	}
+	defer middleware.UnlockDBRead()
+
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT d.id, d.event, d.payload, d.attempts, w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY d.id ASC
+		LIMIT ?
+	`, batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("webhook queue query failed", "error", err)
+		}
+		return nil
+	}
+	var batch []pendingDelivery
+	for rows.Next() {
+		var d pendingDelivery
+		if err := rows.Scan(&d.id, &d.event, &d.payload, &d.attempts, &d.url, &d.secret); err != nil {
+			continue
+		}
+		batch = append(batch, d)
+	}
+	rows.Close()
+	return batch
+}
+
+// recordAttempt stores the outcome of a delivery. If the worker stops while
+// a restore holds the database, the outcome is lost and the entry, still
+// pending, is sent again later.
+func recordAttempt(ctx context.Context, d pendingDelivery, code int, err error, limit int) {
+	if !middleware.LockDBReadContext(ctx) {
+		return
+	}
+	defer middleware.UnlockDBRead()
+
+	attempts := d.attempts + 1
+	if err == nil {
+		_, _ = config.DB.Exec("UPDATE webhook_deliveries SET status = 'success', attempts = ?, response_status = ?, last_error = '', updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			attempts, code, d.id)
+		return
+	}
+
+	status := "pending"
+	if attempts >= limit {
+		status = "failed"
+	}
+	backoff := baseBackoff << (attempts - 1)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	_, _ = config.DB.Exec("UPDATE webhook_deliveries SET status = ?, attempts = ?, response_status = ?, last_error = ?, next_attempt_at = datetime('now', ?), updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		status, attempts, code, err.Error(), fmt.Sprintf("+%d seconds", int(backoff.Seconds())), d.id)
+	slog.Warn("webhook delivery failed", "delivery_id", d.id, "event", d.event, "url", d.url, "attempt", attempts, "status", status, "error", err)
+}
+
+func deliver(ctx context.Context, d pendingDelivery) (int, error) {
+	body := []byte(d.payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "yan-webhook/1")
+	req.Header.Set("X-Webhook-Event", d.event)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(d.id))
+	timestamp := time.Now().Unix()
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(d.secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/repository"
+)
+
+func TestDispatch(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemory().Webhooks
+	hooks := map[string]*models.Webhook{
+		"listening": {URL: "https://example.com/a", Events: []string{EventBlogPublished}, Active: true},
+		"all":       {URL: "https://example.com/b", Events: []string{"*"}, Active: true},
+		"other":     {URL: "https://example.com/c", Events: []string{"db.restored"}, Active: true},
+		"inactive":  {URL: "https://example.com/d", Events: []string{EventBlogPublished}},
+	}
+	for _, h := range hooks {
+		if err := store.Create(ctx, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Dispatch(ctx, store, EventBlogPublished, map[string]int{"id": 7})
+
+	deliveries, err := store.ListDeliveries(ctx, repository.DeliveryFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[int]bool{}
+	for _, d := range deliveries {
+		got[d.WebhookID] = true
+		var p Payload
+		if err := json.Unmarshal([]byte(d.Payload), &p); err != nil || p.Event != EventBlogPublished || d.Status != "pending" {
+			t.Errorf("delivery = %+v, want a pending %s payload", d, EventBlogPublished)
+		}
+	}
+	if len(deliveries) != 2 || !got[hooks["listening"].ID] || !got[hooks["all"].ID] {
+		t.Errorf("deliveries for webhooks %v, want the listening and catch-all subscriptions only", got)
+	}
+}
+
+func TestDeliverSignsTimestampAndBody(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{r.Header.Clone(), body}
+	}))
+	defer srv.Close()
+
+	payload := `{"event":"blog.published"}`
+	if _, err := deliver(context.Background(), pendingDelivery{id: 7, event: EventBlogPublished, payload: payload, url: srv.URL, secret: "shh"}); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	r := <-got
+
+	ts, err := strconv.ParseInt(r.header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)).Abs() > time.Minute {
+		t.Fatalf("X-Webhook-Timestamp = %q, want the current unix time", r.header.Get("X-Webhook-Timestamp"))
+	}
+	if want := "sha256=" + Sign("shh", ts, []byte(payload)); r.header.Get("X-Webhook-Signature") != want {
+		t.Errorf("signature = %q, want %q", r.header.Get("X-Webhook-Signature"), want)
+	}
+	// The timestamp is part of the signed message.
+	if Sign("shh", ts, r.body) == Sign("shh", ts+1, r.body) {
+		t.Error("signature does not depend on the timestamp")
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, b := NewSecret(), NewSecret()
+	if len(a) != 64 || a == b {
+		t.Errorf("NewSecret() = %q, %q; want distinct 32-byte hex strings", a, b)
+	}
+}
+
+func TestStopWorkerDuringRestore(t *testing.T) {
+	// A restore holds the write lock for its whole duration.
+	middleware.LockDBWrite()
+	defer middleware.UnlockDBWrite()
+
+	StartWorker()
+	stopped := make(chan struct{})
+	go func() {
+		StopWorker()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StopWorker blocked while the database was locked")
+	}
+}
@@ -0,0 +1,153 @@
+'use client';
+
+import { useState, useEffect } from 'react';
+import axios from 'axios';
+import { ContentViews, DailyViews, ReferrerViews } from './types';
+import { getApiBase } from '../../lib/api';
+
+const ranges = [7, 30, 90];
+
+// Days are counted in UTC by the backend.
+const utcDay = (date: Date) => date.toISOString().slice(0, 10);
+
+export default function AnalyticsTab() {
+    const [days, setDays] = useState(30);
+    const [top, setTop] = useState<ContentViews[]>([]);
+    const [daily, setDaily] = useState<DailyViews[]>([]);
+    const [referrers, setReferrers] = useState<ReferrerViews[]>([]);
+
+    const fetchAnalytics = async (range: number) => {
+        try {
+            const baseUrl = getApiBase();
+            const to = new Date();
+            const from = new Date(to.getTime() - (range - 1) * 24 * 60 * 60 * 1000);
+            const query = `from=${utcDay(from)}&to=${utcDay(to)}`;
+            const headers = { Authorization: `Bearer ${localStorage.getItem('admin_token')}` };
+            const [topRes, dailyRes, refRes] = await Promise.all([
+                axios.get(`${baseUrl}/api/admin/analytics/top?${query}&limit=20`, { headers }),
+                axios.get(`${baseUrl}/api/admin/analytics/daily?${query}`, { headers }),
+                axios.get(`${baseUrl}/api/admin/analytics/referrers?${query}&limit=20`, { headers }),
+            ]);
+            setTop((topRes.data as ContentViews[]) || []);
+            setDaily((dailyRes.data as DailyViews[]) || []);
+            setReferrers((refRes.data as ReferrerViews[]) || []);
+        } catch (error) {
+            console.error('Failed to fetch analytics:', error);
+            setTop([]);
+            setDaily([]);
+            setReferrers([]);
+        }
+    };
+
+    useEffect(() => {
+        fetchAnalytics(days);
+    }, [days]);
+
+    const totalViews = daily.reduce((sum, d) => sum + d.views, 0);
+    const totalVisitors = daily.reduce((sum, d) => sum + d.visitors, 0);
+    const peak = Math.max(1, ...daily.map((d) => d.views));
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h2 className="text-2xl font-bold text-slate-900">Analytics</h2>
+                    <p className="text-gray-500 mt-1">Views of blog posts and solutions (no cookies, no personal data)</p>
+                </div>
+                <div className="flex space-x-2">
+                    {ranges.map((range) => (
+                        <button
+                            key={range}
+                            onClick={() => setDays(range)}
+                            className={`px-4 py-2 rounded-xl text-sm font-medium transition-colors ${days === range
+                                ? 'bg-sky-500 text-white'
+                                : 'bg-white text-gray-600 border border-gray-200 hover:bg-gray-50'
+                                }`}
+                        >
+                            {range} days
+                        </button>
+                    ))}
+                </div>
+            </div>
+
+            {/* Daily views */}
+            <div className="bg-white rounded-2xl border border-gray-100 shadow-sm p-6">
+                <div className="flex items-baseline space-x-6 mb-6">
+                    <div>
+                        <p className="text-3xl font-bold text-slate-900">{totalViews}</p>
+                        <p className="text-xs text-gray-500 uppercase tracking-wider">Views</p>
+                    </div>
+                    <div>
+                        <p className="text-3xl font-bold text-slate-900">{totalVisitors}</p>
+                        <p className="text-xs text-gray-500 uppercase tracking-wider">Daily visitors</p>
+                    </div>
+                </div>
+                <div className="flex items-end h-40 gap-px">
+                    {daily.map((d) => (
+                        <div
+                            key={d.day}
+                            title={`${d.day}: ${d.views} views, ${d.visitors} visitors`}
+                            className="flex-1 bg-sky-400 hover:bg-sky-600 rounded-t transition-colors"
+                            style={{ height: `${(d.views / peak) * 100}%`, minHeight: d.views > 0 ? 2 : 0 }}
+                        />
+                    ))}
+                </div>
+                {daily.length > 0 && (
+                    <div className="flex justify-between mt-2 text-xs text-gray-400">
+                        <span>{daily[0].day}</span>
+                        <span>{daily[daily.length - 1].day}</span>
+                    </div>
+                )}
+            </div>
+
+            <div className="grid grid-cols-1 lg:grid-cols-3 gap-6">
+                {/* Top content */}
+                <div className="lg:col-span-2 bg-white rounded-2xl border border-gray-100 shadow-sm p-6">
+                    <h3 className="text-sm font-semibold text-slate-900 mb-4 uppercase tracking-wider">Top Content</h3>
+                    {top.length === 0 ? (
+                        <p className="text-sm text-gray-500">No views in this period.</p>
+                    ) : (
+                        <table className="w-full text-sm">
+                            <thead>
+                                <tr className="text-left text-xs text-gray-500 uppercase">
+                                    <th className="pb-3 font-medium">Title</th>
+                                    <th className="pb-3 font-medium text-right">Views</th>
+                                    <th className="pb-3 font-medium text-right">Visitors</th>
+                                </tr>
+                            </thead>
+                            <tbody className="divide-y divide-gray-100">
+                                {top.map((item) => (
+                                    <tr key={`${item.kind}-${item.id}`}>
+                                        <td className="py-3">
+                                            <span className="mr-2 px-2 py-0.5 text-xs rounded-full bg-gray-100 text-gray-600">{item.kind}</span>
+                                            <span className="text-slate-900">{item.title || `Deleted #${item.id}`}</span>
+                                        </td>
+                                        <td className="py-3 text-right font-semibold text-slate-900">{item.views}</td>
+                                        <td className="py-3 text-right text-gray-600">{item.visitors}</td>
+                                    </tr>
+                                ))}
+                            </tbody>
+                        </table>
+                    )}
+                </div>
+
+                {/* Referrers */}
+                <div className="bg-white rounded-2xl border border-gray-100 shadow-sm p-6">
+                    <h3 className="text-sm font-semibold text-slate-900 mb-4 uppercase tracking-wider">Referrers</h3>
+                    {referrers.length === 0 ? (
+                        <p className="text-sm text-gray-500">No views in this period.</p>
+                    ) : (
+                        <ul className="divide-y divide-gray-100 text-sm">
+                            {referrers.map((ref) => (
+                                <li key={ref.referrer} className="flex justify-between py-3">
+                                    <span className="text-slate-900 truncate">{ref.referrer || 'Direct / internal'}</span>
+                                    <span className="font-semibold text-slate-900 ml-4">{ref.views}</span>
+                                </li>
+                            ))}
+                        </ul>
+                    )}
+                </div>
+            </div>
+        </div>
+    );
+}
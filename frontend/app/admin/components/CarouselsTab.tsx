@@ -5,6 +5,24 @@ import axios from "axios";
 import { Carousel } from "./types";
 import { getApiBase } from "../../lib/api";
 
+// <input type="datetime-local"> works in local time without a zone; the API
+// takes and returns RFC 3339.
+const toLocalInput = (iso?: string | null) => {
+  if (!iso) return "";
+  const d = new Date(iso);
+  return new Date(d.getTime() - d.getTimezoneOffset() * 60000).toISOString().slice(0, 16);
+};
+const fromLocalInput = (value: string) => (value ? new Date(value).toISOString() : null);
+
+const scheduleLabel = (item: Carousel) => {
+  const now = Date.now();
+  if (!item.active) return "Inactive";
+  if (item.starts_at && new Date(item.starts_at).getTime() > now) return `Starts ${new Date(item.starts_at).toLocaleString()}`;
+  if (item.ends_at && new Date(item.ends_at).getTime() <= now) return "Ended";
+  if (item.ends_at) return `Until ${new Date(item.ends_at).toLocaleString()}`;
+  return "";
+};
+
 export default function CarouselsTab() {
   const [carousels, setCarousels] = useState<Carousel[]>([]);
   const [editingCarousel, setEditingCarousel] = useState<Carousel | null>(null);
@@ -19,6 +37,14 @@ export default function CarouselsTab() {
     rotation: 0,
     image_width: 0,
     image_height: 0,
+    active: true,
+    starts_at: "",
+    ends_at: "",
+    locale: "" as Carousel["locale"],
+    cta_label: "",
+    cta_type: "" as Carousel["cta_type"],
+    cta_url: "",
+    cta_ref_id: 0,
   });
 
   useEffect(() => {
@@ -28,9 +54,9 @@ export default function CarouselsTab() {
   const fetchCarousels = async () => {
     try {
       const baseUrl = getApiBase();
-      const response = await axios.get(
-        `${baseUrl}/api/carousels`,
-      );
+      const response = await axios.get(`${baseUrl}/api/admin/carousels`, {
+        headers: { Authorization: `Bearer ${localStorage.getItem("admin_token")}` },
+      });
       setCarousels((response.data as Carousel[]) || []);
     } catch (error) {
       console.error("Failed to fetch carousels:", error);
@@ -49,6 +75,14 @@ export default function CarouselsTab() {
       rotation: 0,
       image_width: 0,
       image_height: 0,
+      active: true,
+      starts_at: "",
+      ends_at: "",
+      locale: "",
+      cta_label: "",
+      cta_type: "",
+      cta_url: "",
+      cta_ref_id: 0,
     });
     setIsCreatingCarousel(true);
     setEditingCarousel(null);
@@ -67,6 +101,14 @@ export default function CarouselsTab() {
       rotation: item.rotation ?? 0,
       image_width: item.image_width ?? 0,
       image_height: item.image_height ?? 0,
+      active: item.active,
+      starts_at: toLocalInput(item.starts_at),
+      ends_at: toLocalInput(item.ends_at),
+      locale: item.locale ?? "",
+      cta_label: item.cta_label ?? "",
+      cta_type: item.cta_type ?? "",
+      cta_url: item.cta_url ?? "",
+      cta_ref_id: item.cta_ref_id ?? 0,
     });
   };
 
@@ -82,6 +124,14 @@ export default function CarouselsTab() {
       rotation: carousel.rotation ?? 0,
       image_width: carousel.image_width ?? 0,
       image_height: carousel.image_height ?? 0,
+      active: carousel.active,
+      starts_at: toLocalInput(carousel.starts_at),
+      ends_at: toLocalInput(carousel.ends_at),
+      locale: carousel.locale ?? "",
+      cta_label: carousel.cta_label ?? "",
+      cta_type: carousel.cta_type ?? "",
+      cta_url: carousel.cta_url ?? "",
+      cta_ref_id: carousel.cta_ref_id ?? 0,
     });
     setIsCreatingCarousel(false);
   };
@@ -101,6 +151,9 @@ export default function CarouselsTab() {
         sort_order: Number(carouselFormData.sort_order) || 0,
         image_width: Number(carouselFormData.image_width) || 0,
         image_height: Number(carouselFormData.image_height) || 0,
+        starts_at: fromLocalInput(carouselFormData.starts_at),
+        ends_at: fromLocalInput(carouselFormData.ends_at),
+        cta_ref_id: Number(carouselFormData.cta_ref_id) || 0,
       };
 
       if (editingCarousel) {
@@ -122,7 +175,7 @@ export default function CarouselsTab() {
       fetchCarousels();
     } catch (error: any) {
       console.error("Failed to save carousel:", error);
-      alert("Save failed: " + (error.response?.data?.error || error.message));
+      alert("Save failed: " + (error.response?.data?.message || error.message));
     }
   };
 
@@ -371,6 +424,164 @@ export default function CarouselsTab() {
                 </div>
               </div>
 
+              <h3 className="text-sm font-semibold text-slate-900 mt-6 pt-6 border-t border-gray-100 mb-4 uppercase tracking-wider">
+                Schedule &amp; Audience
+              </h3>
+
+              <div className="space-y-4">
+                <label className="flex items-center gap-2 text-sm font-medium text-slate-700">
+                  <input
+                    type="checkbox"
+                    checked={carouselFormData.active}
+                    onChange={(e) =>
+                      setCarouselFormData({
+                        ...carouselFormData,
+                        active: e.target.checked,
+                      })
+                    }
+                  />
+                  Active
+                </label>
+
+                <div>
+                  <label className="block text-sm font-medium text-slate-700 mb-2">
+                    Show From
+                  </label>
+                  <input
+                    type="datetime-local"
+                    value={carouselFormData.starts_at}
+                    onChange={(e) =>
+                      setCarouselFormData({
+                        ...carouselFormData,
+                        starts_at: e.target.value,
+                      })
+                    }
+                    className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                  />
+                </div>
+
+                <div>
+                  <label className="block text-sm font-medium text-slate-700 mb-2">
+                    Show Until
+                  </label>
+                  <input
+                    type="datetime-local"
+                    value={carouselFormData.ends_at}
+                    onChange={(e) =>
+                      setCarouselFormData({
+                        ...carouselFormData,
+                        ends_at: e.target.value,
+                      })
+                    }
+                    className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                  />
+                  <p className="mt-1 text-xs text-gray-400">Leave empty to show right away / indefinitely</p>
+                </div>
+
+                <div>
+                  <label className="block text-sm font-medium text-slate-700 mb-2">
+                    Language
+                  </label>
+                  <select
+                    value={carouselFormData.locale}
+                    onChange={(e) =>
+                      setCarouselFormData({
+                        ...carouselFormData,
+                        locale: e.target.value as Carousel["locale"],
+                      })
+                    }
+                    className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                  >
+                    <option value="">All visitors</option>
+                    <option value="en">English visitors only</option>
+                    <option value="zh">Chinese visitors only</option>
+                  </select>
+                </div>
+
+                <div>
+                  <label className="block text-sm font-medium text-slate-700 mb-2">
+                    Button
+                  </label>
+                  <select
+                    value={carouselFormData.cta_type}
+                    onChange={(e) =>
+                      setCarouselFormData({
+                        ...carouselFormData,
+                        cta_type: e.target.value as Carousel["cta_type"],
+                      })
+                    }
+                    className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                  >
+                    <option value="">No button</option>
+                    <option value="url">Link (URL or site path)</option>
+                    <option value="blog">Blog post</option>
+                    <option value="solution">Solution</option>
+                  </select>
+                </div>
+
+                {carouselFormData.cta_type && (
+                  <>
+                    <div>
+                      <label className="block text-sm font-medium text-slate-700 mb-2">
+                        Button Label
+                      </label>
+                      <input
+                        type="text"
+                        value={carouselFormData.cta_label}
+                        maxLength={100}
+                        onChange={(e) =>
+                          setCarouselFormData({
+                            ...carouselFormData,
+                            cta_label: e.target.value,
+                          })
+                        }
+                        className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                        placeholder="Learn more"
+                      />
+                    </div>
+
+                    {carouselFormData.cta_type === "url" ? (
+                      <div>
+                        <label className="block text-sm font-medium text-slate-700 mb-2">
+                          Button Link
+                        </label>
+                        <input
+                          type="text"
+                          value={carouselFormData.cta_url}
+                          onChange={(e) =>
+                            setCarouselFormData({
+                              ...carouselFormData,
+                              cta_url: e.target.value,
+                            })
+                          }
+                          className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                          placeholder="https://example.com or /contact"
+                        />
+                      </div>
+                    ) : (
+                      <div>
+                        <label className="block text-sm font-medium text-slate-700 mb-2">
+                          {carouselFormData.cta_type === "blog" ? "Blog ID" : "Solution ID"}
+                        </label>
+                        <input
+                          type="number"
+                          min={1}
+                          value={carouselFormData.cta_ref_id || ""}
+                          onChange={(e) =>
+                            setCarouselFormData({
+                              ...carouselFormData,
+                              cta_ref_id: Number(e.target.value) || 0,
+                            })
+                          }
+                          className="w-full px-4 py-3 border border-gray-200 rounded-xl focus:outline-none focus:ring-2 focus:ring-sky-500 text-gray-900"
+                        />
+                        <p className="mt-1 text-xs text-gray-400">The link follows the current path, even after it is renamed</p>
+                      </div>
+                    )}
+                  </>
+                )}
+              </div>
+
               <div className="mt-6 pt-6 border-t border-gray-100">
                 <button
                   onClick={handleSaveCarousel}
@@ -487,8 +698,18 @@ export default function CarouselsTab() {
                   Order: {item.sort_order}
                 </div>
                 <div className="absolute top-2 left-2 bg-sky-500/90 backdrop-blur-sm text-white text-xs px-2 py-1 rounded-lg capitalize">
-                  {item.position}
+                  {item.position}{item.locale && ` · ${item.locale}`}
                 </div>
+                {scheduleLabel(item) && (
+                  <div className="absolute bottom-2 left-2 bg-amber-500/90 backdrop-blur-sm text-white text-xs px-2 py-1 rounded-lg">
+                    {scheduleLabel(item)}
+                  </div>
+                )}
+                {item.cta_type && !item.cta_href && (
+                  <div className="absolute bottom-2 right-2 bg-red-500/90 backdrop-blur-sm text-white text-xs px-2 py-1 rounded-lg">
+                    Button target deleted
+                  </div>
+                )}
               </div>
 
               <div className="p-5 flex-1 flex flex-col">
@@ -0,0 +1,126 @@
+'use client';
+
+import { useState, useEffect, useCallback } from 'react';
+import axios from 'axios';
+import { Comment } from './types';
+import { getApiBase } from '../../lib/api';
+
+const filters: { id: string; label: string }[] = [
+    { id: 'pending', label: 'Pending' },
+    { id: 'approved', label: 'Approved' },
+    { id: 'spam', label: 'Spam' },
+    { id: '', label: 'All' },
+];
+
+const statusStyles: Record<Comment['status'], string> = {
+    pending: 'bg-amber-50 text-amber-700',
+    approved: 'bg-emerald-50 text-emerald-700',
+    spam: 'bg-red-50 text-red-700',
+};
+
+export default function CommentsTab() {
+    const [comments, setComments] = useState<Comment[]>([]);
+    const [status, setStatus] = useState('pending');
+
+    const authHeaders = () => ({ Authorization: `Bearer ${localStorage.getItem('admin_token')}` });
+
+    const fetchComments = useCallback(async () => {
+        try {
+            const query = status ? `?status=${status}` : '';
+            const response = await axios.get(`${getApiBase()}/api/admin/comments${query}`, { headers: authHeaders() });
+            setComments((response.data as Comment[]) || []);
+        } catch (error) {
+            console.error('Failed to fetch comments:', error);
+            setComments([]);
+        }
+    }, [status]);
+
+    useEffect(() => {
+        fetchComments();
+    }, [fetchComments]);
+
+    const handleStatus = async (id: number, next: Comment['status']) => {
+        try {
+            await axios.put(`${getApiBase()}/api/admin/comments/${id}/status`, { status: next }, { headers: authHeaders() });
+            fetchComments();
+        } catch (error: any) {
+            console.error('Failed to update comment:', error);
+            alert('Update failed: ' + (error.response?.data?.message || error.message));
+        }
+    };
+
+    const handleDelete = async (id: number) => {
+        if (!confirm('Delete this comment and all replies to it?')) return;
+        try {
+            await axios.delete(`${getApiBase()}/api/admin/comments/${id}`, { headers: authHeaders() });
+            fetchComments();
+        } catch (error: any) {
+            console.error('Failed to delete comment:', error);
+            alert('Delete failed: ' + (error.response?.data?.message || error.message));
+        }
+    };
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h2 className="text-2xl font-bold text-slate-900">Comments</h2>
+                    <p className="text-gray-500 mt-1">Moderate comments on blog posts</p>
+                </div>
+                <div className="flex gap-2">
+                    {filters.map((f) => (
+                        <button
+                            key={f.id}
+                            onClick={() => setStatus(f.id)}
+                            className={`px-4 py-2 rounded-xl text-sm font-medium transition-colors ${status === f.id ? 'bg-sky-500 text-white' : 'bg-white text-gray-600 border border-gray-200 hover:bg-gray-50'}`}
+                        >
+                            {f.label}
+                        </button>
+                    ))}
+                </div>
+            </div>
+
+            {comments.length === 0 ? (
+                <div className="text-center py-24 bg-white rounded-3xl border border-gray-100 shadow-sm">
+                    <h3 className="text-xl font-semibold text-slate-900">No comments</h3>
+                    <p className="text-gray-500 mt-2">Nothing to review here.</p>
+                </div>
+            ) : (
+                <div className="space-y-4">
+                    {comments.map((comment) => (
+                        <div key={comment.id} className="bg-white rounded-2xl border border-gray-100 shadow-sm p-6">
+                            <div className="flex justify-between items-start gap-4 mb-3">
+                                <div>
+                                    <p className="font-semibold text-slate-900">
+                                        {comment.author} <span className="font-normal text-sm text-gray-500">&lt;{comment.email}&gt;</span>
+                                    </p>
+                                    <p className="text-xs text-gray-500 mt-1">
+                                        On “{comment.blog_title || `#${comment.blog_id}`}”
+                                        {comment.parent_id > 0 && ` · reply to #${comment.parent_id}`}
+                                        {' · '}{new Date(comment.created_at).toLocaleString()}
+                                    </p>
+                                </div>
+                                <span className={`px-2 py-1 rounded-lg text-xs font-medium ${statusStyles[comment.status]}`}>
+                                    {comment.status}{comment.spam_reason && ` (${comment.spam_reason})`}
+                                </span>
+                            </div>
+                            <p className="text-gray-700 whitespace-pre-wrap mb-4">{comment.content}</p>
+                            <div className="flex gap-2">
+                                {comment.status !== 'approved' && (
+                                    <button onClick={() => handleStatus(comment.id, 'approved')} className="px-3 py-1.5 text-sm rounded-lg bg-emerald-500 text-white hover:bg-emerald-600">Approve</button>
+                                )}
+                                {comment.status !== 'pending' && (
+                                    <button onClick={() => handleStatus(comment.id, 'pending')} className="px-3 py-1.5 text-sm rounded-lg bg-gray-100 text-gray-700 hover:bg-gray-200">Unpublish</button>
+                                )}
+                                {comment.status !== 'spam' && (
+                                    <button onClick={() => handleStatus(comment.id, 'spam')} className="px-3 py-1.5 text-sm rounded-lg bg-amber-100 text-amber-800 hover:bg-amber-200">Mark as spam</button>
+                                )}
+                                <button onClick={() => handleDelete(comment.id)} className="px-3 py-1.5 text-sm rounded-lg text-red-600 hover:bg-red-50">Delete</button>
+                            </div>
+                        </div>
+                    ))}
+                </div>
+            )}
+        </div>
+    );
+}
@@ -0,0 +1,204 @@
+'use client';
+
+import { useState, useEffect, useCallback } from 'react';
+import axios from 'axios';
+import { Subscriber, Suppression } from './types';
+import { getApiBase } from '../../lib/api';
+
+const filters: { id: string; label: string }[] = [
+    { id: 'confirmed', label: 'Confirmed' },
+    { id: 'pending', label: 'Pending' },
+    { id: 'unsubscribed', label: 'Unsubscribed' },
+    { id: '', label: 'All' },
+];
+
+const statusStyles: Record<Subscriber['status'], string> = {
+    pending: 'bg-amber-50 text-amber-700',
+    confirmed: 'bg-emerald-50 text-emerald-700',
+    unsubscribed: 'bg-gray-100 text-gray-600',
+};
+
+export default function NewsletterTab() {
+    const [subscribers, setSubscribers] = useState<Subscriber[]>([]);
+    const [suppressions, setSuppressions] = useState<Suppression[]>([]);
+    const [status, setStatus] = useState('confirmed');
+    const [form, setForm] = useState({ email: '', reason: '' });
+
+    const authHeaders = () => ({ Authorization: `Bearer ${localStorage.getItem('admin_token')}` });
+
+    const fetchSubscribers = useCallback(async () => {
+        try {
+            const query = status ? `?status=${status}` : '';
+            const response = await axios.get(`${getApiBase()}/api/admin/newsletter/subscribers${query}`, { headers: authHeaders() });
+            setSubscribers((response.data as Subscriber[]) || []);
+        } catch (error) {
+            console.error('Failed to fetch subscribers:', error);
+            setSubscribers([]);
+        }
+    }, [status]);
+
+    const fetchSuppressions = useCallback(async () => {
+        try {
+            const response = await axios.get(`${getApiBase()}/api/admin/newsletter/suppressions`, { headers: authHeaders() });
+            setSuppressions((response.data as Suppression[]) || []);
+        } catch (error) {
+            console.error('Failed to fetch suppressions:', error);
+            setSuppressions([]);
+        }
+    }, []);
+
+    useEffect(() => {
+        fetchSubscribers();
+    }, [fetchSubscribers]);
+
+    useEffect(() => {
+        fetchSuppressions();
+    }, [fetchSuppressions]);
+
+    // The export needs the auth header, so it is downloaded as a blob rather than linked.
+    const handleExport = async () => {
+        try {
+            const query = status ? `?status=${status}` : '';
+            const response = await axios.get(`${getApiBase()}/api/admin/newsletter/subscribers/export${query}`, {
+                headers: authHeaders(),
+                responseType: 'blob',
+            });
+            const disposition: string = response.headers['content-disposition'] || '';
+            const filename = /filename="([^"]+)"/.exec(disposition)?.[1] || 'newsletter-subscribers.csv';
+            const url = URL.createObjectURL(response.data as Blob);
+            const a = document.createElement('a');
+            a.href = url;
+            a.download = filename;
+            a.click();
+            URL.revokeObjectURL(url);
+        } catch (error: any) {
+            console.error('Failed to export subscribers:', error);
+            alert('Export failed: ' + error.message);
+        }
+    };
+
+    const handleSuppress = async (e: React.FormEvent) => {
+        e.preventDefault();
+        try {
+            await axios.post(`${getApiBase()}/api/admin/newsletter/suppressions`, form, { headers: authHeaders() });
+            setForm({ email: '', reason: '' });
+            fetchSuppressions();
+            fetchSubscribers();
+        } catch (error: any) {
+            console.error('Failed to add suppression:', error);
+            alert('Add failed: ' + (error.response?.data?.message || error.message));
+        }
+    };
+
+    const handleUnsuppress = async (email: string) => {
+        if (!confirm(`Allow ${email} to subscribe again? They will still have to confirm.`)) return;
+        try {
+            await axios.delete(`${getApiBase()}/api/admin/newsletter/suppressions/${encodeURIComponent(email)}`, { headers: authHeaders() });
+            fetchSuppressions();
+        } catch (error: any) {
+            console.error('Failed to remove suppression:', error);
+            alert('Remove failed: ' + (error.response?.data?.message || error.message));
+        }
+    };
+
+    const formatDate = (value?: string | null) => (value ? new Date(value).toLocaleString() : '—');
+
+    return (
+        <div className="space-y-6">
+            <div className="flex justify-between items-center">
+                <div>
+                    <h2 className="text-2xl font-bold text-slate-900">Newsletter</h2>
+                    <p className="text-gray-500 mt-1">Subscribers and addresses that must not be mailed</p>
+                </div>
+                <div className="flex gap-2">
+                    {filters.map((f) => (
+                        <button
+                            key={f.id}
+                            onClick={() => setStatus(f.id)}
+                            className={`px-4 py-2 rounded-xl text-sm font-medium transition-colors ${status === f.id ? 'bg-sky-500 text-white' : 'bg-white text-gray-600 border border-gray-200 hover:bg-gray-50'}`}
+                        >
+                            {f.label}
+                        </button>
+                    ))}
+                    <button onClick={handleExport} className="px-4 py-2 rounded-xl text-sm font-medium bg-slate-900 text-white hover:bg-slate-800">
+                        Export CSV
+                    </button>
+                </div>
+            </div>
+
+            {subscribers.length === 0 ? (
+                <div className="text-center py-24 bg-white rounded-3xl border border-gray-100 shadow-sm">
+                    <h3 className="text-xl font-semibold text-slate-900">No subscribers</h3>
+                    <p className="text-gray-500 mt-2">Sign-ups from the site footer will appear here.</p>
+                </div>
+            ) : (
+                <div className="bg-white rounded-2xl border border-gray-100 shadow-sm overflow-hidden">
+                    <table className="w-full text-sm">
+                        <thead className="bg-gray-50 text-left text-gray-500">
+                            <tr>
+                                <th className="px-6 py-3 font-medium">Email</th>
+                                <th className="px-6 py-3 font-medium">Status</th>
+                                <th className="px-6 py-3 font-medium">Language</th>
+                                <th className="px-6 py-3 font-medium">Subscribed</th>
+                                <th className="px-6 py-3 font-medium">Confirmed</th>
+                            </tr>
+                        </thead>
+                        <tbody className="divide-y divide-gray-100">
+                            {subscribers.map((s) => (
+                                <tr key={s.id}>
+                                    <td className="px-6 py-3 text-slate-900">{s.email}</td>
+                                    <td className="px-6 py-3">
+                                        <span className={`px-2 py-1 rounded-lg text-xs font-medium ${statusStyles[s.status]}`}>{s.status}</span>
+                                    </td>
+                                    <td className="px-6 py-3 text-gray-500">{s.locale}</td>
+                                    <td className="px-6 py-3 text-gray-500">{formatDate(s.created_at)}</td>
+                                    <td className="px-6 py-3 text-gray-500">{formatDate(s.confirmed_at)}</td>
+                                </tr>
+                            ))}
+                        </tbody>
+                    </table>
+                </div>
+            )}
+
+            <div className="bg-white rounded-2xl border border-gray-100 shadow-sm p-6 space-y-4">
+                <div>
+                    <h3 className="text-lg font-semibold text-slate-900">Suppression list</h3>
+                    <p className="text-gray-500 text-sm mt-1">Unsubscribed, bounced or complaining addresses. They are never mailed.</p>
+                </div>
+                <form onSubmit={handleSuppress} className="flex gap-2">
+                    <input
+                        type="email"
+                        required
+                        placeholder="Email"
+                        value={form.email}
+                        onChange={(e) => setForm({ ...form, email: e.target.value })}
+                        className="flex-1 px-4 py-2 border border-gray-200 rounded-xl text-sm focus:outline-none focus:ring-2 focus:ring-sky-500"
+                    />
+                    <input
+                        placeholder="Reason (e.g. bounce)"
+                        maxLength={200}
+                        value={form.reason}
+                        onChange={(e) => setForm({ ...form, reason: e.target.value })}
+                        className="flex-1 px-4 py-2 border border-gray-200 rounded-xl text-sm focus:outline-none focus:ring-2 focus:ring-sky-500"
+                    />
+                    <button type="submit" className="px-4 py-2 rounded-xl text-sm font-medium bg-sky-500 text-white hover:bg-sky-600">Suppress</button>
+                </form>
+                {suppressions.length === 0 ? (
+                    <p className="text-gray-500 text-sm">No suppressed addresses.</p>
+                ) : (
+                    <ul className="divide-y divide-gray-100">
+                        {suppressions.map((s) => (
+                            <li key={s.email} className="flex justify-between items-center py-3">
+                                <div>
+                                    <p className="text-slate-900">{s.email}</p>
+                                    <p className="text-xs text-gray-500">{s.reason} · {formatDate(s.created_at)}</p>
+                                </div>
+                                <button onClick={() => handleUnsuppress(s.email)} className="px-3 py-1.5 text-sm rounded-lg text-red-600 hover:bg-red-50">Remove</button>
+                            </li>
+                        ))}
+                    </ul>
+                )}
+            </div>
+        </div>
+    );
+}
@@ -0,0 +1,36 @@
+import axios from 'axios';
+import { getApiBase } from '../../lib/api';
+
+// 把 index 处的条目上移（delta = -1）或下移（delta = 1），
+// 再按新的顺序从 0 开始重新编号，一次请求提交全部位置（后端在同一事务中更新）
+export async function moveItem(
+    kind: 'blogs' | 'solutions',
+    items: { id: number }[],
+    index: number,
+    delta: -1 | 1
+): Promise<boolean> {
+    const target = index + delta;
+    if (target < 0 || target >= items.length) return false;
+
+    const token = localStorage.getItem('admin_token');
+    if (!token) {
+        alert('Authentication token not found. Please log in again.');
+        return false;
+    }
+
+    const ordered = [...items];
+    [ordered[index], ordered[target]] = [ordered[target], ordered[index]];
+
+    try {
+        await axios.put(
+            `${getApiBase()}/api/admin/${kind}/order`,
+            { items: ordered.map((item, i) => ({ id: item.id, sort_order: i })) },
+            { headers: { Authorization: `Bearer ${token}` } }
+        );
+        return true;
+    } catch (error: any) {
+        console.error('Failed to reorder:', error);
+        alert('Reorder failed: ' + (error.response?.data?.error || error.message));
+        return false;
+    }
+}
@@ -7,10 +7,21 @@ export interface Blog {
   meta_title?: string;
   meta_description?: string;
   meta_keywords?: string;
+  category_ids?: number[];
+  comment_count?: number;
+  featured?: boolean;
+  pinned?: boolean;
+  sort_order?: number;
   created_at: string;
   updated_at: string;
 }
 
+export interface Category {
+  id: number;
+  name: string;
+  slug: string;
+}
+
 export interface Solution {
   id: number;
   title: string;
@@ -20,6 +31,9 @@ export interface Solution {
   meta_title?: string;
   meta_description?: string;
   meta_keywords?: string;
+  featured?: boolean;
+  pinned?: boolean;
+  sort_order?: number;
   created_at: string;
   updated_at: string;
 }
@@ -33,6 +47,20 @@ export interface Contact {
   created_at: string;
 }
 
+export interface Comment {
+  id: number;
+  blog_id: number;
+  blog_title?: string;
+  parent_id: number;
+  author: string;
+  email: string;
+  content: string;
+  status: 'pending' | 'approved' | 'spam';
+  spam_reason?: string;
+  notify_replies?: boolean;
+  created_at: string;
+}
+
 export interface Carousel {
   id: number;
   title: string;
@@ -44,6 +72,15 @@ export interface Carousel {
   rotation: number;
   image_width?: number;
   image_height?: number;
+  active: boolean;
+  starts_at?: string | null;
+  ends_at?: string | null;
+  locale: "" | "en" | "zh";
+  cta_label: string;
+  cta_type: "" | "url" | "blog" | "solution";
+  cta_url: string;
+  cta_ref_id: number;
+  cta_href: string; // resolved by the API; empty when the reference was deleted
   created_at: string;
   updated_at: string;
 }
@@ -56,3 +93,41 @@ export interface SocialLink {
   created_at?: string;
   updated_at?: string;
 }
+
+export interface ContentViews {
+  kind: "blog" | "solution";
+  id: number;
+  title: string;
+  path: string;
+  views: number;
+  visitors: number;
+}
+
+export interface DailyViews {
+  day: string;
+  views: number;
+  visitors: number;
+}
+
+export interface ReferrerViews {
+  referrer: string;
+  views: number;
+  visitors: number;
+}
+
+export interface Subscriber {
+  id: number;
+  email: string;
+  status: "pending" | "confirmed" | "unsubscribed";
+  locale: string;
+  confirmed_at?: string | null;
+  unsubscribed_at?: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface Suppression {
+  email: string;
+  reason: string;
+  created_at: string;
+}
@@ -2,9 +2,18 @@ import Link from 'next/link';
 import ReactMarkdown from 'react-markdown';
 import { Metadata } from 'next';
 import { getApiBase } from '../../lib/api';
+import ViewTracker from '../../components/ViewTracker';
+import Comments from '../../components/Comments';
 
 export const revalidate = 300;
 
+interface RelatedLink {
+  id: number;
+  title: string;
+  path: string;
+  summary?: string;
+}
+
 interface Blog {
   id: number;
   title: string;
@@ -16,8 +25,15 @@ interface Blog {
   meta_keywords?: string;
   created_at: string;
   updated_at: string;
+  related?: {
+    items: RelatedLink[];
+    previous?: RelatedLink;
+    next?: RelatedLink;
+  };
 }
 
+const linkTo = (item: RelatedLink) => `/blog/${item.path || item.id}`;
+
 async function getBlog(slug: string): Promise<Blog | null> {
   const baseUrl = getApiBase();
 
@@ -32,7 +48,7 @@ async function getBlog(slug: string): Promise<Blog | null> {
 
   try {
     // First try to fetch by path
-    const pathUrl = `${baseUrl}/api/blogs/by-path/${slug}`;
+    const pathUrl = `${baseUrl}/api/blogs/by-path/${slug}?include=related`;
     const blog = await fetchBlog(pathUrl);
     if (blog) return blog;
   } catch (pathError: any) {
@@ -41,7 +57,7 @@ async function getBlog(slug: string): Promise<Blog | null> {
 
   try {
     // If path fails, try ID (for backward compatibility)
-    const idUrl = `${baseUrl}/api/blogs/${slug}`;
+    const idUrl = `${baseUrl}/api/blogs/${slug}?include=related`;
     const blog = await fetchBlog(idUrl);
     if (blog) return blog;
   } catch (idError: any) {
@@ -106,6 +122,7 @@ export default async function BlogDetail({ params }: { params: Promise<{ slug: s
 
   return (
     <div className="min-h-screen bg-[var(--background)] text-[var(--foreground)]">
+      <ViewTracker kind="blog" id={blog.id} />
       {/* Hero Section */}
       <section className="relative py-32 overflow-hidden bg-gradient-to-br from-[#0a192f] via-[#10243c] to-[#0a192f]">
         {/* Background Pattern */}
@@ -164,6 +181,41 @@ export default async function BlogDetail({ params }: { params: Promise<{ slug: s
             </div>
           </div>
 
+          {/* Previous / Next */}
+          {(blog.related?.previous || blog.related?.next) && (
+            <div className="mt-12 grid gap-4 md:grid-cols-2">
+              {blog.related?.previous ? (
+                <Link href={linkTo(blog.related.previous)} className="block p-6 rounded-2xl bg-[#1b2a4a] border border-[rgba(0,188,212,0.25)] hover:border-sky-400 transition-colors">
+                  <span className="block text-xs uppercase tracking-wider text-gray-400 mb-2">Previous</span>
+                  <span className="text-white font-semibold">{blog.related.previous.title}</span>
+                </Link>
+              ) : <div />}
+              {blog.related?.next && (
+                <Link href={linkTo(blog.related.next)} className="block p-6 rounded-2xl bg-[#1b2a4a] border border-[rgba(0,188,212,0.25)] hover:border-sky-400 transition-colors md:text-right">
+                  <span className="block text-xs uppercase tracking-wider text-gray-400 mb-2">Next</span>
+                  <span className="text-white font-semibold">{blog.related.next.title}</span>
+                </Link>
+              )}
+            </div>
+          )}
+
+          {/* Related Articles */}
+          {blog.related && blog.related.items.length > 0 && (
+            <div className="mt-12">
+              <h2 className="text-2xl font-bold text-white mb-6">Related Articles</h2>
+              <div className="grid gap-6 md:grid-cols-3">
+                {blog.related.items.map((item) => (
+                  <Link key={item.id} href={linkTo(item)} className="block p-6 rounded-2xl bg-[#1b2a4a] border border-[rgba(0,188,212,0.25)] hover:border-sky-400 transition-colors">
+                    <h3 className="text-white font-semibold mb-2">{item.title}</h3>
+                    {item.summary && <p className="text-sm text-gray-400 line-clamp-3">{item.summary}</p>}
+                  </Link>
+                ))}
+              </div>
+            </div>
+          )}
+
+          <Comments blogId={blog.id} />
+
           {/* Back Button */}
           <div className="mt-12 text-center">
             <Link
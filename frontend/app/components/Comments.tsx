@@ -0,0 +1,162 @@
+'use client';
+
+import { useCallback, useEffect, useState } from 'react';
+import axios from 'axios';
+import { getApiBase } from '../lib/api';
+
+interface Comment {
+  id: number;
+  parent_id: number;
+  author: string;
+  content: string;
+  created_at: string;
+  replies?: Comment[];
+}
+
+// Must match COMMENTS_MAX_DEPTH on the backend (default 3).
+const MAX_DEPTH = 3;
+
+const emptyForm = { author: '', email: '', content: '', notify_replies: false, website: '' };
+
+function CommentForm({ blogId, parentId, onDone }: { blogId: number; parentId: number; onDone: () => void }) {
+  const [form, setForm] = useState(emptyForm);
+  const [token, setToken] = useState('');
+  const [status, setStatus] = useState<'idle' | 'sending' | 'sent' | 'error'>('idle');
+  const [error, setError] = useState('');
+
+  const fetchToken = useCallback(async () => {
+    try {
+      const res = await axios.get(`${getApiBase()}/api/contact/token`);
+      setToken(res.data.token);
+    } catch {
+      setToken('');
+    }
+  }, []);
+
+  useEffect(() => {
+    fetchToken();
+  }, [fetchToken]);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setStatus('sending');
+    setError('');
+    try {
+      await axios.post(`${getApiBase()}/api/blogs/${blogId}/comments`, {
+        ...form,
+        parent_id: parentId,
+        form_token: token,
+      });
+      setForm(emptyForm);
+      setStatus('sent');
+      onDone();
+    } catch (err: any) {
+      setStatus('error');
+      setError(err.response?.data?.message || 'Failed to post comment. Please try again.');
+    } finally {
+      fetchToken();
+    }
+  };
+
+  const inputClass = 'w-full px-4 py-2 rounded-xl bg-[#0d1c34] border border-[rgba(0,188,212,0.25)] text-white placeholder-gray-500 focus:outline-none focus:border-sky-400';
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-3">
+      <div className="grid gap-3 md:grid-cols-2">
+        <input className={inputClass} placeholder="Name" required maxLength={100}
+          value={form.author} onChange={(e) => setForm({ ...form, author: e.target.value })} />
+        <input className={inputClass} type="email" placeholder="Email (not published)" required maxLength={254}
+          value={form.email} onChange={(e) => setForm({ ...form, email: e.target.value })} />
+      </div>
+      <textarea className={inputClass} rows={4} placeholder="Your comment" required maxLength={5000}
+        value={form.content} onChange={(e) => setForm({ ...form, content: e.target.value })} />
+      {/* Honeypot: hidden from people, left empty by them */}
+      <input type="text" name="website" tabIndex={-1} autoComplete="off" className="hidden" aria-hidden="true"
+        value={form.website} onChange={(e) => setForm({ ...form, website: e.target.value })} />
+      <div className="flex flex-wrap items-center justify-between gap-3">
+        <label className="flex items-center gap-2 text-sm text-gray-400">
+          <input type="checkbox" checked={form.notify_replies}
+            onChange={(e) => setForm({ ...form, notify_replies: e.target.checked })} />
+          Notify me of replies
+        </label>
+        <button type="submit" disabled={status === 'sending'}
+          className="px-6 py-2 bg-gradient-to-r from-sky-500 to-cyan-500 text-white rounded-xl disabled:opacity-50">
+          {status === 'sending' ? 'Posting...' : 'Post Comment'}
+        </button>
+      </div>
+      {status === 'sent' && <p className="text-sm text-emerald-400">Thanks! Your comment will appear once it has been approved.</p>}
+      {status === 'error' && <p className="text-sm text-red-400">{error}</p>}
+    </form>
+  );
+}
+
+function CommentItem({ comment, blogId, depth, onPosted }: { comment: Comment; blogId: number; depth: number; onPosted: () => void }) {
+  const [replying, setReplying] = useState(false);
+
+  return (
+    <li className="space-y-3">
+      <div className="p-5 rounded-2xl bg-[#1b2a4a] border border-[rgba(0,188,212,0.25)]">
+        <div className="flex justify-between items-center mb-2">
+          <span className="text-white font-semibold">{comment.author}</span>
+          <span className="text-xs text-gray-400">{new Date(comment.created_at).toLocaleDateString()}</span>
+        </div>
+        <p className="text-gray-300 whitespace-pre-wrap">{comment.content}</p>
+        {depth < MAX_DEPTH && (
+          <button onClick={() => setReplying(!replying)} className="mt-3 text-sm text-sky-400 hover:text-sky-300">
+            {replying ? 'Cancel' : 'Reply'}
+          </button>
+        )}
+      </div>
+      {replying && (
+        <div className="ml-6">
+          <CommentForm blogId={blogId} parentId={comment.id} onDone={() => { setReplying(false); onPosted(); }} />
+        </div>
+      )}
+      {comment.replies && comment.replies.length > 0 && (
+        <ul className="ml-6 space-y-3 border-l border-[rgba(0,188,212,0.25)] pl-4">
+          {comment.replies.map((reply) => (
+            <CommentItem key={reply.id} comment={reply} blogId={blogId} depth={depth + 1} onPosted={onPosted} />
+          ))}
+        </ul>
+      )}
+    </li>
+  );
+}
+
+// Approved comments of a blog post as a thread, with forms for new
+// comments and replies. New comments are held for moderation.
+export default function Comments({ blogId }: { blogId: number }) {
+  const [comments, setComments] = useState<Comment[]>([]);
+
+  const fetchComments = useCallback(async () => {
+    try {
+      const res = await axios.get(`${getApiBase()}/api/blogs/${blogId}/comments`);
+      setComments((res.data as Comment[]) || []);
+    } catch {
+      setComments([]);
+    }
+  }, [blogId]);
+
+  useEffect(() => {
+    fetchComments();
+  }, [fetchComments]);
+
+  return (
+    <div className="mt-12">
+      <h2 className="text-2xl font-bold text-white mb-6">Comments</h2>
+      {comments.length === 0 ? (
+        <p className="text-gray-400 mb-6">No comments yet. Be the first to share your thoughts.</p>
+      ) : (
+        <ul className="space-y-4 mb-8">
+          {comments.map((comment) => (
+            <CommentItem key={comment.id} comment={comment} blogId={blogId} depth={1} onPosted={fetchComments} />
+          ))}
+        </ul>
+      )}
+      <div className="p-6 rounded-2xl bg-[#1b2a4a] border border-[rgba(0,188,212,0.25)]">
+        <h3 className="text-lg font-semibold text-white mb-4">Leave a comment</h3>
+        <CommentForm blogId={blogId} parentId={0} onDone={fetchComments} />
+      </div>
+    </div>
+  );
+}
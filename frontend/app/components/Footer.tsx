@@ -5,6 +5,7 @@ import Link from 'next/link';
 import axios from 'axios';
 import { FaFacebook, FaTwitter, FaLinkedin, FaInstagram, FaYoutube, FaGithub, FaWeixin, FaWeibo } from 'react-icons/fa';
 import { getApiBase } from '../lib/api';
+import NewsletterForm from './NewsletterForm';
 
 interface SocialLink {
   id: number;
@@ -138,6 +139,9 @@ export default function Footer() {
             >
               Contact Us
             </Link>
+
+            <h4 className="text-sm font-semibold text-[var(--text-heading)] mt-6 mb-3">Newsletter</h4>
+            <NewsletterForm />
           </div>
         </div>
 
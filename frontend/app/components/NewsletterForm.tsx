@@ -0,0 +1,56 @@
+'use client';
+
+import { useState } from 'react';
+import axios from 'axios';
+import { getApiBase } from '../lib/api';
+
+// Newsletter sign-up. The backend mails a confirmation link; the address is
+// only subscribed once that link is opened.
+export default function NewsletterForm() {
+  const [email, setEmail] = useState('');
+  const [website, setWebsite] = useState('');
+  const [status, setStatus] = useState<'idle' | 'sending' | 'sent' | 'error'>('idle');
+  const [error, setError] = useState('');
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setStatus('sending');
+    setError('');
+    try {
+      await axios.post(`${getApiBase()}/api/newsletter/subscribe`, { email, website });
+      setEmail('');
+      setStatus('sent');
+    } catch (err: any) {
+      setStatus('error');
+      setError(err.response?.data?.message || 'Subscription failed. Please try again later.');
+    }
+  };
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-2">
+      <div className="flex gap-2">
+        <input
+          type="email"
+          required
+          maxLength={254}
+          placeholder="Your email"
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          className="flex-1 min-w-0 px-3 py-2 rounded-xl bg-[#10243c] border border-[rgba(0,188,212,0.35)] text-sm text-white placeholder-gray-500 focus:outline-none focus:border-[var(--accent)]"
+        />
+        <button
+          type="submit"
+          disabled={status === 'sending'}
+          className="px-4 py-2 rounded-xl bg-[var(--accent)] text-[#0a192f] text-sm font-semibold disabled:opacity-50"
+        >
+          {status === 'sending' ? '...' : 'Subscribe'}
+        </button>
+      </div>
+      {/* Honeypot: hidden from people, left empty by them */}
+      <input type="text" name="website" tabIndex={-1} autoComplete="off" className="hidden" aria-hidden="true"
+        value={website} onChange={(e) => setWebsite(e.target.value)} />
+      {status === 'sent' && <p className="text-xs text-emerald-400">Please check your inbox to confirm your subscription.</p>}
+      {status === 'error' && <p className="text-xs text-red-400">{error}</p>}
+    </form>
+  );
+}
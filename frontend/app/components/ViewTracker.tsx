@@ -0,0 +1,19 @@
+'use client';
+
+import { useEffect } from 'react';
+import { getApiBase } from '../lib/api';
+
+// Reports one view of a blog post or solution. The backend decides whether it
+// counts (bots, Do Not Track and Global Privacy Control are ignored there).
+export default function ViewTracker({ kind, id }: { kind: 'blog' | 'solution'; id: number }) {
+  useEffect(() => {
+    const url = `${getApiBase()}/api/track`;
+    const body = JSON.stringify({ kind, id, referrer: document.referrer });
+    // sendBeacon posts text/plain, so no CORS preflight is needed.
+    if (!navigator.sendBeacon?.(url, body)) {
+      fetch(url, { method: 'POST', body, keepalive: true }).catch(() => {});
+    }
+  }, [kind, id]);
+
+  return null;
+}
@@ -1,6 +1,6 @@
 'use client';
 
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import axios from 'axios';
 import { getApiBase } from '../lib/api';
 
@@ -9,12 +9,27 @@ export default function ContactPage() {
     name: '',
     email: '',
     subject: '',
-    message: ''
+    message: '',
+    website: ''
   });
+  const [formToken, setFormToken] = useState('');
   const [loading, setLoading] = useState(false);
   const [success, setSuccess] = useState(false);
   const [error, setError] = useState('');
 
+  const loadFormToken = async () => {
+    try {
+      const res = await axios.get(`${getApiBase()}/api/contact/token`);
+      setFormToken(res.data.token);
+    } catch (error) {
+      console.error('Contact token error:', error);
+    }
+  };
+
+  useEffect(() => {
+    loadFormToken();
+  }, []);
+
   const handleChange = (e: React.ChangeEvent<HTMLInputElement | HTMLTextAreaElement>) => {
     setFormData({
       ...formData,
@@ -29,9 +44,10 @@ export default function ContactPage() {
 
     try {
       const baseUrl = getApiBase();
-      await axios.post(`${baseUrl}/api/contact`, formData);
+      await axios.post(`${baseUrl}/api/contact`, { ...formData, form_token: formToken });
       setSuccess(true);
-      setFormData({ name: '', email: '', subject: '', message: '' });
+      setFormData({ name: '', email: '', subject: '', message: '', website: '' });
+      loadFormToken();
     } catch (error) {
       setError('Failed to send message. Please try again later.');
       console.error('Contact form error:', error);
@@ -153,6 +169,18 @@ export default function ContactPage() {
                 ) : null}
 
                 <form onSubmit={handleSubmit} className="space-y-6">
+                  {/* Honeypot: hidden from humans, bots tend to fill it in */}
+                  <div className="hidden" aria-hidden="true">
+                    <input
+                      type="text"
+                      name="website"
+                      value={formData.website}
+                      onChange={handleChange}
+                      tabIndex={-1}
+                      autoComplete="off"
+                    />
+                  </div>
+
                   <div>
                     <label className="block text-sm font-medium text-[var(--text-heading)] mb-2">Name *</label>
                     <input
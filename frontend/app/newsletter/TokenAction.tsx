@@ -0,0 +1,52 @@
+'use client';
+
+import { useEffect, useRef, useState } from 'react';
+import { useSearchParams } from 'next/navigation';
+import Link from 'next/link';
+import axios from 'axios';
+import { getApiBase } from '../lib/api';
+
+const copy = {
+  confirm: {
+    pending: 'Confirming your subscription...',
+    done: 'Your subscription is confirmed. Thanks for subscribing!',
+  },
+  unsubscribe: {
+    pending: 'Unsubscribing...',
+    done: 'You have been unsubscribed and will not receive further emails.',
+  },
+};
+
+// Posts the token from a newsletter email link back to the API. Links only
+// open this page, so mail scanners that prefetch them change nothing.
+export default function TokenAction({ action }: { action: 'confirm' | 'unsubscribe' }) {
+  const token = useSearchParams().get('token') || '';
+  const [status, setStatus] = useState<'pending' | 'done' | 'error'>('pending');
+  const [error, setError] = useState('');
+  const sent = useRef(false);
+
+  useEffect(() => {
+    if (sent.current) return;
+    sent.current = true;
+    if (!token) {
+      setStatus('error');
+      setError('This link is incomplete. Please open it again from the email.');
+      return;
+    }
+    axios.post(`${getApiBase()}/api/newsletter/${action}`, { token })
+      .then(() => setStatus('done'))
+      .catch((err: any) => {
+        setStatus('error');
+        setError(err.response?.data?.message || 'Something went wrong. Please try again later.');
+      });
+  }, [action, token]);
+
+  return (
+    <div className="text-center space-y-6">
+      {status === 'pending' && <p className="text-gray-300">{copy[action].pending}</p>}
+      {status === 'done' && <p className="text-emerald-400 text-lg">{copy[action].done}</p>}
+      {status === 'error' && <p className="text-red-400">{error}</p>}
+      <Link href="/" className="inline-block text-sky-400 hover:text-sky-300">Back to home</Link>
+    </div>
+  );
+}
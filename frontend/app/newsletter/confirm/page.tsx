@@ -0,0 +1,18 @@
+import type { Metadata } from 'next';
+import { Suspense } from 'react';
+import TokenAction from '../TokenAction';
+
+export const metadata: Metadata = { title: 'Confirm Subscription', robots: { index: false } };
+
+export default function ConfirmSubscriptionPage() {
+  return (
+    <div className="min-h-[60vh] flex items-center justify-center bg-[#0d1c34] px-4 py-24">
+      <div className="max-w-lg w-full p-8 rounded-2xl bg-[#1b2a4a] border border-[rgba(0,188,212,0.25)]">
+        <h1 className="text-2xl font-bold text-white text-center mb-6">Confirm Subscription</h1>
+        <Suspense>
+          <TokenAction action="confirm" />
+        </Suspense>
+      </div>
+    </div>
+  );
+}
@@ -15,6 +15,26 @@ interface CarouselItem {
   rotation?: number;
   image_width?: number;
   image_height?: number;
+  cta_label?: string;
+  cta_href?: string; // resolved by the API from a URL or a blog/solution reference
+}
+
+// 轮播图按钮：站内路径用 Link，外部地址在新窗口打开
+function SlideCTA({ slide, className }: { slide: CarouselItem; className: string }) {
+  if (!slide.cta_href) return null;
+  const label = slide.cta_label || 'Learn more';
+  if (slide.cta_href.startsWith('/')) {
+    return (
+      <Link href={slide.cta_href} className={className}>
+        {label}
+      </Link>
+    );
+  }
+  return (
+    <a href={slide.cta_href} target="_blank" rel="noopener noreferrer" className={className}>
+      {label}
+    </a>
+  );
 }
 
 const DEFAULT_SLIDES: CarouselItem[] = [
@@ -71,6 +91,8 @@ export default function Home() {
         rotation: item.rotation ?? 0,
         image_width: item.image_width ?? 0,
         image_height: item.image_height ?? 0,
+        cta_label: item.cta_label,
+        cta_href: item.cta_href,
       }));
 
     const fetchSlides = async () => {
@@ -255,6 +277,10 @@ export default function Home() {
           <div className="mt-6 text-sm text-[var(--text-dark)]">
             {activeHeroSlide.title} — {activeHeroSlide.description}
           </div>
+          <SlideCTA
+            slide={activeHeroSlide}
+            className="inline-block mt-4 text-sm uppercase tracking-wide text-[var(--accent)] hover:underline"
+          />
           <div className="mt-6 flex justify-center space-x-4">
             <button
               type="button"
@@ -422,6 +448,7 @@ export default function Home() {
                           {slide.description}
                         </p>
                       )}
+                      <SlideCTA slide={slide} className="inline-block text-xs uppercase tracking-wide text-[var(--primary-blue)] hover:underline" />
                     </div>
                   </div>
                 ))}
@@ -456,6 +483,7 @@ export default function Home() {
                         {slide.description}
                       </p>
                     )}
+                    <SlideCTA slide={slide} className="inline-block text-xs uppercase tracking-wide text-[var(--primary-blue)] hover:underline" />
                   </div>
                 </div>
               ))}
@@ -1,6 +1,7 @@
 import Link from 'next/link';
 import { Metadata } from 'next';
 import { getApiBase } from '../../lib/api';
+import ViewTracker from '../../components/ViewTracker';
 
 export const revalidate = 300;
 
@@ -90,6 +91,7 @@ export default async function SolutionDetail({ params }: { params: Promise<{ slu
 
   return (
     <div className="min-h-screen bg-[var(--background)] text-[var(--foreground)]">
+      <ViewTracker kind="solution" id={solution.id} />
       {/* Hero Section */}
       <section className="relative py-32 overflow-hidden bg-gradient-to-br from-[#0a192f] via-[#10243c] to-[#0a192f]">
         {/* Background Pattern */}